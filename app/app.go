@@ -8,7 +8,11 @@ import (
 	"runtime"
 
 	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/nenki.ui/app/scheme"
 	"github.com/Seikaijyu/nenki.ui/context"
+	"github.com/Seikaijyu/nenki.ui/widget/font"
+	"github.com/Seikaijyu/nenki.ui/widget/style"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
 
 	gapp "github.com/Seikaijyu/gio/app"
 	glayout "github.com/Seikaijyu/gio/layout"
@@ -45,10 +49,27 @@ const (
 	Maximized
 )
 
+// 主题配置
+type themeConfig struct {
+	// 系统配色偏好提供者
+	provider scheme.Provider
+	// 跟随系统时使用的亮色主题
+	light *theme.Theme
+	// 跟随系统时使用的暗色主题
+	dark *theme.Theme
+	// 停止跟随系统，为nil时表示没有跟随系统
+	stop chan struct{}
+	// 主题改变事件
+	_changed func(*App, *theme.Theme)
+	// 跟随系统配色偏好失败事件
+	_error func(*App, error)
+}
+
 // 程序
 type App struct {
 	window    *gapp.Window   // 窗口
 	uiContext *context.AppUI // UI上下文管理器
+	theme     *themeConfig   // 主题配置
 }
 
 // 主动更新UI
@@ -155,12 +176,146 @@ func (p *App) Orientation(orientation Orientation) *App {
 	return p
 }
 
-// 设置背景颜色
+// 设置应用主题，所有组件都会使用此主题，除非组件手动设置了对应的属性
+//
+// 内置theme.Light()和theme.Dark()两个主题，可以在运行时切换
+func (p *App) Theme(t *theme.Theme) *App {
+	p.Then(func(self *App, root *context.Root) {
+		p.uiContext.Theme(t)
+		if p.theme._changed != nil {
+			p.theme._changed(p, t)
+		}
+	})
+	return p
+}
+
+// 设置跟随系统时使用的亮色和暗色主题，默认为theme.Light()和theme.Dark()
+func (p *App) SystemThemes(light, dark *theme.Theme) *App {
+	p.theme.light = light
+	p.theme.dark = dark
+	return p
+}
+
+// 设置系统配色偏好提供者，默认使用当前平台的实现，需要在FollowSystemTheme之前调用
+func (p *App) SystemThemeProvider(provider scheme.Provider) *App {
+	p.theme.provider = provider
+	return p
+}
+
+// 是否跟随系统的亮色/暗色偏好，跟随时会立即应用系统偏好对应的主题，并在偏好改变时自动切换
+//
+// 无法读取系统偏好时保持当前主题不变，也不会开始监听；监听时读取失败会自动重试，
+// 持续失败时停止跟随系统并触发OnSystemThemeError设置的事件
+func (p *App) FollowSystemTheme(follow bool) *App {
+	if p.theme.stop != nil {
+		close(p.theme.stop)
+		p.theme.stop = nil
+	}
+	if !follow {
+		return p
+	}
+	value, err := p.theme.provider.Read()
+	if err != nil {
+		return p
+	}
+	p.applyScheme(value)
+	stop := make(chan struct{})
+	p.theme.stop = stop
+	go func() {
+		err := p.theme.provider.Watch(stop, func(value scheme.Scheme) {
+			select {
+			case <-stop:
+			default:
+				p.applyScheme(value)
+			}
+		})
+		if err == nil {
+			return
+		}
+		// 持续失败，停止跟随系统，在UI协程中通知
+		p.Then(func(self *App, root *context.Root) {
+			if p.theme.stop != stop {
+				return
+			}
+			p.theme.stop = nil
+			if p.theme._error != nil {
+				p.theme._error(p, err)
+			}
+		})
+	}()
+	return p
+}
+
+// 主题改变事件，手动切换主题和跟随系统切换主题时都会触发
+func (p *App) OnThemeChanged(fn func(self *App, t *theme.Theme)) *App {
+	p.theme._changed = fn
+	return p
+}
+
+// 跟随系统配色偏好失败事件，系统偏好持续无法读取时触发，此时已经停止跟随系统并保持当前主题不变
+//
+// 只是通知，不会交给OnUIContextError设置的错误处理函数，可以在事件中再次调用FollowSystemTheme重新跟随
+func (p *App) OnSystemThemeError(fn func(self *App, err error)) *App {
+	p.theme._error = fn
+	return p
+}
+
+// 应用系统配色偏好对应的主题，没有偏好时使用亮色主题
+func (p *App) applyScheme(value scheme.Scheme) {
+	if value == scheme.Dark {
+		p.Theme(p.theme.dark)
+	} else {
+		p.Theme(p.theme.light)
+	}
+}
+
+// 获取当前主题
+func (p *App) GetTheme() *theme.Theme {
+	return theme.Current()
+}
+
+// 设置背景颜色，设置后不再使用主题的背景颜色
 func (p *App) Background(r, g, b, a uint8) *App {
 	p.uiContext.Background(r, g, b, a)
 	return p
 }
 
+// 设置应用默认字体族，没有设置字体族的组件都会使用此字体族
+//
+// 字体需要先通过font包注册
+func (p *App) DefaultFont(family string) *App {
+	p.Then(func(self *App, root *context.Root) {
+		font.DefaultFamily(family)
+	})
+	return p
+}
+
+// 设置样式表，可以在运行时替换，替换后立即重新渲染
+//
+// 替换或者传入nil时，原来的样式表设置的属性会恢复到设置之前的值
+func (p *App) StyleSheet(sheet *style.StyleSheet) *App {
+	p.Then(func(self *App, root *context.Root) {
+		p.uiContext.StyleSheet(sheet)
+	})
+	return p
+}
+
+// 写入剪贴板，可以在任意协程中调用
+//
+// 当前版本的gio只支持纯文本，其它MIME类型暂不支持
+func (p *App) ClipboardWrite(text string) *App {
+	p.uiContext.ClipboardWrite(text)
+	return p
+}
+
+// 读取剪贴板，可以在任意协程中调用，读取完成后在UI协程中调用fn
+//
+// 当前版本的gio只支持纯文本，其它MIME类型暂不支持
+func (p *App) ClipboardRead(fn func(text string)) *App {
+	p.uiContext.ClipboardRead(fn)
+	return p
+}
+
 // 设置窗口模式
 func (p *App) WindowMode(mode WindowMode) *App {
 	p.window.Option(mode.Option())
@@ -194,6 +349,11 @@ func NewApp(title string) *App {
 	var application = &App{
 		window:    window,
 		uiContext: context.NewAppUI(window),
+		theme: &themeConfig{
+			provider: scheme.Default(),
+			light:    theme.Light(),
+			dark:     theme.Dark(),
+		},
 	}
 	application.Title(title) // 设置标题
 	// 提前调用一次以获取HWND和更快的加载
@@ -0,0 +1,94 @@
+// 系统配色偏好
+// 读取并监听操作系统的亮色/暗色偏好，不同平台的实现通过Provider接口统一，测试时可以替换为自定义实现
+package scheme
+
+import "time"
+
+// 系统配色偏好，值与freedesktop设置门户的color-scheme一致
+type Scheme uint8
+
+const (
+	// 没有偏好
+	NoPreference Scheme = iota
+	// 偏好暗色
+	Dark
+	// 偏好亮色
+	Light
+)
+
+// 系统配色偏好提供者
+type Provider interface {
+	// 读取当前的系统配色偏好
+	Read() (Scheme, error)
+	// 监听系统配色偏好的变化，偏好改变时调用fn，直到stop被关闭
+	//
+	// 此函数会阻塞，偶尔的读取失败应该在内部重试，只有持续失败、无法继续监听时才返回错误
+	Watch(stop <-chan struct{}, fn func(Scheme)) error
+}
+
+// 获取当前平台默认的提供者
+func Default() Provider {
+	return platformProvider()
+}
+
+const (
+	// 连续读取失败多少次后认为无法继续监听
+	maxFailures = 5
+	// 读取失败后重试的最长间隔
+	maxBackoff = time.Minute
+)
+
+// 通过定时读取实现监听，用于没有变化通知的平台
+//
+// 读取失败时按逐渐加倍的间隔重试，连续失败maxFailures次后才返回错误
+type polling struct {
+	read     func() (Scheme, error)
+	interval time.Duration
+}
+
+func (p *polling) Read() (Scheme, error) {
+	return p.read()
+}
+
+func (p *polling) Watch(stop <-chan struct{}, fn func(Scheme)) error {
+	var last Scheme
+	// 第一次读取只记录当前的偏好，不触发fn
+	first := true
+	failures := 0
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-timer.C:
+		}
+		current, err := p.read()
+		if err != nil {
+			failures++
+			if failures >= maxFailures {
+				return err
+			}
+			timer.Reset(backoff(p.interval, failures))
+			continue
+		}
+		failures = 0
+		if !first && current != last {
+			fn(current)
+		}
+		last, first = current, false
+		timer.Reset(p.interval)
+	}
+}
+
+// 连续失败failures次后的重试间隔，每次失败间隔加倍，不超过maxBackoff
+func backoff(interval time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
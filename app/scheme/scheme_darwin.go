@@ -0,0 +1,26 @@
+package scheme
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// 读取系统外观设置，亮色模式下AppleInterfaceStyle不存在
+func readDefaults() (Scheme, error) {
+	output, err := exec.Command("defaults", "read", "-g", "AppleInterfaceStyle").Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return Light, nil
+		}
+		return NoPreference, err
+	}
+	if strings.EqualFold(strings.TrimSpace(string(output)), "dark") {
+		return Dark, nil
+	}
+	return Light, nil
+}
+
+func platformProvider() Provider {
+	return &polling{read: readDefaults, interval: 2 * time.Second}
+}
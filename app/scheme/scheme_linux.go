@@ -0,0 +1,97 @@
+package scheme
+
+import (
+	"bufio"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 设置门户的D-Bus地址
+	portalDest = "org.freedesktop.portal.Desktop"
+	portalPath = "/org/freedesktop/portal/desktop"
+	// 配色偏好所在的命名空间和键
+	appearanceNamespace = "org.freedesktop.appearance"
+	colorSchemeKey      = "color-scheme"
+)
+
+// 匹配gdbus输出中的配色偏好值
+var uint32Pattern = regexp.MustCompile(`uint32 (\d+)`)
+
+// 通过gdbus访问freedesktop设置门户
+//
+// 使用glib自带的gdbus命令而不是D-Bus库，避免为这一个设置引入额外的依赖，gdbus的输出格式多年没有变化。
+// 没有安装gdbus或者没有设置门户时Read返回错误，App不会开始监听；
+// 监听的gdbus进程无法启动或者意外退出时改为定时读取，读取失败时按逐渐加倍的间隔重试
+type portal struct{}
+
+func platformProvider() Provider {
+	return &portal{}
+}
+
+// 解析gdbus输出中的配色偏好
+func parseScheme(output string) (Scheme, error) {
+	match := uint32Pattern.FindStringSubmatch(output)
+	if match == nil {
+		return NoPreference, errors.New("无法解析配色偏好: " + strings.TrimSpace(output))
+	}
+	value, err := strconv.Atoi(match[1])
+	if err != nil || value > int(Light) {
+		return NoPreference, nil
+	}
+	return Scheme(value), nil
+}
+
+func (p *portal) Read() (Scheme, error) {
+	output, err := exec.Command("gdbus", "call", "--session",
+		"--dest", portalDest,
+		"--object-path", portalPath,
+		"--method", "org.freedesktop.portal.Settings.Read",
+		appearanceNamespace, colorSchemeKey).Output()
+	if err != nil {
+		return NoPreference, err
+	}
+	return parseScheme(string(output))
+}
+
+func (p *portal) Watch(stop <-chan struct{}, fn func(Scheme)) error {
+	// 定时读取，用于gdbus monitor无法使用时
+	poll := &polling{read: p.Read, interval: 2 * time.Second}
+	cmd := exec.Command("gdbus", "monitor", "--session",
+		"--dest", portalDest,
+		"--object-path", portalPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return poll.Watch(stop, fn)
+	}
+	if err := cmd.Start(); err != nil {
+		return poll.Watch(stop, fn)
+	}
+	go func() {
+		<-stop
+		cmd.Process.Kill()
+	}()
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// 例如：org.freedesktop.portal.Settings.SettingChanged ('org.freedesktop.appearance', 'color-scheme', <uint32 1>)
+		if !strings.Contains(line, "SettingChanged") || !strings.Contains(line, appearanceNamespace) || !strings.Contains(line, colorSchemeKey) {
+			continue
+		}
+		if value, err := parseScheme(line); err == nil {
+			fn(value)
+		}
+	}
+	cmd.Wait()
+	select {
+	case <-stop:
+		return nil
+	default:
+	}
+	// gdbus monitor意外退出，改为定时读取
+	return poll.Watch(stop, fn)
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !windows && !darwin
+
+package scheme
+
+import "errors"
+
+// 不支持的平台
+type unsupported struct{}
+
+func platformProvider() Provider {
+	return unsupported{}
+}
+
+func (unsupported) Read() (Scheme, error) {
+	return NoPreference, errors.New("当前平台不支持读取系统配色偏好")
+}
+
+func (unsupported) Watch(stop <-chan struct{}, fn func(Scheme)) error {
+	return errors.New("当前平台不支持监听系统配色偏好")
+}
@@ -0,0 +1,28 @@
+package scheme
+
+import (
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// 读取注册表中的应用配色偏好
+func readRegistry() (Scheme, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		return NoPreference, err
+	}
+	defer key.Close()
+	value, _, err := key.GetIntegerValue("AppsUseLightTheme")
+	if err != nil {
+		return NoPreference, err
+	}
+	if value == 0 {
+		return Dark, nil
+	}
+	return Light, nil
+}
+
+func platformProvider() Provider {
+	return &polling{read: readRegistry, interval: 2 * time.Second}
+}
@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"image/color"
 	"os"
+	"sync"
 
 	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/font"
+	"github.com/Seikaijyu/nenki.ui/widget/style"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
 
 	gio "github.com/Seikaijyu/gio/app"
+	"github.com/Seikaijyu/gio/io/clipboard"
 	"github.com/Seikaijyu/gio/io/system"
 	glayout "github.com/Seikaijyu/gio/layout"
 	"github.com/Seikaijyu/gio/op"
@@ -24,6 +29,17 @@ type Root = widget.ContainerLayout
 type contextConfig struct {
 	// 背景颜色
 	background *color.NRGBA
+	// 样式表
+	styleSheet *style.StyleSheet
+	// 被替换的样式表，在下一次渲染时撤销它们设置的属性
+	retiredSheets []*style.StyleSheet
+}
+
+// 剪贴板读取请求
+type clipboardReaders struct {
+	mutex sync.Mutex
+	// 等待剪贴板内容的回调
+	callbacks []func(string)
 }
 
 // UI上下文管理器
@@ -36,6 +52,7 @@ type AppUI struct {
 	updateHandler       func(glayout.Context)         // UI每次更新时执行的函数
 	singleUpdateHandler *Queue[func(glayout.Context)] // 单次执行的UI函数
 	graphContext        glayout.Context               // 渲染上下文
+	clipboard           *clipboardReaders             // 剪贴板读取请求
 }
 
 // UI循环
@@ -52,21 +69,40 @@ func (p *AppUI) loop() error {
 			case system.DestroyEvent:
 				return e.Err
 			case system.FrameEvent:
+				// 渲染这一帧之后的重绘请求需要再次唤醒窗口
+				widget.BeginFrame()
 				p.graphContext = glayout.NewContext(&ops, e)
+				// 注册了新字体时重新创建文字排版器
+				font.Refresh()
+				p.deliverClipboard()
 				var stack = clip.Rect{Max: e.Size}.Push(p.graphContext.Ops)
-				// 设置背景颜色
+				// 设置背景颜色，没有设置时使用主题的背景颜色
+				background := theme.Current().Background
 				if p.config.background != nil {
-					paint.ColorOp{Color: *p.config.background}.Add(p.graphContext.Ops)
-					paint.PaintOp{}.Add(p.graphContext.Ops)
+					background = *p.config.background
 				}
+				paint.ColorOp{Color: background}.Add(p.graphContext.Ops)
+				paint.PaintOp{}.Add(p.graphContext.Ops)
 				p.updateHandler(p.graphContext)
 				// 执行队列中的UI函数，如果有的话
 				if fn, ok := p.singleUpdateHandler.Dequeue(); ok {
 					fn(p.graphContext)
 					p.window.Invalidate()
 				}
+				// 撤销被替换的样式表设置的属性，并在渲染前应用样式表，避免组件先以没有样式的外观显示一帧
+				for _, sheet := range p.config.retiredSheets {
+					sheet.Revert()
+				}
+				p.config.retiredSheets = nil
+				if p.config.styleSheet != nil {
+					p.config.styleSheet.Apply(p.uiWidget)
+				}
 				// 渲染UI
 				p.uiWidget.Layout(p.graphContext)
+				// 组件状态在渲染时才会更新，状态改变后需要再渲染一帧以应用对应的样式
+				if p.config.styleSheet != nil && p.config.styleSheet.Outdated(p.uiWidget) {
+					p.window.Invalidate()
+				}
 				stack.Pop()
 				e.Frame(p.graphContext.Ops)
 
@@ -86,6 +122,30 @@ func (p *AppUI) Background(r, g, b, a uint8) *AppUI {
 	return p
 }
 
+// 切换主题，所有组件在下一次渲染时应用新主题
+func (p *AppUI) Theme(t *theme.Theme) *AppUI {
+	theme.Set(t)
+	p.window.Invalidate()
+	return p
+}
+
+// 设置样式表，为nil时不再应用样式表
+//
+// 原来的样式表设置的属性会在下一次渲染时恢复
+func (p *AppUI) StyleSheet(sheet *style.StyleSheet) *AppUI {
+	if old := p.config.styleSheet; old != nil && old != sheet {
+		old.OnChange(nil)
+		p.config.retiredSheets = append(p.config.retiredSheets, old)
+	}
+	p.config.styleSheet = sheet
+	if sheet != nil {
+		// 样式表修改后立即重新渲染
+		sheet.OnChange(p.window.Invalidate)
+	}
+	p.window.Invalidate()
+	return p
+}
+
 // 自定义UI循环
 func (p *AppUI) OnUILoop(fn func(glayout.Context)) {
 	p.updateHandler = fn
@@ -97,6 +157,44 @@ func (p *AppUI) OnUIContextError(fn func(err error)) *AppUI {
 	return p
 }
 
+// 写入剪贴板，可以在任意协程中调用
+//
+// 当前只支持纯文本
+func (p *AppUI) ClipboardWrite(text string) {
+	p.AppendSingleUIHandler(func(gtx glayout.Context) {
+		clipboard.WriteOp{Text: text}.Add(gtx.Ops)
+	})
+}
+
+// 读取剪贴板，可以在任意协程中调用，读取到内容后在UI协程中调用fn
+//
+// 当前只支持纯文本
+func (p *AppUI) ClipboardRead(fn func(text string)) {
+	p.clipboard.mutex.Lock()
+	p.clipboard.callbacks = append(p.clipboard.callbacks, fn)
+	p.clipboard.mutex.Unlock()
+	p.AppendSingleUIHandler(func(gtx glayout.Context) {
+		clipboard.ReadOp{Tag: p.clipboard}.Add(gtx.Ops)
+	})
+}
+
+// 将剪贴板内容交给等待中的回调
+func (p *AppUI) deliverClipboard() {
+	for _, e := range p.graphContext.Events(p.clipboard) {
+		e, ok := e.(clipboard.Event)
+		if !ok {
+			continue
+		}
+		p.clipboard.mutex.Lock()
+		callbacks := p.clipboard.callbacks
+		p.clipboard.callbacks = nil
+		p.clipboard.mutex.Unlock()
+		for _, fn := range callbacks {
+			fn(e.Text)
+		}
+	}
+}
+
 func (p *AppUI) AppendSingleUIHandler(fn func(glayout.Context)) {
 	p.singleUpdateHandler.Enqueue(fn)
 	p.window.Invalidate()
@@ -123,8 +221,11 @@ func NewAppUI(window *gio.Window) *AppUI {
 		updateHandler:       func(glayout.Context) {},
 		singleUpdateHandler: &Queue[func(glayout.Context)]{},
 		config:              &contextConfig{},
+		clipboard:           &clipboardReaders{},
 	}
 	uiContext.uiWidget.OnDestroy(func() {})
+	// 后台goroutine修改组件数据后唤醒窗口
+	widget.OnRedrawRequest(window.Invalidate)
 	go func() {
 		// 进行UI循环
 		if err := uiContext.loop(); err != nil {
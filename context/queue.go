@@ -1,18 +1,25 @@
 package context
 
-// Queue 表示任意类型 T 的队列。
+import "sync"
+
+// Queue 表示任意类型 T 的队列，可以在多个协程中同时使用。
 type Queue[T any] struct {
-	data []T
+	mutex sync.Mutex
+	data  []T
 }
 
 // Enqueue 将元素添加到队列的末尾。
 func (q *Queue[T]) Enqueue(v T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 	q.data = append(q.data, v)
 }
 
 // Dequeue 从队列中移除并返回第一个元素。
 // 如果队列为空，则返回 false。
 func (q *Queue[T]) Dequeue() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 	if len(q.data) == 0 {
 		var zero T // 创建类型 T 的零值
 		return zero, false
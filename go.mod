@@ -5,6 +5,9 @@ go 1.19
 require (
 	github.com/Seikaijyu/gio v0.0.1
 	github.com/pkg/browser v0.0.0-20240102092130-5ac0b6a4141c
+	github.com/yuin/goldmark v1.7.8
+	golang.org/x/image v0.7.0
+	golang.org/x/sys v0.12.0
 )
 
 require (
@@ -13,7 +16,5 @@ require (
 	github.com/go-text/typesetting v0.0.0-20230803102845-24e03d8b5372 // indirect
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9 // indirect
 	golang.org/x/exp/shiny v0.0.0-20220906200021-fcb1a314c389 // indirect
-	golang.org/x/image v0.7.0 // indirect
-	golang.org/x/sys v0.12.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 )
@@ -0,0 +1,415 @@
+package utils
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 颜色，各分量为0-255，透明度不预乘
+//
+// 使用RGBA8获取分量后可以直接传给组件的(r, g, b, a uint8)设置函数，例如
+//
+//	button.Background(utils.MustParseColor("tomato").Lighten(0.1).RGBA8())
+type Color struct {
+	R, G, B, A uint8
+}
+
+// 校验接口是否实现
+var _ color.Color = Color{}
+
+// 使用RGBA分量创建颜色
+func RGBA(r, g, b, a uint8) Color {
+	return Color{R: r, G: g, B: b, A: a}
+}
+
+// 使用image/color中的颜色创建颜色
+func FromColor(c color.Color) Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return Color{R: n.R, G: n.G, B: n.B, A: n.A}
+}
+
+// 获取RGBA分量，用于组件的(r, g, b, a uint8)设置函数
+func (c Color) RGBA8() (r, g, b, a uint8) {
+	return c.R, c.G, c.B, c.A
+}
+
+// 实现color.Color接口，返回预乘透明度的16位分量
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return c.NRGBA().RGBA()
+}
+
+// 转换为color.NRGBA
+func (c Color) NRGBA() color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+// 16进制字符串，不透明时为#rrggbb，否则为#rrggbbaa
+func (c Color) Hex() string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// CSS的rgba()形式
+func (c Color) String() string {
+	return fmt.Sprintf("rgba(%d, %d, %d, %s)", c.R, c.G, c.B, strconv.FormatFloat(float64(c.A)/0xff, 'f', -1, 32))
+}
+
+// 修改透明度
+func (c Color) WithAlpha(a uint8) Color {
+	c.A = a
+	return c
+}
+
+// 提高HSL亮度，amount为0-1
+func (c Color) Lighten(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, s, clamp(l+amount, 0, 1), c.A)
+}
+
+// 降低HSL亮度，amount为0-1
+func (c Color) Darken(amount float64) Color {
+	return c.Lighten(-amount)
+}
+
+// 提高HSL饱和度，amount为0-1，为负数时降低
+func (c Color) Saturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, clamp(s+amount, 0, 1), l, c.A)
+}
+
+// 与另一个颜色混合，weight为另一个颜色所占的比例，0时为自身，1时为另一个颜色
+func (c Color) Mix(other Color, weight float64) Color {
+	weight = clamp(weight, 0, 1)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*(1-weight) + float64(b)*weight))
+	}
+	return Color{R: mix(c.R, other.R), G: mix(c.G, other.G), B: mix(c.B, other.B), A: mix(c.A, other.A)}
+}
+
+// 相对亮度，按照WCAG 2的定义，范围为0-1，忽略透明度
+func (c Color) Luminance() float64 {
+	linear := func(v uint8) float64 {
+		f := float64(v) / 0xff
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// 与另一个颜色的对比度，按照WCAG 2的定义，范围为1-21，忽略透明度
+//
+// 正文文字通常需要至少4.5，大号文字需要至少3
+func (c Color) ContrastRatio(other Color) float64 {
+	l1, l2 := c.Luminance(), other.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// 转换为HSL，h为0-360，s和l为0-1
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	d := max - min
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	return hue(r, g, b, max, d), s, l
+}
+
+// 转换为HSV，h为0-360，s和v为0-1
+func (c Color) HSV() (h, s, v float64) {
+	r, g, b := float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	d := max - min
+	if max > 0 {
+		s = d / max
+	}
+	if d == 0 {
+		return 0, s, max
+	}
+	return hue(r, g, b, max, d), s, max
+}
+
+// 使用HSL创建不透明的颜色，h为0-360，s和l为0-1
+func HSL(h, s, l float64) Color {
+	return HSLA(h, s, l, 0xff)
+}
+
+// 使用HSL和透明度创建颜色，h为0-360，s和l为0-1
+func HSLA(h, s, l float64, a uint8) Color {
+	s, l = clamp(s, 0, 1), clamp(l, 0, 1)
+	c := (1 - math.Abs(2*l-1)) * s
+	return fromChroma(h, c, l-c/2, a)
+}
+
+// 使用HSV创建不透明的颜色，h为0-360，s和v为0-1
+func HSV(h, s, v float64) Color {
+	return HSVA(h, s, v, 0xff)
+}
+
+// 使用HSV和透明度创建颜色，h为0-360，s和v为0-1
+func HSVA(h, s, v float64, a uint8) Color {
+	s, v = clamp(s, 0, 1), clamp(v, 0, 1)
+	c := v * s
+	return fromChroma(h, c, v-c, a)
+}
+
+// 根据色相、色度和明度偏移计算RGB
+func fromChroma(h, c, m float64, a uint8) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	h /= 60
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return Color{R: unit8(r + m), G: unit8(g + m), B: unit8(b + m), A: a}
+}
+
+// 根据RGB计算色相
+func hue(r, g, b, max, d float64) float64 {
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// 解析16进制颜色，支持#rgb、#rgba、#rrggbb和#rrggbbaa，#可以省略
+func ParseHex(hex string) (Color, error) {
+	digits := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	switch len(digits) {
+	case 3, 4:
+		// 每一位重复一次，例如f80等同于ff8800
+		expanded := make([]byte, 0, 8)
+		for i := 0; i < len(digits); i++ {
+			expanded = append(expanded, digits[i], digits[i])
+		}
+		digits = string(expanded)
+	case 6, 8:
+	default:
+		return Color{}, fmt.Errorf("无效的16进制颜色%q，长度应为3、4、6或8位", hex)
+	}
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("无效的16进制颜色%q，包含非16进制字符", hex)
+	}
+	return Color{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
+
+// 解析颜色字符串，支持以下格式，不区分大小写
+//
+//   - 16进制：#rgb、#rgba、#rrggbb、#rrggbbaa
+//   - CSS函数：rgb()、rgba()、hsl()、hsla()，参数可以用逗号或空格分隔，透明度可以写在/后面
+//   - CSS颜色名称：例如red、tomato、transparent
+func ParseColor(s string) (Color, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if strings.HasPrefix(value, "#") {
+		return ParseHex(value)
+	}
+	if open := strings.IndexByte(value, '('); open >= 0 {
+		if !strings.HasSuffix(value, ")") {
+			return Color{}, fmt.Errorf("无效的颜色%q，缺少右括号", s)
+		}
+		name := strings.TrimSpace(value[:open])
+		c, err := parseFunction(name, value[open+1:len(value)-1])
+		if err != nil {
+			return Color{}, fmt.Errorf("无效的颜色%q，%s", s, err.Error())
+		}
+		return c, nil
+	}
+	if c, ok := namedColors[value]; ok {
+		return c, nil
+	}
+	return Color{}, fmt.Errorf("无效的颜色%q", s)
+}
+
+// 解析颜色字符串，失败时panic，用于常量颜色
+func MustParseColor(s string) Color {
+	c, err := ParseColor(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// 解析rgb()、rgba()、hsl()和hsla()
+func parseFunction(name, body string) (Color, error) {
+	args, err := splitArgs(body)
+	if err != nil {
+		return Color{}, err
+	}
+	if len(args) != 3 && len(args) != 4 {
+		return Color{}, fmt.Errorf("%s()需要3个或4个参数，实际为%d个", name, len(args))
+	}
+	a := uint8(0xff)
+	if len(args) == 4 {
+		alpha, err := parseAlpha(args[3])
+		if err != nil {
+			return Color{}, err
+		}
+		a = alpha
+	}
+	switch name {
+	case "rgb", "rgba":
+		var channels [3]uint8
+		for i := range channels {
+			v, err := parseChannel(args[i])
+			if err != nil {
+				return Color{}, err
+			}
+			channels[i] = v
+		}
+		return Color{R: channels[0], G: channels[1], B: channels[2], A: a}, nil
+	case "hsl", "hsla":
+		h, err := parseHue(args[0])
+		if err != nil {
+			return Color{}, err
+		}
+		s, err := parsePercent(args[1])
+		if err != nil {
+			return Color{}, err
+		}
+		l, err := parsePercent(args[2])
+		if err != nil {
+			return Color{}, err
+		}
+		return HSLA(h, s, l, a), nil
+	}
+	return Color{}, fmt.Errorf("不支持的函数%s()", name)
+}
+
+// 拆分函数参数，支持rgb(1, 2, 3, 0.5)和rgb(1 2 3 / 0.5)两种写法
+func splitArgs(body string) ([]string, error) {
+	var alpha string
+	if i := strings.IndexByte(body, '/'); i >= 0 {
+		alpha = strings.TrimSpace(body[i+1:])
+		body = body[:i]
+		if alpha == "" {
+			return nil, fmt.Errorf("/后缺少透明度")
+		}
+	}
+	var args []string
+	if strings.Contains(body, ",") {
+		for _, arg := range strings.Split(body, ",") {
+			arg = strings.TrimSpace(arg)
+			if arg == "" {
+				return nil, fmt.Errorf("参数不能为空")
+			}
+			args = append(args, arg)
+		}
+	} else {
+		args = strings.Fields(body)
+	}
+	if alpha != "" {
+		args = append(args, alpha)
+	}
+	return args, nil
+}
+
+// 解析RGB分量，支持0-255的数字和百分比
+func parseChannel(arg string) (uint8, error) {
+	if strings.HasSuffix(arg, "%") {
+		v, err := parsePercent(arg)
+		if err != nil {
+			return 0, err
+		}
+		return unit8(v), nil
+	}
+	v, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的颜色分量%q", arg)
+	}
+	return uint8(math.Round(clamp(v, 0, 0xff))), nil
+}
+
+// 解析透明度，支持0-1的数字和百分比
+func parseAlpha(arg string) (uint8, error) {
+	if strings.HasSuffix(arg, "%") {
+		v, err := parsePercent(arg)
+		if err != nil {
+			return 0, err
+		}
+		return unit8(v), nil
+	}
+	v, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的透明度%q", arg)
+	}
+	return unit8(v), nil
+}
+
+// 解析百分比，返回0-1，没有%时按百分比处理
+func parsePercent(arg string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的百分比%q", arg)
+	}
+	return clamp(v/100, 0, 1), nil
+}
+
+// 解析色相，支持deg、rad、grad和turn单位，没有单位时为角度
+func parseHue(arg string) (float64, error) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / math.Pi}, {"turn", 360}}
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(arg, u.suffix) {
+			arg, scale = strings.TrimSuffix(arg, u.suffix), u.scale
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的色相%q", arg)
+	}
+	return v * scale, nil
+}
+
+// 将0-1的值转换为0-255
+func unit8(v float64) uint8 {
+	return uint8(math.Round(clamp(v, 0, 1) * 0xff))
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
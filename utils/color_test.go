@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		text string
+		want Color
+		ok   bool
+	}{
+		{"#f80", RGBA(0xff, 0x88, 0x00, 0xff), true},
+		{"#f808", RGBA(0xff, 0x88, 0x00, 0x88), true},
+		{"#3F51B5", RGBA(0x3f, 0x51, 0xb5, 0xff), true},
+		{" #3f51b580 ", RGBA(0x3f, 0x51, 0xb5, 0x80), true},
+		{"rgb(255, 0, 0)", RGBA(0xff, 0, 0, 0xff), true},
+		{"RGBA(255, 0, 0, 0.5)", RGBA(0xff, 0, 0, 0x80), true},
+		{"rgb(255 128 0 / 50%)", RGBA(0xff, 0x80, 0, 0x80), true},
+		{"rgb(100%, 0%, 50%)", RGBA(0xff, 0, 0x80, 0xff), true},
+		{"rgb(300, -5, 0)", RGBA(0xff, 0, 0, 0xff), true},
+		{"hsl(120, 100%, 50%)", RGBA(0, 0xff, 0, 0xff), true},
+		{"hsl(0.5turn 100% 50%)", RGBA(0, 0xff, 0xff, 0xff), true},
+		{"hsla(240deg, 100%, 50%, 0.25)", RGBA(0, 0, 0xff, 0x40), true},
+		{"red", RGBA(0xff, 0, 0, 0xff), true},
+		{"RebeccaPurple", RGBA(0x66, 0x33, 0x99, 0xff), true},
+		{"transparent", Color{}, true},
+		{"", Color{}, false},
+		{"#12", Color{}, false},
+		{"#ggg", Color{}, false},
+		{"rgb(1, 2)", Color{}, false},
+		{"rgb(1, 2, 3", Color{}, false},
+		{"rgb(1, , 3)", Color{}, false},
+		{"rgb(1 2 3 /)", Color{}, false},
+		{"rgb(a, 2, 3)", Color{}, false},
+		{"cmyk(1, 2, 3, 4)", Color{}, false},
+		{"notacolor", Color{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.text)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, %v, want %v, ok %v", tt.text, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHSLAndHSV(t *testing.T) {
+	tests := []struct {
+		color   Color
+		h, s, l float64
+		v, sv   float64
+	}{
+		{RGBA(0xff, 0, 0, 0xff), 0, 1, 0.5, 1, 1},
+		{RGBA(0, 0xff, 0, 0xff), 120, 1, 0.5, 1, 1},
+		{RGBA(0, 0, 0xff, 0xff), 240, 1, 0.5, 1, 1},
+		{RGBA(0xff, 0, 0xff, 0xff), 300, 1, 0.5, 1, 1},
+		{RGBA(0xff, 0xff, 0xff, 0xff), 0, 0, 1, 1, 0},
+		{RGBA(0, 0, 0, 0xff), 0, 0, 0, 0, 0},
+		{RGBA(0x80, 0x80, 0x80, 0xff), 0, 0, 0x80 / 255.0, 0x80 / 255.0, 0},
+		{RGBA(0xff, 0x80, 0, 0xff), 30.12, 1, 0.5, 1, 1},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.01 }
+	for _, tt := range tests {
+		h, s, l := tt.color.HSL()
+		if !near(h, tt.h) || !near(s, tt.s) || !near(l, tt.l) {
+			t.Errorf("%v.HSL() = %.2f, %.2f, %.2f, want %.2f, %.2f, %.2f", tt.color, h, s, l, tt.h, tt.s, tt.l)
+		}
+		if got := HSLA(h, s, l, tt.color.A); got != tt.color {
+			t.Errorf("HSLA(%v.HSL()) = %v", tt.color, got)
+		}
+		h, s, v := tt.color.HSV()
+		if !near(h, tt.h) || !near(s, tt.sv) || !near(v, tt.v) {
+			t.Errorf("%v.HSV() = %.2f, %.2f, %.2f, want %.2f, %.2f, %.2f", tt.color, h, s, v, tt.h, tt.sv, tt.v)
+		}
+		if got := HSVA(h, s, v, tt.color.A); got != tt.color {
+			t.Errorf("HSVA(%v.HSV()) = %v", tt.color, got)
+		}
+	}
+	// 色相超出范围时取模，饱和度和亮度限制在0到1
+	if got := HSL(-240, 2, 0.5); got != RGBA(0, 0xff, 0, 0xff) {
+		t.Errorf("HSL(-240, 2, 0.5) = %v", got)
+	}
+	if got := HSV(480, 1, 1); got != RGBA(0, 0xff, 0, 0xff) {
+		t.Errorf("HSV(480, 1, 1) = %v", got)
+	}
+}
+
+// 转换为HSL或HSV再转换回来时颜色不变
+func TestHSLAndHSVRoundTrip(t *testing.T) {
+	for r := 0; r <= 0xff; r += 15 {
+		for g := 0; g <= 0xff; g += 15 {
+			for b := 0; b <= 0xff; b += 15 {
+				c := RGBA(uint8(r), uint8(g), uint8(b), 0xff)
+				if got := HSL(c.HSL()); got != c {
+					t.Fatalf("HSL(%v.HSL()) = %v", c, got)
+				}
+				if got := HSV(c.HSV()); got != c {
+					t.Fatalf("HSV(%v.HSV()) = %v", c, got)
+				}
+			}
+		}
+	}
+}
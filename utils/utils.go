@@ -9,6 +9,8 @@ import (
 // 根据16进制字符串将其转换为RGBA值
 // 如果字符串有任何的16进制错误则返回255,255,255,255
 // 否则根据情况隐式处理，即：如果字符串不足8位则补全，填充完全使用f，如果字符串超过8位则截断
+//
+// 需要知道解析是否成功或者需要支持#rgba时使用ParseHex或ParseColor
 func HexToRGBA(hex string) (uint8, uint8, uint8, uint8) {
 	r, g, b, a := uint8(0), uint8(0), uint8(0), uint8(255)
 	hex = strings.TrimPrefix(hex, "#")
@@ -16,7 +18,7 @@ func HexToRGBA(hex string) (uint8, uint8, uint8, uint8) {
 
 	// 补全位数
 	if length == 3 {
-		hex = fmt.Sprintf("%s%s%s%s%s%s", string(hex[0]), string(hex[0]), string(hex[1]), string(hex[1]), string(hex[2]), string(hex[2]))
+		hex = fmt.Sprintf("%s%s%s%s%s%sff", string(hex[0]), string(hex[0]), string(hex[1]), string(hex[1]), string(hex[2]), string(hex[2]))
 	} else if length < 8 {
 		hex = fmt.Sprintf("%-8s", hex)
 		hex = strings.Replace(hex, " ", "f", -1)
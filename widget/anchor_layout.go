@@ -21,6 +21,8 @@ type anchorLayoutConfig struct {
 
 // 锚定布局
 type AnchorLayout struct {
+	// 组件标识
+	identity
 	// 配置
 	config *anchorLayoutConfig
 	// 内边距
@@ -39,6 +41,18 @@ func (p *AnchorLayout) Then(fn func(self *AnchorLayout)) *AnchorLayout {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *AnchorLayout) ID(id string) *AnchorLayout {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *AnchorLayout) Class(classes ...string) *AnchorLayout {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *AnchorLayout) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -53,14 +67,19 @@ func (p *AnchorLayout) Update(update bool) {
 func (p *AnchorLayout) ResetParent(child WidgetInterface) {
 	child.Destroy()
 	child.Update(true)
+	setParent(child, p)
 	child.OnDestroy(func() {
 		child.Update(false)
+		setParent(child, nil)
 		p.RemoveChild()
 	})
 }
 
 // 设置子节点
+//
+// 不接管子节点的删除事件，只记录父节点，供组件查询和样式表使用
 func (p *AnchorLayout) AppendChild(child WidgetInterface) *AnchorLayout {
+	setParent(child, p)
 	p.childWidget = child
 	return p
 }
@@ -72,6 +91,9 @@ func (p *AnchorLayout) GetChild() WidgetInterface {
 
 // 删除子节点
 func (p *AnchorLayout) RemoveChild() *AnchorLayout {
+	if p.childWidget != nil && ParentOf(p.childWidget) == WidgetInterface(p) {
+		setParent(p.childWidget, nil)
+	}
 	p.childWidget = nil
 	return p
 }
@@ -97,6 +119,11 @@ func (p *AnchorLayout) Margin(Top, Left, Bottom, Right float32) *AnchorLayout {
 	return p
 }
 
+// 获取外边距
+func (p *AnchorLayout) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 设置锚定方向
 func (p *AnchorLayout) Direction(direc anchor.Direction) *AnchorLayout {
 	p.direction = direc
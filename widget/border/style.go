@@ -0,0 +1,13 @@
+package border
+
+// 边框线条样式
+type Style uint8
+
+const (
+	// 实线
+	Solid Style = iota
+	// 虚线
+	Dashed
+	// 点线
+	Dotted
+)
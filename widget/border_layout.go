@@ -1,11 +1,18 @@
 package widget
 
 import (
+	"image"
 	"image/color"
 
+	"github.com/Seikaijyu/nenki.ui/widget/border"
+	"github.com/Seikaijyu/nenki.ui/widget/edge"
+	"github.com/Seikaijyu/nenki.ui/widget/gradient"
+
 	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
 	gunit "github.com/Seikaijyu/gio/unit"
-	gwidget "github.com/Seikaijyu/gio/widget"
 )
 
 // 校验接口是否实现
@@ -13,19 +20,35 @@ var _ WidgetInterface = &Border{}
 var _ SingleChildLayoutInterface[*Border] = &Border{}
 
 type borderConfig struct {
+	// 主题状态
+	themed themed
 	// 是否更新组件
 	update bool
 	// 删除事件
 	_destroy func()
+	// 背景颜色
+	background color.NRGBA
+	// 渐变、阴影和内边框
+	decoration decoration
 }
 type Border struct {
+	// 组件标识
+	identity
 	config *borderConfig
 	// 外边距
 	margin *glayout.Inset
 	// 内边距
 	padding *glayout.Inset
-	// 边框
-	border *gwidget.Border
+	// 每条边的宽度，按edge.EdgeDirection的顺序排列
+	widths [4]float32
+	// 每条边的颜色，按edge.EdgeDirection的顺序排列
+	colors [4]color.NRGBA
+	// 单独设置过颜色的边，切换主题时不会被覆盖
+	colorSet [4]bool
+	// 每个角的圆角，顺序为左上、右上、右下、左下
+	radii [4]float32
+	// 线条样式
+	style border.Style
 	// 间隔，用于占位
 	spacer *glayout.Inset
 	// 包裹的组件
@@ -38,6 +61,18 @@ func (p *Border) Then(fn func(self *Border)) *Border {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *Border) ID(id string) *Border {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Border) Class(classes ...string) *Border {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *Border) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -48,6 +83,11 @@ func (p *Border) Update(update bool) {
 	p.config.update = update
 }
 
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Border) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *Border) Destroy() {
 	p.config.update = false
@@ -62,8 +102,10 @@ func (p *Border) Destroy() {
 func (p *Border) ResetParent(child WidgetInterface) {
 	child.Destroy()
 	child.Update(true)
+	setParent(child, p)
 	child.OnDestroy(func() {
 		child.Update(false)
+		setParent(child, nil)
 		p.RemoveChild()
 	})
 }
@@ -86,31 +128,103 @@ func (p *Border) RemoveChild() *Border {
 	return p
 }
 
-// 设置边框颜色
+// 设置所有边的颜色
 func (p *Border) Color(r, g, b, a uint8) *Border {
-	p.border.Color = color.NRGBA{
-		R: r,
-		G: g,
-		B: b,
-		A: a,
+	for i := range p.colors {
+		p.colors[i] = color.NRGBA{R: r, G: g, B: b, A: a}
 	}
+	p.config.themed.overrides.set(overrideBorderColor)
+	return p
+}
+
+// 设置一条边的颜色
+func (p *Border) SideColor(side edge.EdgeDirection, r, g, b, a uint8) *Border {
+	p.colors[side] = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.colorSet[side] = true
 	return p
 }
 
-// 设置边框宽度
+// 设置所有边的宽度
 func (p *Border) Width(width float32) *Border {
-	p.border.Width = gunit.Dp(width)
-	p.spacer.Top = gunit.Dp(width)
-	p.spacer.Left = gunit.Dp(width)
-	p.spacer.Bottom = gunit.Dp(width)
-	p.spacer.Right = gunit.Dp(width)
+	return p.Widths(edge.All(width))
+}
 
+// 分别设置每条边的宽度，宽度为0的边不绘制，例如Widths(edge.Bottom.FromDirection(2))只绘制底边
+func (p *Border) Widths(top, left, bottom, right float32) *Border {
+	p.widths = [4]float32{edge.Top: top, edge.Left: left, edge.Bottom: bottom, edge.Right: right}
+	p.spacer.Top = gunit.Dp(top)
+	p.spacer.Left = gunit.Dp(left)
+	p.spacer.Bottom = gunit.Dp(bottom)
+	p.spacer.Right = gunit.Dp(right)
 	return p
 }
 
-// 设置边框圆角
+// 设置一条边的宽度，宽度为0时不绘制这条边
+func (p *Border) SideWidth(side edge.EdgeDirection, width float32) *Border {
+	widths := p.widths
+	widths[side] = width
+	return p.Widths(widths[edge.Top], widths[edge.Left], widths[edge.Bottom], widths[edge.Right])
+}
+
+// 设置所有角的圆角
 func (p *Border) CornerRadius(radius float32) *Border {
-	p.border.CornerRadius = gunit.Dp(radius)
+	return p.CornerRadii(radius, radius, radius, radius)
+}
+
+// 分别设置每个角的圆角，例如标签页的标题可以使用CornerRadii(8, 8, 0, 0)
+func (p *Border) CornerRadii(topLeft, topRight, bottomRight, bottomLeft float32) *Border {
+	p.radii = [4]float32{topLeft, topRight, bottomRight, bottomLeft}
+	return p
+}
+
+// 获取每个角的圆角，顺序与CornerRadii的参数相同
+func (p *Border) GetCornerRadii() (topLeft, topRight, bottomRight, bottomLeft float32) {
+	return p.radii[0], p.radii[1], p.radii[2], p.radii[3]
+}
+
+// 设置线条样式，默认为实线
+func (p *Border) Style(style border.Style) *Border {
+	p.style = style
+	return p
+}
+
+// 设置背景颜色，背景绘制在边框内并使用边框的圆角
+func (p *Border) Background(r, g, b, a uint8) *Border {
+	p.config.background = color.NRGBA{R: r, G: g, B: b, A: a}
+	return p
+}
+
+// 获取背景颜色
+func (p *Border) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.config.background)
+}
+
+// 线性渐变背景，angle为渐变方向的角度，0度从下到上，90度从左到右，180度从上到下
+//
+// 设置渐变后背景颜色不再生效，没有颜色节点时清除渐变
+func (p *Border) LinearGradient(angle float32, stops ...gradient.Stop) *Border {
+	p.config.decoration.linear(angle, stops)
+	return p
+}
+
+// 径向渐变背景，centerX和centerY为圆心相对于边框大小的位置，0.5表示居中，
+// radius为半径相对于圆心到最远角距离的比例，1表示刚好到达最远的角
+//
+// 设置渐变后背景颜色不再生效，没有颜色节点时清除渐变
+func (p *Border) RadialGradient(centerX, centerY, radius float32, stops ...gradient.Stop) *Border {
+	p.config.decoration.radial(centerX, centerY, radius, stops)
+	return p
+}
+
+// 阴影，offsetX和offsetY为偏移，blur为模糊半径，spread为扩展距离，颜色完全透明时清除阴影
+func (p *Border) Shadow(offsetX, offsetY, blur, spread float32, r, g, b, a uint8) *Border {
+	p.config.decoration.setShadow(offsetX, offsetY, blur, spread, color.NRGBA{R: r, G: g, B: b, A: a})
+	return p
+}
+
+// 内边框，绘制在边框内侧并覆盖在子节点上方，宽度为0时清除内边框
+func (p *Border) InnerBorder(width float32, r, g, b, a uint8) *Border {
+	p.config.decoration.setInner(width, color.NRGBA{R: r, G: g, B: b, A: a})
 	return p
 }
 
@@ -125,6 +239,11 @@ func (p *Border) Margin(Top, Left, Bottom, Right float32) *Border {
 	return p
 }
 
+// 获取外边距
+func (p *Border) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 设置内边距
 func (p *Border) Padding(Top, Left, Bottom, Right float32) *Border {
 	p.padding = &glayout.Inset{
@@ -141,35 +260,103 @@ func (p *Border) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update || p.childWidget == nil {
 		return glayout.Dimensions{}
 	}
+	p.applyTheme()
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-		return p.border.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-			return p.padding.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-				return p.spacer.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-					return p.childWidget.Layout(gtx)
-				})
+		// 先测量边框内容的大小，再在内容下方绘制阴影和背景
+		macro := op.Record(gtx.Ops)
+		dims := p.padding.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+			return p.spacer.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+				return p.childWidget.Layout(gtx)
 			})
 		})
+		call := macro.Stop()
+		var widths [4]int
+		for i, width := range p.widths {
+			widths[i] = gtx.Dp(gunit.Dp(width))
+		}
+		var radii [4]int
+		for i, radius := range p.radii {
+			radii[i] = gtx.Dp(gunit.Dp(radius))
+		}
+		shape := clampRRect(clip.RRect{Rect: image.Rectangle{Max: dims.Size}, NW: radii[0], NE: radii[1], SE: radii[2], SW: radii[3]})
+		decoration := &p.config.decoration
+		decoration.paintShadow(gtx, shape)
+		decoration.paintFill(gtx, shape, p.config.background)
+		call.Add(gtx.Ops)
+		decoration.paintInner(gtx, insetRRect(shape, widths[edge.Top], widths[edge.Left], widths[edge.Bottom], widths[edge.Right]))
+		p.paintBorder(gtx, dims.Size, widths, radii)
+		return dims
 	})
+}
+
+// 绘制边框线条，线条覆盖在子节点上方
+func (p *Border) paintBorder(gtx glayout.Context, size image.Point, widths [4]int, radii [4]int) {
+	uniform := true
+	for i := range widths {
+		uniform = uniform && widths[i] == widths[0] && p.colors[i] == p.colors[0]
+	}
+	// 所有边相同的实线直接描边整个圆角矩形
+	if uniform && p.style == border.Solid {
+		width := widths[0]
+		if width == 0 || p.colors[0].A == 0 {
+			return
+		}
+		rect := image.Rectangle{Max: size.Sub(image.Pt(width, width))}.Add(image.Pt(width/2, width/2))
+		rr := clampRRect(clip.RRect{Rect: rect, NW: radii[0], NE: radii[1], SE: radii[2], SW: radii[3]})
+		paint.FillShape(gtx.Ops, p.colors[0], clip.Stroke{Path: rr.Path(gtx.Ops), Width: float32(width)}.Op())
+		return
+	}
+	lines := borderCenterLines(size, widths, radii, p.style != border.Dotted)
+	for k, side := range clockwiseSides {
+		width, c := widths[side], p.colors[side]
+		if width == 0 || c.A == 0 {
+			continue
+		}
+		w := float32(width)
+		switch p.style {
+		case border.Dashed:
+			paint.FillShape(gtx.Ops, c, clip.Stroke{Path: dashedPath(gtx.Ops, lines[k], w*3, w*2), Width: w}.Op())
+		case border.Dotted:
+			// 下一条边不绘制时在终点补上一个点
+			next := clockwiseSides[(k+1)%4]
+			for _, center := range dottedCenters(lines[k], w*2, widths[next] == 0 || p.colors[next].A == 0) {
+				dot := image.Rectangle{Min: image.Pt(int(center.X-w/2+0.5), int(center.Y-w/2+0.5))}
+				dot.Max = dot.Min.Add(image.Pt(width, width))
+				paint.FillShape(gtx.Ops, c, clip.Ellipse(dot).Op(gtx.Ops))
+			}
+		default:
+			paint.FillShape(gtx.Ops, c, clip.Stroke{Path: solidPath(gtx.Ops, lines[k]), Width: w}.Op())
+		}
+	}
+}
 
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *Border) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if p.config.themed.overrides.has(overrideBorderColor) {
+		return
+	}
+	for i := range p.colors {
+		if !p.colorSet[i] {
+			p.colors[i] = th.Text
+		}
+	}
 }
 
 // 创建一个边框
 func NewBorder(widget WidgetInterface) *Border {
+	black := color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xff}
 	border := &Border{
 		childWidget: widget,
-		config:      &borderConfig{update: true},
-		border: &gwidget.Border{
-			Color: color.NRGBA{
-				R: 0x00,
-				G: 0x00,
-				B: 0x00,
-				A: 0xff,
-			},
-			Width: gunit.Dp(1),
-		},
-		margin:  &glayout.Inset{},
-		padding: &glayout.Inset{},
-		spacer:  &glayout.Inset{},
+		config:      &borderConfig{update: true, themed: newThemed()},
+		widths:      [4]float32{1, 1, 1, 1},
+		colors:      [4]color.NRGBA{black, black, black, black},
+		margin:      &glayout.Inset{},
+		padding:     &glayout.Inset{},
+		spacer:      &glayout.Inset{},
 	}
 	border.AppendChild(widget)
 	return border
@@ -0,0 +1,186 @@
+package widget
+
+import (
+	"image"
+	"math"
+
+	"github.com/Seikaijyu/nenki.ui/widget/edge"
+
+	"github.com/Seikaijyu/gio/f32"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+)
+
+// 顺时针排列的四条边，第k条边从第k个角到第k+1个角，角的顺序为左上、右上、右下、左下
+var clockwiseSides = [4]edge.EdgeDirection{edge.Top, edge.Right, edge.Bottom, edge.Left}
+
+// 每个角圆弧的起始角度，y轴向下
+var cornerAngles = [4]float64{180, 270, 360, 450}
+
+// 边框每条边的中心线，按clockwiseSides排列，widths按edge.EdgeDirection排列，radii按左上、右上、右下、左下排列
+//
+// 每条边从前一个角的圆弧中点开始，到后一个角的圆弧中点结束，相邻的边在圆弧中点处对接；
+// extend为true时没有圆角的角会把两端延长相邻边宽度的一半，填满角上的方块
+func borderCenterLines(size image.Point, widths [4]int, radii [4]int, extend bool) [4][]f32.Point {
+	x0, y0 := float32(widths[edge.Left])/2, float32(widths[edge.Top])/2
+	x1, y1 := float32(size.X)-float32(widths[edge.Right])/2, float32(size.Y)-float32(widths[edge.Bottom])/2
+	limit := float32(math.Max(float64(minFloat32(x1-x0, y1-y0)/2), 0))
+	var rc [4]float32
+	for i, r := range radii {
+		rc[i] = clampRange(float32(r), 0, limit)
+	}
+	centers := [4]f32.Point{
+		f32.Pt(x0+rc[0], y0+rc[0]),
+		f32.Pt(x1-rc[1], y0+rc[1]),
+		f32.Pt(x1-rc[2], y1-rc[2]),
+		f32.Pt(x0+rc[3], y1-rc[3]),
+	}
+	var lines [4][]f32.Point
+	for k := range lines {
+		next := (k + 1) % 4
+		pts := appendArc(nil, centers[k], rc[k], cornerAngles[k]+45, cornerAngles[k]+90)
+		pts = appendArc(pts, centers[next], rc[next], cornerAngles[next], cornerAngles[next]+45)
+		if extend && rc[k] == 0 {
+			pts = extendPolyline(pts, float32(widths[clockwiseSides[(k+3)%4]])/2, true)
+		}
+		if extend && rc[next] == 0 {
+			pts = extendPolyline(pts, float32(widths[clockwiseSides[next]])/2, false)
+		}
+		lines[k] = pts
+	}
+	return lines
+}
+
+// 添加圆弧上的点，半径为0时只添加圆心
+func appendArc(pts []f32.Point, center f32.Point, radius float32, from, to float64) []f32.Point {
+	if radius <= 0 {
+		return append(pts, center)
+	}
+	n := maxInt(int(radius/3), 2)
+	for i := 0; i <= n; i++ {
+		sin, cos := math.Sincos((from + (to-from)*float64(i)/float64(n)) * math.Pi / 180)
+		pts = append(pts, center.Add(f32.Pt(float32(cos)*radius, float32(sin)*radius)))
+	}
+	return pts
+}
+
+// 沿折线的方向延长起点或终点
+func extendPolyline(pts []f32.Point, d float32, start bool) []f32.Point {
+	if d <= 0 || len(pts) < 2 {
+		return pts
+	}
+	if start {
+		for _, pt := range pts[1:] {
+			if dir := pts[0].Sub(pt); dir != (f32.Point{}) {
+				return append([]f32.Point{pts[0].Add(dir.Mul(d / distance(pts[0], pt)))}, pts...)
+			}
+		}
+		return pts
+	}
+	last := pts[len(pts)-1]
+	for i := len(pts) - 2; i >= 0; i-- {
+		if dir := last.Sub(pts[i]); dir != (f32.Point{}) {
+			return append(pts, last.Add(dir.Mul(d/distance(last, pts[i]))))
+		}
+	}
+	return pts
+}
+
+// 折线的长度
+func polylineLength(pts []f32.Point) float32 {
+	length := float32(0)
+	for i := 1; i < len(pts); i++ {
+		length += distance(pts[i-1], pts[i])
+	}
+	return length
+}
+
+// 截取折线上距离起点from到to的一段
+func polylineSlice(pts []f32.Point, from, to float32) []f32.Point {
+	var out []f32.Point
+	walked := float32(0)
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		seg := distance(a, b)
+		if seg == 0 {
+			continue
+		}
+		start, end := walked, walked+seg
+		walked = end
+		if end < from || start > to {
+			continue
+		}
+		if len(out) == 0 {
+			out = append(out, a.Add(b.Sub(a).Mul(clamp01((from-start)/seg))))
+		}
+		out = append(out, a.Add(b.Sub(a).Mul(clamp01((to-start)/seg))))
+		if end >= to {
+			break
+		}
+	}
+	return out
+}
+
+// 把折线添加到路径中
+func addPolyline(path *clip.Path, pts []f32.Point) {
+	if len(pts) < 2 {
+		return
+	}
+	path.MoveTo(pts[0])
+	for _, pt := range pts[1:] {
+		path.LineTo(pt)
+	}
+}
+
+// 实线路径
+func solidPath(ops *op.Ops, pts []f32.Point) clip.PathSpec {
+	var path clip.Path
+	path.Begin(ops)
+	addPolyline(&path, pts)
+	return path.End()
+}
+
+// 虚线路径，调整线段和间隔的长度使两端都以完整的线段结束
+func dashedPath(ops *op.Ops, pts []f32.Point, dash, gap float32) clip.PathSpec {
+	var path clip.Path
+	path.Begin(ops)
+	length := polylineLength(pts)
+	n := maxInt(int(math.Round(float64((length+gap)/(dash+gap)))), 1)
+	scale := length / (float32(n)*dash + float32(n-1)*gap)
+	dash, gap = dash*scale, gap*scale
+	for i := 0; i < n; i++ {
+		from := float32(i) * (dash + gap)
+		addPolyline(&path, polylineSlice(pts, from, from+dash))
+	}
+	return path.End()
+}
+
+// 点线中每个点的圆心，调整间隔使点均匀分布，end为true时在终点也放置一个点
+func dottedCenters(pts []f32.Point, spacing float32, end bool) []f32.Point {
+	length := polylineLength(pts)
+	n := maxInt(int(math.Round(float64(length/spacing))), 1)
+	step := length / float32(n)
+	if end {
+		n++
+	}
+	centers := make([]f32.Point, 0, n)
+	for i := 0; i < n; i++ {
+		d := float32(i) * step
+		if d >= length {
+			centers = append(centers, pts[len(pts)-1])
+			continue
+		}
+		if seg := polylineSlice(pts, d, d); len(seg) > 0 {
+			centers = append(centers, seg[0])
+		}
+	}
+	return centers
+}
+
+// 返回两个数中较小的一个
+func minFloat32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -1,13 +1,21 @@
 package widget
 
 import (
+	"image"
 	"image/color"
 	"time"
 
+	"github.com/Seikaijyu/nenki.ui/utils"
 	"github.com/Seikaijyu/nenki.ui/widget/edge"
+	"github.com/Seikaijyu/nenki.ui/widget/gradient"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
 	"github.com/Seikaijyu/nenki.ui/widget/theme"
 
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/io/semantic"
 	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op/paint"
 	gunit "github.com/Seikaijyu/gio/unit"
 	gwidget "github.com/Seikaijyu/gio/widget"
 	gmaterial "github.com/Seikaijyu/gio/widget/material"
@@ -15,6 +23,18 @@ import (
 
 // 按钮配置
 type buttonConfig struct {
+	// 主题状态
+	themed themed
+	// 字体族
+	typeface typeface
+	// 字间距
+	letterSpacing float32
+	// 装饰线
+	decoration text.Decoration
+	// 背景渐变、阴影和内边框
+	background decoration
+	// 是否禁用
+	disabled bool
 	// 是否更新组件
 	update bool
 	// 删除事件
@@ -37,6 +57,8 @@ type buttonConfig struct {
 	_hoverValue bool
 }
 type Button struct {
+	// 组件标识
+	identity
 	margin *glayout.Inset
 	// 配置
 	config *buttonConfig
@@ -57,11 +79,28 @@ func (p *Button) Then(fn func(self *Button)) *Button {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *Button) ID(id string) *Button {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Button) Class(classes ...string) *Button {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *Button) OnDestroy(fn func()) {
 	p.config._destroy = fn
 }
 
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Button) themeState() *themed {
+	return &p.config.themed
+}
+
 // 删除组件
 func (p *Button) Destroy() {
 	p.config.update = false
@@ -76,6 +115,17 @@ func (p *Button) Update(update bool) {
 	p.config.update = update
 }
 
+// 是否禁用组件，禁用时不响应任何输入
+func (p *Button) Disabled(disabled bool) *Button {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *Button) GetState() State {
+	return stateOf(p.button.Button.Hovered(), p.button.Button.Focused(), p.config.disabled, false)
+}
+
 // 设置焦点为按钮
 func (p *Button) Focus() *Button {
 	p.button.Button.Focus()
@@ -85,24 +135,120 @@ func (p *Button) Focus() *Button {
 // 设置字体大小
 func (p *Button) FontSize(size int) *Button {
 	p.button.TextSize = gunit.Sp(size)
+	p.config.themed.overrides.set(overrideFontSize)
+	return p
+}
+
+// 获取字体大小
+func (p *Button) GetFontSize() int {
+	return int(p.button.TextSize)
+}
+
+// 设置字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *Button) FontFamily(name string) *Button {
+	p.config.typeface.set(name)
+	return p
+}
+
+// 设置字体粗细
+func (p *Button) FontWeight(weight text.Weight) *Button {
+	p.button.Font.Weight = weight
+	return p
+}
+
+// 设置是否使用斜体
+func (p *Button) Italic(italic bool) *Button {
+	p.button.Font.Style = gfont.Regular
+	if italic {
+		p.button.Font.Style = gfont.Italic
+	}
+	return p
+}
+
+// 设置文本样式
+func (p *Button) TextStyle(style text.Style) *Button {
+	p.FontFamily(style.Family)
+	p.FontWeight(style.Weight)
+	p.Italic(style.Italic)
+	p.LetterSpacing(style.LetterSpacing)
+	p.Decoration(style.Decoration)
+	return p
+}
+
+// 设置字间距，字间距不参与换行计算
+func (p *Button) LetterSpacing(spacing float32) *Button {
+	p.config.letterSpacing = spacing
+	return p
+}
+
+// 设置装饰线，例如text.Underline|text.Strikethrough
+func (p *Button) Decoration(decoration text.Decoration) *Button {
+	p.config.decoration = decoration
 	return p
 }
 
 // 设置圆角
 func (p *Button) CornerRadius(radius float32) *Button {
 	p.button.CornerRadius = gunit.Dp(radius)
+	p.config.themed.overrides.set(overrideCornerRadius)
 	return p
 }
 
+// 获取圆角
+func (p *Button) GetCornerRadius() float32 {
+	return float32(p.button.CornerRadius)
+}
+
 // 设置文字颜色
 func (p *Button) FontColor(r, g, b, a uint8) *Button {
 	p.button.Color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
 	return p
 }
 
+// 获取文字颜色
+func (p *Button) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.button.Color)
+}
+
 // 设置背景颜色
 func (p *Button) Background(r, g, b, a uint8) *Button {
 	p.button.Background = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBackground)
+	return p
+}
+
+// 获取背景颜色
+func (p *Button) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.button.Background)
+}
+
+// 设置线性渐变背景，angle为渐变方向的角度，0度从下到上，90度从左到右，180度从上到下
+//
+// 设置渐变后背景颜色不再生效，没有颜色节点时清除渐变
+func (p *Button) LinearGradient(angle float32, stops ...gradient.Stop) *Button {
+	p.config.background.linear(angle, stops)
+	return p
+}
+
+// 设置径向渐变背景，centerX和centerY为圆心相对于按钮大小的位置，0.5表示居中，
+// radius为半径相对于圆心到最远角距离的比例，1表示刚好到达最远的角
+//
+// 设置渐变后背景颜色不再生效，没有颜色节点时清除渐变
+func (p *Button) RadialGradient(centerX, centerY, radius float32, stops ...gradient.Stop) *Button {
+	p.config.background.radial(centerX, centerY, radius, stops)
+	return p
+}
+
+// 设置阴影，offsetX和offsetY为偏移，blur为模糊半径，spread为扩展距离，颜色完全透明时清除阴影
+func (p *Button) Shadow(offsetX, offsetY, blur, spread float32, r, g, b, a uint8) *Button {
+	p.config.background.setShadow(offsetX, offsetY, blur, spread, color.NRGBA{R: r, G: g, B: b, A: a})
+	return p
+}
+
+// 设置内边框，绘制在按钮边缘内侧，宽度为0时清除内边框
+func (p *Button) InnerBorder(width float32, r, g, b, a uint8) *Button {
+	p.config.background.setInner(width, color.NRGBA{R: r, G: g, B: b, A: a})
 	return p
 }
 
@@ -128,6 +274,11 @@ func (p *Button) Margin(top, left, bottom, right float32) *Button {
 	return p
 }
 
+// 获取外边距
+func (p *Button) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 设置文本
 func (p *Button) Text(text string) *Button {
 	p.button.Text = text
@@ -178,6 +329,12 @@ func (p *Button) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		return glayout.Dimensions{}
 	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	p.button.Font.Typeface = p.config.typeface.resolve(p.button.Text)
 	// 悬浮事件
 	if p.config._hovered != nil && p.config._hoverValue != p.button.Button.Hovered() {
 		p.config._hovered(p, p.button.Button.Hovered())
@@ -244,19 +401,104 @@ func (p *Button) Layout(gtx glayout.Context) glayout.Dimensions {
 	}
 	// 外边距
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		// 有渐变、阴影或内边框时自行绘制按钮背景
+		if p.config.background.active() {
+			return p.layoutDecorated(gtx)
+		}
+		// 有字间距或装饰线时自行绘制按钮文本
+		if p.config.letterSpacing != 0 || p.config.decoration != text.DecorationNone {
+			return gmaterial.ButtonLayoutStyle{
+				Background:   p.button.Background,
+				CornerRadius: p.button.CornerRadius,
+				Button:       p.button.Button,
+			}.Layout(gtx, p.layoutText)
+		}
 		// 按钮
 		return p.button.Layout(gtx)
 	})
 }
 
+// 绘制按钮文本
+func (p *Button) layoutText(gtx glayout.Context) glayout.Dimensions {
+	return p.button.Inset.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		return textpaint.Label{
+			Alignment:     text.Middle,
+			LetterSpacing: gunit.Sp(p.config.letterSpacing),
+			Decoration:    p.config.decoration,
+		}.Layout(gtx, theme.Shaper, p.button.Font, p.button.TextSize, p.button.Text, p.button.Color)
+	})
+}
+
+// 绘制带有渐变、阴影或内边框的按钮，悬浮、焦点和禁用状态的效果与普通按钮一致
+func (p *Button) layoutDecorated(gtx glayout.Context) glayout.Dimensions {
+	min := gtx.Constraints.Min
+	return p.button.Button.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		semantic.Button.Add(gtx.Ops)
+		return glayout.Background{}.Layout(gtx,
+			func(gtx glayout.Context) glayout.Dimensions {
+				shape := roundedRect(image.Rectangle{Max: gtx.Constraints.Min}, gtx.Dp(p.button.CornerRadius))
+				background := &p.config.background
+				background.paintShadow(gtx, shape)
+				// 禁用时降低整体的不透明度
+				if gtx.Queue == nil {
+					defer paint.PushOpacity(gtx.Ops, 0.6).Pop()
+				}
+				background.paintFill(gtx, shape, p.button.Background)
+				// 悬浮或获得焦点时叠加一层高亮
+				if gtx.Queue != nil && (p.button.Button.Hovered() || p.button.Button.Focused()) {
+					paint.FillShape(gtx.Ops, p.hoverOverlay(), shape.Op(gtx.Ops))
+				}
+				background.paintInner(gtx, shape)
+				return glayout.Dimensions{Size: gtx.Constraints.Min}
+			},
+			func(gtx glayout.Context) glayout.Dimensions {
+				gtx.Constraints.Min = min
+				return glayout.Center.Layout(gtx, p.layoutText)
+			},
+		)
+	})
+}
+
+// 悬浮高亮的颜色，浅色背景变暗，深色背景变亮
+func (p *Button) hoverOverlay() color.NRGBA {
+	base := p.button.Background
+	if g := p.config.background.gradient; g != nil {
+		base = gradient.ColorAt(g.stops, 0.5)
+	}
+	if utils.FromColor(base).Luminance() > 0.2 {
+		return color.NRGBA{A: 0x20}
+	}
+	return color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x20}
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *Button) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideBackground) {
+		p.button.Background = th.Primary
+	}
+	if !p.config.themed.overrides.has(overrideFontColor) {
+		p.button.Color = th.OnPrimary
+	}
+	if !p.config.themed.overrides.has(overrideFontSize) {
+		p.button.TextSize = gunit.Sp(th.Body)
+	}
+	if !p.config.themed.overrides.has(overrideCornerRadius) {
+		p.button.CornerRadius = gunit.Dp(th.CornerRadius)
+	}
+}
+
 // 创建按钮
 func NewButton(text string) *Button {
 	widget := &Button{
 		clickCount:    0,
 		lastClickTime: time.Time{},
 		margin:        &glayout.Inset{},
-		config:        &buttonConfig{update: true},
-		button:        gmaterial.Button(theme.NewTheme(), &gwidget.Clickable{}, text),
+		config:        &buttonConfig{update: true, themed: newThemed(), typeface: newTypeface()},
+		button:        gmaterial.Button(theme.Material(), &gwidget.Clickable{}, text),
 	}
 	widget.Padding(edge.All(0))
 	return widget
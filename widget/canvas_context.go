@@ -0,0 +1,507 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+
+	"github.com/Seikaijyu/nenki.ui/widget/font"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	"github.com/Seikaijyu/gio/f32"
+	gfont "github.com/Seikaijyu/gio/font"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 路径命令的类型
+const (
+	canvasMove = iota
+	canvasLine
+	canvasQuad
+	canvasCubic
+	canvasClose
+)
+
+// 路径命令
+type canvasCmd struct {
+	// 命令类型
+	kind int
+	// 控制点和终点，按命令类型使用
+	pts [3]f32.Point
+}
+
+// 绘制状态，Save时保存，Restore时恢复
+type canvasState struct {
+	// 填充颜色，也用于文字
+	fill color.NRGBA
+	// 描边颜色
+	stroke color.NRGBA
+	// 描边宽度
+	lineWidth float32
+	// 字体大小
+	fontSize float32
+	// 字体族
+	fontFamily string
+	// 字体粗细
+	fontWeight text.Weight
+	// 文字对齐方式
+	textAlign text.Alignment
+}
+
+// 保存的状态
+type canvasSaved struct {
+	// 保存时的绘制状态
+	state canvasState
+	// 保存时已经压入的变换、裁剪和透明度数量
+	stacks int
+}
+
+// 画布的绘制上下文，在Canvas的重绘函数中使用，所有坐标和尺寸的单位都是dp
+//
+// 绘制的内容会被保留，直到下一次重绘；变换、裁剪和透明度在Restore或重绘结束时撤销
+type CanvasContext struct {
+	// 记录绘制内容的操作列表
+	ops *op.Ops
+	// 排版文字使用的上下文
+	gtx glayout.Context
+	// 当前状态
+	state canvasState
+	// 保存的状态
+	saved []canvasSaved
+	// 已经压入的变换、裁剪和透明度
+	stacks []interface{ Pop() }
+	// 当前路径
+	path []canvasCmd
+	// 图像缓存，由画布持有
+	images map[image.Image]paint.ImageOp
+	// 本次重绘用到的图像
+	used map[image.Image]paint.ImageOp
+}
+
+// 创建绘制上下文，gtx只用于读取度量和语言
+func newCanvasContext(ops *op.Ops, gtx glayout.Context, images map[image.Image]paint.ImageOp) *CanvasContext {
+	th := theme.Current()
+	return &CanvasContext{
+		ops: ops,
+		gtx: glayout.Context{
+			Ops: ops,
+			// 内容以dp记录，绘制时再整体缩放，文字只需要保留sp和dp的比例
+			Metric:      gunit.Metric{PxPerDp: 1, PxPerSp: gtx.Metric.PxPerSp / gtx.Metric.PxPerDp},
+			Locale:      gtx.Locale,
+			Constraints: glayout.Constraints{Max: image.Pt(1<<20, 1<<20)},
+		},
+		state: canvasState{
+			fill:      th.Text,
+			stroke:    th.Text,
+			lineWidth: 1,
+			fontSize:  th.Body,
+		},
+		images: images,
+		used:   map[image.Image]paint.ImageOp{},
+	}
+}
+
+// 撤销所有变换、裁剪和透明度，返回本次重绘用到的图像
+func (p *CanvasContext) finish() map[image.Image]paint.ImageOp {
+	p.popTo(0)
+	return p.used
+}
+
+// 撤销到指定数量的变换、裁剪和透明度
+func (p *CanvasContext) popTo(n int) {
+	for len(p.stacks) > n {
+		p.stacks[len(p.stacks)-1].Pop()
+		p.stacks = p.stacks[:len(p.stacks)-1]
+	}
+}
+
+// 保存当前的绘制状态、变换、裁剪和透明度
+func (p *CanvasContext) Save() *CanvasContext {
+	p.saved = append(p.saved, canvasSaved{state: p.state, stacks: len(p.stacks)})
+	return p
+}
+
+// 恢复到上一次Save时的绘制状态，撤销之后的变换、裁剪和透明度
+func (p *CanvasContext) Restore() *CanvasContext {
+	if len(p.saved) == 0 {
+		return p
+	}
+	saved := p.saved[len(p.saved)-1]
+	p.saved = p.saved[:len(p.saved)-1]
+	p.popTo(saved.stacks)
+	p.state = saved.state
+	return p
+}
+
+// 填充颜色，同时用于文字
+func (p *CanvasContext) FillColor(r, g, b, a uint8) *CanvasContext {
+	p.state.fill = color.NRGBA{R: r, G: g, B: b, A: a}
+	return p
+}
+
+// 描边颜色
+func (p *CanvasContext) StrokeColor(r, g, b, a uint8) *CanvasContext {
+	p.state.stroke = color.NRGBA{R: r, G: g, B: b, A: a}
+	return p
+}
+
+// 描边宽度
+func (p *CanvasContext) LineWidth(width float32) *CanvasContext {
+	p.state.lineWidth = width
+	return p
+}
+
+// 字体大小
+func (p *CanvasContext) FontSize(size float32) *CanvasContext {
+	p.state.fontSize = size
+	return p
+}
+
+// 字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *CanvasContext) FontFamily(name string) *CanvasContext {
+	p.state.fontFamily = name
+	return p
+}
+
+// 字体粗细
+func (p *CanvasContext) FontWeight(weight text.Weight) *CanvasContext {
+	p.state.fontWeight = weight
+	return p
+}
+
+// 文字的水平对齐方式，Start时x为文字左边，Middle时为中间，End时为右边
+func (p *CanvasContext) TextAlign(align text.Alignment) *CanvasContext {
+	p.state.textAlign = align
+	return p
+}
+
+// 之后绘制的内容使用的不透明度，0为完全透明，1为不透明，多次设置时相乘
+func (p *CanvasContext) Opacity(opacity float32) *CanvasContext {
+	p.stacks = append(p.stacks, paint.PushOpacity(p.ops, clamp01(opacity)))
+	return p
+}
+
+// 平移坐标系
+func (p *CanvasContext) Translate(x, y float32) *CanvasContext {
+	return p.transform(f32.Affine2D{}.Offset(f32.Pt(x, y)))
+}
+
+// 绕原点旋转坐标系，角度为正时顺时针旋转
+func (p *CanvasContext) Rotate(degrees float32) *CanvasContext {
+	return p.transform(f32.Affine2D{}.Rotate(f32.Point{}, degrees*math.Pi/180))
+}
+
+// 以原点为中心缩放坐标系
+func (p *CanvasContext) Scale(sx, sy float32) *CanvasContext {
+	return p.transform(f32.Affine2D{}.Scale(f32.Point{}, f32.Pt(sx, sy)))
+}
+
+// 压入变换
+func (p *CanvasContext) transform(t f32.Affine2D) *CanvasContext {
+	p.stacks = append(p.stacks, op.Affine(t).Push(p.ops))
+	return p
+}
+
+// 把之后的绘制限制在矩形内
+func (p *CanvasContext) ClipRect(x, y, width, height float32) *CanvasContext {
+	return p.clipShape(p.rectPath(x, y, width, height, 0))
+}
+
+// 把之后的绘制限制在圆角矩形内
+func (p *CanvasContext) ClipRoundRect(x, y, width, height, radius float32) *CanvasContext {
+	return p.clipShape(p.rectPath(x, y, width, height, radius))
+}
+
+// 把之后的绘制限制在当前路径内，不会清除当前路径
+func (p *CanvasContext) ClipPath() *CanvasContext {
+	return p.clipShape(p.path)
+}
+
+// 压入裁剪
+func (p *CanvasContext) clipShape(cmds []canvasCmd) *CanvasContext {
+	p.stacks = append(p.stacks, clip.Outline{Path: p.build(cmds, true)}.Op().Push(p.ops))
+	return p
+}
+
+// 清除当前路径，开始新的路径
+func (p *CanvasContext) BeginPath() *CanvasContext {
+	p.path = p.path[:0]
+	return p
+}
+
+// 移动到指定点，开始新的子路径
+func (p *CanvasContext) MoveTo(x, y float32) *CanvasContext {
+	p.path = append(p.path, canvasCmd{kind: canvasMove, pts: [3]f32.Point{f32.Pt(x, y)}})
+	return p
+}
+
+// 从当前点画直线到指定点
+func (p *CanvasContext) LineTo(x, y float32) *CanvasContext {
+	p.path = append(p.path, canvasCmd{kind: canvasLine, pts: [3]f32.Point{f32.Pt(x, y)}})
+	return p
+}
+
+// 从当前点画二次贝塞尔曲线到指定点
+func (p *CanvasContext) QuadTo(cx, cy, x, y float32) *CanvasContext {
+	p.path = append(p.path, canvasCmd{kind: canvasQuad, pts: [3]f32.Point{f32.Pt(cx, cy), f32.Pt(x, y)}})
+	return p
+}
+
+// 从当前点画三次贝塞尔曲线到指定点
+func (p *CanvasContext) CubicTo(c1x, c1y, c2x, c2y, x, y float32) *CanvasContext {
+	p.path = append(p.path, canvasCmd{kind: canvasCubic, pts: [3]f32.Point{f32.Pt(c1x, c1y), f32.Pt(c2x, c2y), f32.Pt(x, y)}})
+	return p
+}
+
+// 添加圆弧，角度单位为度，0度指向右边，end大于start时顺时针绘制
+//
+// 路径不为空时先用直线连接到圆弧的起点
+func (p *CanvasContext) Arc(cx, cy, radius, start, end float32) *CanvasContext {
+	p.path = appendCanvasArc(p.path, f32.Pt(cx, cy), radius, start, end, len(p.path) == 0)
+	return p
+}
+
+// 闭合当前子路径
+func (p *CanvasContext) ClosePath() *CanvasContext {
+	p.path = append(p.path, canvasCmd{kind: canvasClose})
+	return p
+}
+
+// 使用填充颜色填充当前路径，未闭合的子路径会自动闭合
+func (p *CanvasContext) Fill() *CanvasContext {
+	return p.fillShape(p.path)
+}
+
+// 使用描边颜色和宽度描边当前路径
+func (p *CanvasContext) Stroke() *CanvasContext {
+	return p.strokeShape(p.path)
+}
+
+// 填充矩形
+func (p *CanvasContext) FillRect(x, y, width, height float32) *CanvasContext {
+	return p.fillShape(p.rectPath(x, y, width, height, 0))
+}
+
+// 描边矩形
+func (p *CanvasContext) StrokeRect(x, y, width, height float32) *CanvasContext {
+	return p.strokeShape(p.rectPath(x, y, width, height, 0))
+}
+
+// 填充圆角矩形
+func (p *CanvasContext) FillRoundRect(x, y, width, height, radius float32) *CanvasContext {
+	return p.fillShape(p.rectPath(x, y, width, height, radius))
+}
+
+// 描边圆角矩形
+func (p *CanvasContext) StrokeRoundRect(x, y, width, height, radius float32) *CanvasContext {
+	return p.strokeShape(p.rectPath(x, y, width, height, radius))
+}
+
+// 填充圆
+func (p *CanvasContext) FillCircle(cx, cy, radius float32) *CanvasContext {
+	return p.fillShape(appendCanvasArc(nil, f32.Pt(cx, cy), radius, 0, 360, true))
+}
+
+// 描边圆
+func (p *CanvasContext) StrokeCircle(cx, cy, radius float32) *CanvasContext {
+	return p.strokeShape(append(appendCanvasArc(nil, f32.Pt(cx, cy), radius, 0, 360, true), canvasCmd{kind: canvasClose}))
+}
+
+// 描边圆弧，角度单位为度，0度指向右边，end大于start时顺时针绘制
+func (p *CanvasContext) StrokeArc(cx, cy, radius, start, end float32) *CanvasContext {
+	return p.strokeShape(appendCanvasArc(nil, f32.Pt(cx, cy), radius, start, end, true))
+}
+
+// 填充扇形，角度单位为度，0度指向右边，end大于start时顺时针绘制
+func (p *CanvasContext) FillSector(cx, cy, radius, start, end float32) *CanvasContext {
+	center := f32.Pt(cx, cy)
+	cmds := []canvasCmd{{kind: canvasMove, pts: [3]f32.Point{center}}}
+	return p.fillShape(appendCanvasArc(cmds, center, radius, start, end, false))
+}
+
+// 画直线
+func (p *CanvasContext) Line(x1, y1, x2, y2 float32) *CanvasContext {
+	return p.strokeShape([]canvasCmd{
+		{kind: canvasMove, pts: [3]f32.Point{f32.Pt(x1, y1)}},
+		{kind: canvasLine, pts: [3]f32.Point{f32.Pt(x2, y2)}},
+	})
+}
+
+// 使用填充颜色绘制文字，y为文字的顶部，x的含义由TextAlign决定
+func (p *CanvasContext) Text(x, y float32, txt string) *CanvasContext {
+	macro := op.Record(p.ops)
+	dims := p.text(txt, p.state.fill)
+	call := macro.Stop()
+	switch p.state.textAlign {
+	case text.Middle:
+		x -= float32(dims.Size.X) / 2
+	case text.End:
+		x -= float32(dims.Size.X)
+	}
+	defer op.Affine(f32.Affine2D{}.Offset(f32.Pt(x, y))).Push(p.ops).Pop()
+	call.Add(p.ops)
+	return p
+}
+
+// 测量文字的宽度和高度
+func (p *CanvasContext) MeasureText(txt string) (float32, float32) {
+	macro := op.Record(p.ops)
+	dims := p.text(txt, p.state.fill)
+	macro.Stop()
+	return float32(dims.Size.X), float32(dims.Size.Y)
+}
+
+// 在原点排版并绘制文字
+func (p *CanvasContext) text(txt string, col color.NRGBA) glayout.Dimensions {
+	face := gfont.Font{Typeface: font.Resolve(p.state.fontFamily, txt), Weight: p.state.fontWeight}
+	return textpaint.Label{}.Layout(p.gtx, theme.Shaper, face, gunit.Sp(p.state.fontSize), txt, col)
+}
+
+// 绘制图像，图像缩放到指定的宽度和高度，宽度或高度为0时使用图像的像素大小
+//
+// 同一个图像在多次重绘之间只上传一次，修改图像内容后需要传入新的图像
+func (p *CanvasContext) Image(img image.Image, x, y, width, height float32) *CanvasContext {
+	if img == nil {
+		return p
+	}
+	imageOp, ok := p.used[img]
+	if !ok {
+		comparable := reflect.TypeOf(img).Comparable()
+		if comparable {
+			imageOp, ok = p.images[img]
+		}
+		if !ok {
+			imageOp = paint.NewImageOp(img)
+		}
+		if comparable {
+			p.used[img] = imageOp
+		}
+	}
+	size := imageOp.Size()
+	if size.X == 0 || size.Y == 0 {
+		return p
+	}
+	if width <= 0 {
+		width = float32(size.X)
+	}
+	if height <= 0 {
+		height = float32(size.Y)
+	}
+	t := f32.Affine2D{}.Scale(f32.Point{}, f32.Pt(width/float32(size.X), height/float32(size.Y))).Offset(f32.Pt(x, y))
+	defer op.Affine(t).Push(p.ops).Pop()
+	defer clip.Rect{Max: size}.Push(p.ops).Pop()
+	imageOp.Add(p.ops)
+	paint.PaintOp{}.Add(p.ops)
+	return p
+}
+
+// 填充路径
+func (p *CanvasContext) fillShape(cmds []canvasCmd) *CanvasContext {
+	if len(cmds) == 0 || p.state.fill.A == 0 {
+		return p
+	}
+	paint.FillShape(p.ops, p.state.fill, clip.Outline{Path: p.build(cmds, true)}.Op())
+	return p
+}
+
+// 描边路径
+func (p *CanvasContext) strokeShape(cmds []canvasCmd) *CanvasContext {
+	if len(cmds) == 0 || p.state.stroke.A == 0 || p.state.lineWidth <= 0 {
+		return p
+	}
+	paint.FillShape(p.ops, p.state.stroke, clip.Stroke{Path: p.build(cmds, false), Width: p.state.lineWidth}.Op())
+	return p
+}
+
+// 把路径命令转换为gio路径，closeAll为true时闭合所有子路径
+func (p *CanvasContext) build(cmds []canvasCmd, closeAll bool) clip.PathSpec {
+	var path clip.Path
+	path.Begin(p.ops)
+	open := false
+	for _, cmd := range cmds {
+		switch cmd.kind {
+		case canvasMove:
+			if open && closeAll {
+				path.Close()
+			}
+			path.MoveTo(cmd.pts[0])
+			open = true
+		case canvasLine:
+			path.LineTo(cmd.pts[0])
+			open = true
+		case canvasQuad:
+			path.QuadTo(cmd.pts[0], cmd.pts[1])
+			open = true
+		case canvasCubic:
+			path.CubeTo(cmd.pts[0], cmd.pts[1], cmd.pts[2])
+			open = true
+		case canvasClose:
+			path.Close()
+			open = false
+		}
+	}
+	if open && closeAll {
+		path.Close()
+	}
+	return path.End()
+}
+
+// 矩形路径，radius大于0时为圆角矩形
+func (p *CanvasContext) rectPath(x, y, width, height, radius float32) []canvasCmd {
+	radius = clampRange(radius, 0, minFloat32(abs32(width), abs32(height))/2)
+	if radius == 0 {
+		return []canvasCmd{
+			{kind: canvasMove, pts: [3]f32.Point{f32.Pt(x, y)}},
+			{kind: canvasLine, pts: [3]f32.Point{f32.Pt(x+width, y)}},
+			{kind: canvasLine, pts: [3]f32.Point{f32.Pt(x+width, y+height)}},
+			{kind: canvasLine, pts: [3]f32.Point{f32.Pt(x, y+height)}},
+			{kind: canvasClose},
+		}
+	}
+	var cmds []canvasCmd
+	cmds = appendCanvasArc(cmds, f32.Pt(x+radius, y+radius), radius, 180, 270, true)
+	cmds = appendCanvasArc(cmds, f32.Pt(x+width-radius, y+radius), radius, 270, 360, false)
+	cmds = appendCanvasArc(cmds, f32.Pt(x+width-radius, y+height-radius), radius, 0, 90, false)
+	cmds = appendCanvasArc(cmds, f32.Pt(x+radius, y+height-radius), radius, 90, 180, false)
+	return append(cmds, canvasCmd{kind: canvasClose})
+}
+
+// 添加圆弧，每段不超过90度，使用三次贝塞尔曲线近似；move为true时移动到起点，否则用直线连接到起点
+func appendCanvasArc(cmds []canvasCmd, center f32.Point, radius, start, end float32, move bool) []canvasCmd {
+	at := func(deg float64) f32.Point {
+		sin, cos := math.Sincos(deg * math.Pi / 180)
+		return center.Add(f32.Pt(float32(cos)*radius, float32(sin)*radius))
+	}
+	kind := canvasLine
+	if move {
+		kind = canvasMove
+	}
+	cmds = append(cmds, canvasCmd{kind: kind, pts: [3]f32.Point{at(float64(start))}})
+	sweep := float64(end - start)
+	segments := int(math.Ceil(math.Abs(sweep) / 90))
+	if segments == 0 || radius <= 0 {
+		return cmds
+	}
+	step := sweep / float64(segments)
+	// 控制点到端点的距离
+	k := float32(4.0 / 3.0 * math.Tan(step*math.Pi/180/4))
+	for i := 0; i < segments; i++ {
+		a0 := float64(start) + step*float64(i)
+		a1 := a0 + step
+		p0, p3 := at(a0), at(a1)
+		// 切线方向为半径方向旋转90度
+		t0 := p0.Sub(center)
+		t3 := p3.Sub(center)
+		c1 := p0.Add(f32.Pt(-t0.Y, t0.X).Mul(k))
+		c2 := p3.Sub(f32.Pt(-t3.Y, t3.X).Mul(k))
+		cmds = append(cmds, canvasCmd{kind: canvasCubic, pts: [3]f32.Point{c1, c2, p3}})
+	}
+	return cmds
+}
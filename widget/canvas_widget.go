@@ -0,0 +1,331 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/Seikaijyu/gio/f32"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &Canvas{}
+
+// 画布指针事件的类型
+type CanvasEventKind uint8
+
+const (
+	// 按下
+	CanvasPress CanvasEventKind = iota
+	// 松开
+	CanvasRelease
+	// 没有按下时移动
+	CanvasMove
+	// 按下后拖动
+	CanvasDrag
+	// 进入画布
+	CanvasEnter
+	// 离开画布
+	CanvasLeave
+	// 滚动，只有开启CaptureScroll时才会收到
+	CanvasScroll
+	// 按下的操作被系统或其他组件取消
+	CanvasCancel
+)
+
+// 画布上的指针事件，坐标相对于画布的左上角，单位为dp
+type CanvasEvent struct {
+	// 事件类型
+	Kind CanvasEventKind
+	// 指针的横坐标
+	X float32
+	// 指针的纵坐标
+	Y float32
+	// 横向滚动距离
+	ScrollX float32
+	// 纵向滚动距离
+	ScrollY float32
+	// 是否按下了主按键，通常为鼠标左键或触摸
+	Primary bool
+	// 是否按下了次按键，通常为鼠标右键
+	Secondary bool
+	// 是否按下了中键
+	Tertiary bool
+	// 是否为触摸产生的事件
+	Touch bool
+}
+
+// 指针事件类型和画布事件类型的对应关系
+var canvasEventKinds = map[pointer.Kind]CanvasEventKind{
+	pointer.Press:   CanvasPress,
+	pointer.Release: CanvasRelease,
+	pointer.Move:    CanvasMove,
+	pointer.Drag:    CanvasDrag,
+	pointer.Enter:   CanvasEnter,
+	pointer.Leave:   CanvasLeave,
+	pointer.Scroll:  CanvasScroll,
+	pointer.Cancel:  CanvasCancel,
+}
+
+type canvasConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 重绘事件
+	_paint func(*Canvas, *CanvasContext, float32, float32)
+	// 指针事件
+	_pointer func(*Canvas, CanvasEvent)
+	// 是否接收滚动事件，接收时父组件不会再滚动
+	scroll bool
+	// 是否需要重绘
+	dirty bool
+}
+
+// 画布，用于绘制自定义的图形
+//
+// 绘制内容在OnPaint注册的重绘函数中生成并被保留，只有画布大小、屏幕缩放或主题改变，
+// 或者调用Repaint后才会重新调用重绘函数
+type Canvas struct {
+	// 组件标识
+	identity
+	// 配置
+	config *canvasConfig
+	// 外边距
+	margin *glayout.Inset
+	// 宽度，0表示占满可用宽度
+	width float32
+	// 高度，0表示占满可用高度
+	height float32
+	// 背景颜色
+	background color.NRGBA
+	// 保留的绘制内容
+	ops op.Ops
+	// 调用绘制内容的操作
+	call op.CallOp
+	// 上次重绘时的像素大小
+	size image.Point
+	// 上次重绘时的屏幕缩放
+	pxPerDp float32
+	// 绘制过的图像，多次重绘之间复用
+	images map[image.Image]paint.ImageOp
+}
+
+// 绑定函数
+func (p *Canvas) Then(fn func(self *Canvas)) *Canvas {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *Canvas) ID(id string) *Canvas {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Canvas) Class(classes ...string) *Canvas {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Canvas) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *Canvas) Destroy() {
+	p.config.update = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *Canvas) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 是否更新组件
+func (p *Canvas) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不接收指针事件
+func (p *Canvas) Disabled(disabled bool) *Canvas {
+	p.config.disabled = disabled
+	return p
+}
+
+// 外边距
+func (p *Canvas) Margin(Top, Left, Bottom, Right float32) *Canvas {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *Canvas) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 画布大小，宽度或高度为0时占满可用的空间
+func (p *Canvas) Size(width, height float32) *Canvas {
+	p.width = width
+	p.height = height
+	return p
+}
+
+// 背景颜色，默认透明
+func (p *Canvas) Background(r, g, b, a uint8) *Canvas {
+	p.background = color.NRGBA{R: r, G: g, B: b, A: a}
+	return p
+}
+
+// 获取背景颜色
+func (p *Canvas) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.background)
+}
+
+// 是否接收滚动事件，接收时鼠标在画布上滚动不会再滚动父组件
+func (p *Canvas) CaptureScroll(capture bool) *Canvas {
+	p.config.scroll = capture
+	return p
+}
+
+// 注册重绘事件，width和height为画布的大小，单位为dp
+//
+// 每次重绘都从空白的画布开始，重绘函数中绘制的内容会一直显示到下一次重绘
+func (p *Canvas) OnPaint(fn func(p *Canvas, dc *CanvasContext, width, height float32)) *Canvas {
+	p.config._paint = fn
+	p.config.dirty = true
+	return p
+}
+
+// 注册指针事件，坐标相对于画布的左上角
+func (p *Canvas) OnPointer(fn func(p *Canvas, e CanvasEvent)) *Canvas {
+	p.config._pointer = fn
+	return p
+}
+
+// 在下一次渲染时重新调用重绘函数，数据改变后调用
+func (p *Canvas) Repaint() *Canvas {
+	p.config.dirty = true
+	return p
+}
+
+// 获取画布上次重绘时的大小，单位为dp
+func (p *Canvas) GetSize() (float32, float32) {
+	if p.pxPerDp == 0 {
+		return 0, 0
+	}
+	return float32(p.size.X) / p.pxPerDp, float32(p.size.Y) / p.pxPerDp
+}
+
+// 处理指针事件
+func (p *Canvas) events(gtx glayout.Context) {
+	for _, e := range gtx.Events(p) {
+		e, ok := e.(pointer.Event)
+		if !ok || p.config._pointer == nil {
+			continue
+		}
+		kind, ok := canvasEventKinds[e.Kind]
+		if !ok {
+			continue
+		}
+		scale := 1 / gtx.Metric.PxPerDp
+		p.config._pointer(p, CanvasEvent{
+			Kind:      kind,
+			X:         e.Position.X * scale,
+			Y:         e.Position.Y * scale,
+			ScrollX:   e.Scroll.X * scale,
+			ScrollY:   e.Scroll.Y * scale,
+			Primary:   e.Buttons.Contain(pointer.ButtonPrimary) || e.Source == pointer.Touch,
+			Secondary: e.Buttons.Contain(pointer.ButtonSecondary),
+			Tertiary:  e.Buttons.Contain(pointer.ButtonTertiary),
+			Touch:     e.Source == pointer.Touch,
+		})
+	}
+}
+
+// 重新调用重绘函数，记录新的绘制内容
+func (p *Canvas) repaint(gtx glayout.Context, size image.Point) {
+	p.size = size
+	p.pxPerDp = gtx.Metric.PxPerDp
+	p.config.dirty = false
+	p.ops.Reset()
+	macro := op.Record(&p.ops)
+	if p.config._paint != nil {
+		dc := newCanvasContext(&p.ops, gtx, p.images)
+		p.config._paint(p, dc, float32(size.X)/p.pxPerDp, float32(size.Y)/p.pxPerDp)
+		p.images = dc.finish()
+	}
+	p.call = macro.Stop()
+}
+
+// 渲染组件
+func (p *Canvas) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 绘制内容可能使用了主题颜色，主题改变时重绘
+	if _, ok := p.config.themed.changed(); ok {
+		p.config.dirty = true
+	}
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		size := gtx.Constraints.Max
+		if p.width > 0 {
+			size.X = gtx.Dp(gunit.Dp(p.width))
+		}
+		if p.height > 0 {
+			size.Y = gtx.Dp(gunit.Dp(p.height))
+		}
+		size = gtx.Constraints.Constrain(size)
+		// 先处理事件，事件中调用Repaint可以在同一帧生效
+		p.events(gtx)
+		if p.config.dirty || size != p.size || gtx.Metric.PxPerDp != p.pxPerDp {
+			p.repaint(gtx, size)
+		}
+		defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+		if p.background.A > 0 {
+			paint.ColorOp{Color: p.background}.Add(gtx.Ops)
+			paint.PaintOp{}.Add(gtx.Ops)
+		}
+		scale := op.Affine(f32.Affine2D{}.Scale(f32.Point{}, f32.Pt(gtx.Metric.PxPerDp, gtx.Metric.PxPerDp))).Push(gtx.Ops)
+		p.call.Add(gtx.Ops)
+		scale.Pop()
+		kinds := pointer.Press | pointer.Release | pointer.Move | pointer.Drag | pointer.Enter | pointer.Leave | pointer.Cancel
+		input := pointer.InputOp{Tag: p, Kinds: kinds}
+		if p.config.scroll {
+			input.Kinds |= pointer.Scroll
+			input.ScrollBounds = image.Rect(-1<<30, -1<<30, 1<<30, 1<<30)
+		}
+		input.Add(gtx.Ops)
+		return glayout.Dimensions{Size: size}
+	})
+}
+
+// 创建画布，默认占满可用的空间
+func NewCanvas() *Canvas {
+	return &Canvas{
+		margin: &glayout.Inset{},
+		config: &canvasConfig{update: true, themed: newThemed(), dirty: true},
+		images: map[image.Image]paint.ImageOp{},
+	}
+}
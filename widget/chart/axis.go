@@ -0,0 +1,249 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+)
+
+// 坐标轴
+type axis struct {
+	// 显示范围
+	lo, hi float64
+	// 刻度位置
+	ticks []float64
+	// 刻度标签，与ticks对应
+	labels []string
+}
+
+// 把数值映射到from和to之间的屏幕坐标
+func (a *axis) at(v float64, from, to float32) float32 {
+	if a.hi == a.lo {
+		return from
+	}
+	return from + float32((v-a.lo)/(a.hi-a.lo))*(to-from)
+}
+
+// 把屏幕坐标映射回数值
+func (a *axis) value(pos, from, to float32) float64 {
+	if from == to {
+		return a.lo
+	}
+	return a.lo + float64((pos-from)/(to-from))*(a.hi-a.lo)
+}
+
+// 直角坐标系的绘图区和坐标轴，绘制数据时计算，查找悬浮数据时使用
+type plotArea struct {
+	rect
+	// 横轴
+	x axis
+	// 纵轴
+	y axis
+}
+
+// 数值对应的横坐标
+func (p *plotArea) px(v float64) float32 {
+	return p.x.at(v, p.rect.x, p.rect.x+p.w)
+}
+
+// 数值对应的纵坐标
+func (p *plotArea) py(v float64) float32 {
+	return p.y.at(v, p.rect.y+p.h, p.rect.y)
+}
+
+// 绘图区是否可用
+func (p *plotArea) valid() bool {
+	return p.w > 0 && p.h > 0
+}
+
+// 刻度间隔，取1、2、5乘以10的幂中最接近的一个，使范围内大约有count个间隔
+func niceStep(span float64, count int) float64 {
+	if span <= 0 || !finite(span) || count < 1 {
+		return 1
+	}
+	raw := span / float64(count)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch f := raw / mag; {
+	case f < 1.5:
+		return mag
+	case f < 3:
+		return 2 * mag
+	case f < 7:
+		return 5 * mag
+	}
+	return 10 * mag
+}
+
+// 范围为空时向两边扩展
+func padRange(lo, hi float64) (float64, float64) {
+	if lo < hi {
+		return lo, hi
+	}
+	pad := math.Abs(lo) * 0.1
+	if pad == 0 {
+		pad = 1
+	}
+	return lo - pad, hi + pad
+}
+
+// 生成坐标轴，nice为true时把范围扩展到刻度上
+func newAxis(lo, hi float64, count int, nice bool, format func(float64) string) axis {
+	lo, hi = padRange(lo, hi)
+	step := niceStep(hi-lo, count)
+	if nice {
+		lo, hi = math.Floor(lo/step)*step, math.Ceil(hi/step)*step
+	}
+	if format == nil {
+		// 小数位数和刻度间隔一致
+		digits := int(math.Max(0, -math.Floor(math.Log10(step)+1e-9)))
+		format = func(v float64) string {
+			return strconv.FormatFloat(v, 'f', digits, 64)
+		}
+	}
+	a := axis{lo: lo, hi: hi}
+	first := math.Ceil(lo/step - 1e-9)
+	for i := first; i*step <= hi+step*1e-9 && len(a.ticks) < 1000; i++ {
+		v := i * step
+		// 避免显示-0
+		if math.Abs(v) < step*1e-9 {
+			v = 0
+		}
+		a.ticks = append(a.ticks, v)
+		a.labels = append(a.labels, format(v))
+	}
+	return a
+}
+
+// 分类坐标轴，第i个分类位于i到i+1之间
+func newCategoryAxis(categories []string) axis {
+	a := axis{lo: 0, hi: float64(len(categories)), labels: categories}
+	for i := range categories {
+		a.ticks = append(a.ticks, float64(i)+0.5)
+	}
+	if len(categories) == 0 {
+		a.hi = 1
+	}
+	return a
+}
+
+// 直角坐标系图表的公共部分，包括纵轴的设置和坐标轴的绘制
+type cartesian[T any] struct {
+	base[T]
+	// 纵轴刻度和提示的格式，为nil时使用默认格式
+	yFormat func(float64) string
+	// 手动设置的纵轴范围
+	yMin, yMax float64
+	// 是否手动设置了纵轴范围
+	yFixed bool
+	// 是否显示网格
+	grid bool
+}
+
+// 纵轴刻度标签和悬浮提示中数值的格式
+func (p *cartesian[T]) YFormat(format func(v float64) string) T {
+	p.yFormat = format
+	p.plot.Repaint()
+	return p.self
+}
+
+// 固定纵轴的范围，min不小于max时根据数据自动计算
+func (p *cartesian[T]) YRange(min, max float64) T {
+	p.yMin, p.yMax, p.yFixed = min, max, min < max
+	p.plot.Repaint()
+	return p.self
+}
+
+// 是否显示网格，默认显示
+func (p *cartesian[T]) Grid(show bool) T {
+	p.grid = show
+	p.plot.Repaint()
+	return p.self
+}
+
+// 格式化纵轴的数值
+func (p *cartesian[T]) formatY(v float64) string {
+	if p.yFormat != nil {
+		return p.yFormat(v)
+	}
+	return formatValue(v)
+}
+
+// 根据数据范围生成纵轴，height为绘图区的大致高度
+func (p *cartesian[T]) yAxis(lo, hi float64, height float32) axis {
+	count := maxInt(int(height/40), 2)
+	if p.yFixed {
+		return newAxis(p.yMin, p.yMax, count, false, p.yFormat)
+	}
+	return newAxis(lo, hi, count, true, p.yFormat)
+}
+
+// 在区域中绘制坐标轴、网格和刻度标签，返回绘图区
+//
+// makeY和makeX根据绘图区的大致大小生成坐标轴，band为true时横轴为分类轴，不绘制竖直的网格线
+func (p *cartesian[T]) paintAxes(dc *widget.CanvasContext, c *colors, area rect, makeX, makeY func(length float32) axis, band bool) plotArea {
+	dc.Save().FontSize(p.textSize()).LineWidth(1)
+	defer dc.Restore()
+	_, lineHeight := dc.MeasureText("0")
+	g := plotArea{y: makeY(area.h - 2*lineHeight)}
+	labelWidth := float32(0)
+	for _, label := range g.y.labels {
+		w, _ := dc.MeasureText(label)
+		labelWidth = float32(math.Max(float64(labelWidth), float64(w)))
+	}
+	g.rect = rect{x: area.x + labelWidth + 6, y: area.y + lineHeight/2}
+	g.h = area.h - lineHeight/2 - lineHeight - 4
+	g.x = makeX(area.w - labelWidth - 6)
+	// 为最后一个横轴标签留出一半的宽度
+	right := float32(4)
+	if !band && len(g.x.labels) > 0 {
+		w, _ := dc.MeasureText(g.x.labels[len(g.x.labels)-1])
+		right = float32(math.Max(float64(right), float64(w/2)))
+	}
+	g.w = area.x + area.w - right - g.rect.x
+	if !g.valid() {
+		return g
+	}
+	bottom := g.rect.y + g.h
+	if p.grid {
+		stroke(dc, c.grid)
+		for _, v := range g.y.ticks {
+			y := g.py(v)
+			dc.Line(g.rect.x, y, g.rect.x+g.w, y)
+		}
+		if !band {
+			for _, v := range g.x.ticks {
+				x := g.px(v)
+				dc.Line(x, g.rect.y, x, bottom)
+			}
+		}
+	}
+	stroke(dc, c.axis).Line(g.rect.x, g.rect.y, g.rect.x, bottom).Line(g.rect.x, bottom, g.rect.x+g.w, bottom)
+	fill(dc, c.secondary)
+	dc.TextAlign(text.End)
+	for i, v := range g.y.ticks {
+		dc.Text(g.rect.x-6, g.py(v)-lineHeight/2, g.y.labels[i])
+	}
+	// 横轴标签重叠时跳过
+	dc.TextAlign(text.Middle)
+	last := float32(math.Inf(-1))
+	for i, v := range g.x.ticks {
+		x := g.px(v)
+		w, _ := dc.MeasureText(g.x.labels[i])
+		if x-w/2 < last+4 {
+			continue
+		}
+		dc.Text(x, bottom+4, g.x.labels[i])
+		last = x + w/2
+	}
+	return g
+}
+
+// 返回两个数中较大的一个
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
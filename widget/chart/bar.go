@@ -0,0 +1,284 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 校验接口是否实现
+var _ widget.WidgetInterface = &BarChart{}
+
+// 柱状图的系列
+type barSeries struct {
+	// 名称
+	name string
+	// 颜色，为nil时使用图表的系列颜色
+	color *color.NRGBA
+}
+
+// 柱状图，横轴为分类，每个分类中每个系列一根柱子，默认并排显示，开启Stacked后堆叠显示
+//
+// 所有修改数据的方法都可以在任意goroutine中调用
+type BarChart struct {
+	cartesian[*BarChart]
+	// 系列
+	series []barSeries
+	// 分类名称
+	categories []string
+	// 每个分类中每个系列的值
+	values [][]float64
+	// 最多保留的分类数量，0表示不限制
+	max int
+	// 是否堆叠显示
+	stacked bool
+	// 上次绘制时的绘图区
+	geom plotArea
+}
+
+// 修改数据，fn在写锁中执行
+func (p *BarChart) modify(fn func()) *BarChart {
+	p.data.mu.Lock()
+	fn()
+	p.data.mu.Unlock()
+	p.data.changed()
+	return p
+}
+
+// 设置系列的名称，名称显示在图例和提示中
+func (p *BarChart) Series(names ...string) *BarChart {
+	return p.modify(func() {
+		series := make([]barSeries, len(names))
+		for i, name := range names {
+			series[i].name = name
+			if i < len(p.series) {
+				series[i].color = p.series[i].color
+			}
+		}
+		p.series = series
+	})
+}
+
+// 设置系列的颜色，默认使用图表的系列颜色
+func (p *BarChart) SeriesColor(index int, r, g, b, a uint8) *BarChart {
+	return p.modify(func() {
+		for len(p.series) <= index {
+			p.series = append(p.series, barSeries{})
+		}
+		p.series[index].color = &color.NRGBA{R: r, G: g, B: b, A: a}
+	})
+}
+
+// 设置分类中每个系列的值，分类不存在时追加到最后，超过MaxCategories时丢弃最早的分类
+func (p *BarChart) Category(label string, values ...float64) *BarChart {
+	return p.modify(func() {
+		values = append([]float64(nil), values...)
+		for i, category := range p.categories {
+			if category == label {
+				p.values[i] = values
+				return
+			}
+		}
+		p.categories = append(p.categories, label)
+		p.values = append(p.values, values)
+		p.trim()
+	})
+}
+
+// 移除分类
+func (p *BarChart) RemoveCategory(label string) *BarChart {
+	return p.modify(func() {
+		for i, category := range p.categories {
+			if category == label {
+				p.categories = append(p.categories[:i], p.categories[i+1:]...)
+				p.values = append(p.values[:i], p.values[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// 清空所有分类
+func (p *BarChart) Clear() *BarChart {
+	return p.modify(func() {
+		p.categories = nil
+		p.values = nil
+	})
+}
+
+// 最多保留的分类数量，用于滚动显示的实时数据，0表示不限制
+func (p *BarChart) MaxCategories(max int) *BarChart {
+	return p.modify(func() {
+		p.max = maxInt(max, 0)
+		p.trim()
+	})
+}
+
+// 是否堆叠显示，堆叠时正数向上、负数向下分别累加
+func (p *BarChart) Stacked(stacked bool) *BarChart {
+	p.stacked = stacked
+	p.plot.Repaint()
+	return p
+}
+
+// 获取所有分类的名称
+func (p *BarChart) GetCategories() []string {
+	p.data.mu.RLock()
+	defer p.data.mu.RUnlock()
+	return append([]string(nil), p.categories...)
+}
+
+// 丢弃超出数量的分类
+func (p *BarChart) trim() {
+	if p.max > 0 && len(p.categories) > p.max {
+		n := len(p.categories) - p.max
+		p.categories = append(p.categories[:0], p.categories[n:]...)
+		p.values = append(p.values[:0], p.values[n:]...)
+	}
+}
+
+// 系列的数量，取设置的系列和数据中较多的一个
+func (p *BarChart) seriesCount() int {
+	n := len(p.series)
+	for _, values := range p.values {
+		n = maxInt(n, len(values))
+	}
+	return n
+}
+
+// 第i个系列的名称和颜色
+func (p *BarChart) seriesAt(c *colors, i int) (string, color.NRGBA) {
+	if i < len(p.series) {
+		if p.series[i].color != nil {
+			return p.series[i].name, *p.series[i].color
+		}
+		return p.series[i].name, c.series(i)
+	}
+	return "", c.series(i)
+}
+
+// 分类中第i个系列的值，没有设置或无法绘制时为0
+func valueAt(values []float64, i int) float64 {
+	if i < len(values) && finite(values[i]) {
+		return values[i]
+	}
+	return 0
+}
+
+// 数据的范围，总是包含0
+func (p *BarChart) bounds() (float64, float64) {
+	lo, hi := 0.0, 0.0
+	for _, values := range p.values {
+		pos, neg := 0.0, 0.0
+		for i := range values {
+			v := valueAt(values, i)
+			if !p.stacked {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			} else if v > 0 {
+				pos += v
+			} else {
+				neg += v
+			}
+		}
+		lo, hi = math.Min(lo, neg), math.Max(hi, pos)
+	}
+	return lo, hi
+}
+
+// 依次回调分类中每根柱子的系列下标、左边、宽度，以及底部和顶部的值
+func (p *BarChart) bars(g *plotArea, category int, fn func(i int, x, w float32, from, to float64)) {
+	n := p.seriesCount()
+	if n == 0 {
+		return
+	}
+	band := g.w / float32(len(p.categories))
+	left := g.px(float64(category))
+	values := p.values[category]
+	if p.stacked {
+		w := band * 0.6
+		pos, neg := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			v := valueAt(values, i)
+			if v >= 0 {
+				fn(i, left+(band-w)/2, w, pos, pos+v)
+				pos += v
+			} else {
+				fn(i, left+(band-w)/2, w, neg, neg+v)
+				neg += v
+			}
+		}
+		return
+	}
+	group := band * 0.8
+	w := group / float32(n)
+	// 柱子之间留出间隔，柱子太窄时不留
+	gap := float32(math.Min(2, float64(w)/4))
+	for i := 0; i < n; i++ {
+		fn(i, left+(band-group)/2+float32(i)*w+gap/2, w-gap, 0, valueAt(values, i))
+	}
+}
+
+// 绘制数据
+func (p *BarChart) paintPlot(dc *widget.CanvasContext, c *colors, width, height float32) {
+	entries := make([]legendEntry, p.seriesCount())
+	for i := range entries {
+		entries[i].name, entries[i].color = p.seriesAt(c, i)
+	}
+	area := p.paintFrame(dc, c, width, height, entries)
+	lo, hi := p.bounds()
+	p.geom = p.paintAxes(dc, c, area, func(float32) axis {
+		return newCategoryAxis(p.categories)
+	}, func(length float32) axis {
+		return p.yAxis(lo, hi, length)
+	}, true)
+	g := &p.geom
+	if !g.valid() || len(p.categories) == 0 {
+		return
+	}
+	dc.Save().ClipRect(g.rect.x, g.rect.y, g.w, g.h)
+	defer dc.Restore()
+	for category := range p.categories {
+		p.bars(g, category, func(i int, x, w float32, from, to float64) {
+			_, col := p.seriesAt(c, i)
+			y0, y1 := g.py(from), g.py(to)
+			fill(dc, col).FillRect(x, float32(math.Min(float64(y0), float64(y1))), w, float32(math.Abs(float64(y1-y0))))
+		})
+	}
+}
+
+// 高亮指针所在的分类，提示中显示分类中每个系列的值
+func (p *BarChart) lookup(dc *widget.CanvasContext, c *colors, x, y float32) (*tip, bool) {
+	g := &p.geom
+	if !g.valid() || !g.contains(x, y) || len(p.categories) == 0 {
+		return nil, false
+	}
+	category := int(g.x.value(x, g.rect.x, g.rect.x+g.w))
+	if category < 0 || category >= len(p.categories) {
+		return nil, false
+	}
+	band := g.w / float32(len(p.categories))
+	fill(dc, c.grid).FillRect(g.px(float64(category)), g.rect.y, band, g.h)
+	t := &tip{title: p.categories[category]}
+	total := 0.0
+	for i := 0; i < p.seriesCount(); i++ {
+		name, col := p.seriesAt(c, i)
+		v := valueAt(p.values[category], i)
+		total += v
+		t.rows = append(t.rows, tipRow{color: col, text: rowText(name, p.formatY(v))})
+	}
+	if p.stacked && len(t.rows) > 1 {
+		t.rows = append(t.rows, tipRow{text: rowText("合计", p.formatY(total))})
+	}
+	return t, true
+}
+
+// 创建柱状图
+func NewBarChart() *BarChart {
+	p := &BarChart{}
+	p.init(p)
+	p.grid = true
+	p.paintData = p.paintPlot
+	p.hit = p.lookup
+	return p
+}
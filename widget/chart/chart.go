@@ -0,0 +1,560 @@
+// 图表组件
+// 基于Canvas绘制折线图、面积图、柱状图、饼图和散点图，数据可以在后台goroutine中追加
+package chart
+
+import (
+	"image/color"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/setter"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	glayout "github.com/Seikaijyu/gio/layout"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 图例位置
+type LegendPosition uint8
+
+const (
+	// 图表上方
+	LegendTop LegendPosition = iota
+	// 图表下方
+	LegendBottom
+	// 图表右侧
+	LegendRight
+	// 不显示图例
+	LegendNone
+)
+
+// 描述文件和样式表中可以使用top、bottom、right和none设置图例位置
+func init() {
+	setter.RegisterEnum(reflect.TypeOf(LegendTop), map[string]int64{
+		"top": int64(LegendTop), "bottom": int64(LegendBottom), "right": int64(LegendRight), "none": int64(LegendNone),
+	})
+}
+
+// 注册描述文件和样式表中可以使用的属性，AddSeries、Category、Slice等添加数据的函数不能作为属性使用
+func init() {
+	common := []string{"Size", "Title", "Legend", "Tooltip", "Palette", "Background", "FontColor", "FontSize", "GridColor", "Margin"}
+	axes := []string{"Grid", "XRange", "YRange", "XFormat", "YFormat"}
+	setter.RegisterProperties(&LineChart{}, append(append(common, axes...), "Area", "LineWidth", "ShowPoints")...)
+	setter.RegisterProperties(&ScatterChart{}, append(append(common, axes...), "PointSize")...)
+	setter.RegisterProperties(&BarChart{}, append(common, "Grid", "YRange", "YFormat", "Series", "SeriesColor", "Stacked", "MaxCategories")...)
+	setter.RegisterProperties(&PieChart{}, append(common, "Donut", "Labels", "Format")...)
+}
+
+// 主色之后使用的默认系列颜色
+var defaultPalette = []color.NRGBA{
+	{R: 0xf5, G: 0x7c, B: 0x00, A: 0xff},
+	{R: 0x43, G: 0xa0, B: 0x47, A: 0xff},
+	{R: 0xe5, G: 0x39, B: 0x35, A: 0xff},
+	{R: 0x8e, G: 0x24, B: 0xaa, A: 0xff},
+	{R: 0x00, G: 0xac, B: 0xc1, A: 0xff},
+	{R: 0xfd, G: 0xd8, B: 0x35, A: 0xff},
+	{R: 0x6d, G: 0x4c, B: 0x41, A: 0xff},
+	{R: 0xd8, G: 0x1b, B: 0x60, A: 0xff},
+	{R: 0x75, G: 0x75, B: 0x75, A: 0xff},
+}
+
+// 图表数据，由图表和它的系列共用，所有读写都需要加锁
+type store struct {
+	// 数据锁，后台goroutine写入时加写锁，绘制时加读锁
+	mu sync.RWMutex
+	// 数据版本，每次修改后增加
+	version atomic.Uint64
+}
+
+// 标记数据已经修改
+func (p *store) changed() {
+	p.version.Add(1)
+	widget.RequestRedraw()
+}
+
+// 绘制时使用的颜色
+type colors struct {
+	// 文字颜色
+	text color.NRGBA
+	// 次要文字颜色，用于坐标轴标签
+	secondary color.NRGBA
+	// 坐标轴颜色
+	axis color.NRGBA
+	// 网格颜色
+	grid color.NRGBA
+	// 提示框背景颜色
+	surface color.NRGBA
+	// 系列颜色
+	palette []color.NRGBA
+}
+
+// 图表的公共部分，T为具体的图表类型，设置函数返回T以便链式调用
+type base[T any] struct {
+	// 具体的图表
+	self T
+	// 组件ID
+	id string
+	// 组件类名
+	classes []string
+	// 父节点
+	parent widget.WidgetInterface
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 外边距
+	margin *glayout.Inset
+	// 绘制数据的画布，只在数据、大小或主题改变时重绘
+	plot *widget.Canvas
+	// 绘制悬浮提示的画布，指针移动时重绘
+	overlay *widget.Canvas
+	// 数据
+	data *store
+	// 上次绘制时的数据版本
+	painted uint64
+	// 标题
+	title string
+	// 图例位置
+	legend LegendPosition
+	// 是否显示悬浮提示
+	tooltip bool
+	// 字体大小，0表示使用主题的说明文字字号
+	fontSize float32
+	// 手动设置的文字颜色
+	fontColor *color.NRGBA
+	// 手动设置的网格颜色
+	gridColor *color.NRGBA
+	// 手动设置的系列颜色
+	palette []color.NRGBA
+	// 指针是否在图表上
+	hovering bool
+	// 指针位置，单位为dp
+	hoverX, hoverY float32
+	// 绘制数据，由具体的图表实现
+	paintData func(dc *widget.CanvasContext, c *colors, width, height float32)
+	// 查找指针附近的数据，返回提示内容和标记位置，由具体的图表实现
+	hit func(dc *widget.CanvasContext, c *colors, x, y float32) (*tip, bool)
+}
+
+// 初始化公共部分
+func (p *base[T]) init(self T) {
+	p.self = self
+	p.update = true
+	p.tooltip = true
+	p.margin = &glayout.Inset{}
+	p.data = &store{}
+	p.plot = widget.NewCanvas().OnPaint(func(_ *widget.Canvas, dc *widget.CanvasContext, width, height float32) {
+		p.data.mu.RLock()
+		defer p.data.mu.RUnlock()
+		if p.paintData != nil {
+			p.paintData(dc, p.colors(), width, height)
+		}
+	})
+	p.overlay = widget.NewCanvas().OnPaint(func(_ *widget.Canvas, dc *widget.CanvasContext, width, height float32) {
+		if !p.hovering || !p.tooltip || p.hit == nil {
+			return
+		}
+		c := p.colors()
+		p.data.mu.RLock()
+		t, ok := p.hit(dc, c, p.hoverX, p.hoverY)
+		p.data.mu.RUnlock()
+		if ok {
+			p.paintTip(dc, c, t, width, height)
+		}
+	}).OnPointer(func(_ *widget.Canvas, e widget.CanvasEvent) {
+		switch e.Kind {
+		case widget.CanvasMove, widget.CanvasDrag, widget.CanvasEnter, widget.CanvasPress:
+			p.hovering, p.hoverX, p.hoverY = true, e.X, e.Y
+		case widget.CanvasLeave, widget.CanvasCancel:
+			p.hovering = false
+		default:
+			return
+		}
+		p.overlay.Repaint()
+	})
+}
+
+// 绑定函数
+func (p *base[T]) Then(fn func(self T)) T {
+	fn(p.self)
+	return p.self
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *base[T]) ID(id string) T {
+	p.id = id
+	return p.self
+}
+
+// 添加组件类名
+func (p *base[T]) Class(classes ...string) T {
+	p.classes = append(p.classes, classes...)
+	return p.self
+}
+
+// 获取组件ID
+func (p *base[T]) GetID() string {
+	return p.id
+}
+
+// 获取组件类名
+func (p *base[T]) GetClasses() []string {
+	return p.classes
+}
+
+// 获取父节点，不存在时返回nil
+func (p *base[T]) GetParent() widget.WidgetInterface {
+	return p.parent
+}
+
+// 设置父节点，由父节点的ResetParent调用
+func (p *base[T]) SetParent(parent widget.WidgetInterface) {
+	p.parent = parent
+}
+
+// 注销自身，清理所有引用
+func (p *base[T]) Destroy() {
+	p.update = false
+	if p._destroy != nil {
+		p._destroy()
+	}
+	p._destroy = nil
+}
+
+// 注册删除事件
+func (p *base[T]) OnDestroy(fn func()) {
+	p._destroy = fn
+}
+
+// 是否更新组件
+func (p *base[T]) Update(update bool) {
+	p.update = update
+}
+
+// 外边距
+func (p *base[T]) Margin(Top, Left, Bottom, Right float32) T {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p.self
+}
+
+// 图表大小，宽度或高度为0时占满可用的空间
+func (p *base[T]) Size(width, height float32) T {
+	p.plot.Size(width, height)
+	return p.self
+}
+
+// 背景颜色，默认透明
+func (p *base[T]) Background(r, g, b, a uint8) T {
+	p.plot.Background(r, g, b, a)
+	return p.self
+}
+
+// 标题，为空时不显示
+func (p *base[T]) Title(title string) T {
+	p.title = title
+	p.plot.Repaint()
+	return p.self
+}
+
+// 图例位置，默认在图表上方
+func (p *base[T]) Legend(position LegendPosition) T {
+	p.legend = position
+	p.plot.Repaint()
+	return p.self
+}
+
+// 是否在指针悬浮时显示最近数据的提示，默认显示
+func (p *base[T]) Tooltip(show bool) T {
+	p.tooltip = show
+	p.overlay.Repaint()
+	return p.self
+}
+
+// 坐标轴标签、图例和提示的字体大小，默认使用主题的说明文字字号
+func (p *base[T]) FontSize(size float32) T {
+	p.fontSize = size
+	p.plot.Repaint()
+	return p.self
+}
+
+// 文字颜色，默认使用主题的文字颜色
+func (p *base[T]) FontColor(r, g, b, a uint8) T {
+	p.fontColor = &color.NRGBA{R: r, G: g, B: b, A: a}
+	p.plot.Repaint()
+	return p.self
+}
+
+// 网格和坐标轴的颜色，默认使用半透明的主题文字颜色
+func (p *base[T]) GridColor(r, g, b, a uint8) T {
+	p.gridColor = &color.NRGBA{R: r, G: g, B: b, A: a}
+	p.plot.Repaint()
+	return p.self
+}
+
+// 系列颜色，按系列的顺序循环使用，为空时使用主题主色和默认配色
+func (p *base[T]) Palette(colors ...color.NRGBA) T {
+	p.palette = colors
+	p.plot.Repaint()
+	return p.self
+}
+
+// 在下一次渲染时重绘图表
+func (p *base[T]) Repaint() T {
+	p.plot.Repaint()
+	p.overlay.Repaint()
+	return p.self
+}
+
+// 渲染组件
+func (p *base[T]) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.update {
+		return glayout.Dimensions{}
+	}
+	if version := p.data.version.Load(); version != p.painted {
+		p.painted = version
+		p.plot.Repaint()
+		p.overlay.Repaint()
+	}
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		dims := p.plot.Layout(gtx)
+		gtx.Constraints = glayout.Exact(dims.Size)
+		p.overlay.Layout(gtx)
+		return dims
+	})
+}
+
+// 当前使用的颜色，手动设置过的颜色优先
+func (p *base[T]) colors() *colors {
+	th := theme.Current()
+	c := &colors{
+		text:      th.Text,
+		secondary: th.TextSecondary,
+		axis:      withAlpha(th.Text, 0x66),
+		grid:      withAlpha(th.Text, 0x1f),
+		surface:   th.Background,
+		palette:   p.palette,
+	}
+	if p.fontColor != nil {
+		c.text = *p.fontColor
+		c.secondary = withAlpha(*p.fontColor, 0xbb)
+	}
+	if p.gridColor != nil {
+		c.axis = *p.gridColor
+		c.grid = withAlpha(*p.gridColor, 0x50)
+	}
+	if len(c.palette) == 0 {
+		c.palette = append([]color.NRGBA{th.Primary}, defaultPalette...)
+	}
+	return c
+}
+
+// 字体大小
+func (p *base[T]) textSize() float32 {
+	if p.fontSize > 0 {
+		return p.fontSize
+	}
+	return theme.Current().Caption
+}
+
+// 第i个系列的颜色
+func (c *colors) series(i int) color.NRGBA {
+	return c.palette[i%len(c.palette)]
+}
+
+// 设置填充颜色
+func fill(dc *widget.CanvasContext, c color.NRGBA) *widget.CanvasContext {
+	return dc.FillColor(c.R, c.G, c.B, c.A)
+}
+
+// 设置描边颜色
+func stroke(dc *widget.CanvasContext, c color.NRGBA) *widget.CanvasContext {
+	return dc.StrokeColor(c.R, c.G, c.B, c.A)
+}
+
+// 按比例调整颜色的透明度
+func withAlpha(c color.NRGBA, a uint8) color.NRGBA {
+	c.A = uint8(uint32(c.A) * uint32(a) / 0xff)
+	return c
+}
+
+// 矩形区域，单位为dp
+type rect struct {
+	x, y, w, h float32
+}
+
+// 是否包含某一点
+func (r rect) contains(x, y float32) bool {
+	return x >= r.x && x <= r.x+r.w && y >= r.y && y <= r.y+r.h
+}
+
+// 图例中的一项
+type legendEntry struct {
+	// 名称
+	name string
+	// 颜色
+	color color.NRGBA
+}
+
+// 悬浮提示的内容
+type tip struct {
+	// 标题
+	title string
+	// 每一行的内容
+	rows []tipRow
+}
+
+// 悬浮提示中的一行
+type tipRow struct {
+	// 色块颜色
+	color color.NRGBA
+	// 文字
+	text string
+}
+
+// 绘制标题和图例，返回剩余的绘图区域
+func (p *base[T]) paintFrame(dc *widget.CanvasContext, c *colors, width, height float32, entries []legendEntry) rect {
+	const padding = 8
+	area := rect{x: padding, y: padding, w: width - 2*padding, h: height - 2*padding}
+	size := p.textSize()
+	if p.title != "" {
+		dc.Save().FontSize(size * 4 / 3).FontWeight(text.Bold).TextAlign(text.Middle)
+		_, h := dc.MeasureText(p.title)
+		fill(dc, c.text).Text(width/2, area.y, p.title)
+		dc.Restore()
+		area.y += h + padding
+		area.h -= h + padding
+	}
+	if p.legend == LegendNone || len(entries) == 0 || (len(entries) == 1 && entries[0].name == "") {
+		return area
+	}
+	dc.Save().FontSize(size).TextAlign(text.Start)
+	defer dc.Restore()
+	_, lineHeight := dc.MeasureText("0")
+	swatch := lineHeight * 0.6
+	widths := make([]float32, len(entries))
+	for i, e := range entries {
+		w, _ := dc.MeasureText(e.name)
+		widths[i] = swatch + 4 + w
+	}
+	item := func(e legendEntry, x, y float32) {
+		fill(dc, e.color).FillRoundRect(x, y+(lineHeight-swatch)/2, swatch, swatch, 2)
+		fill(dc, c.text).Text(x+swatch+4, y, e.name)
+	}
+	if p.legend == LegendRight {
+		column := float32(0)
+		for _, w := range widths {
+			column = float32(math.Max(float64(column), float64(w)))
+		}
+		x := area.x + area.w - column
+		for i, e := range entries {
+			item(e, x, area.y+float32(i)*(lineHeight+4))
+		}
+		area.w -= column + 2*padding
+		return area
+	}
+	// 横向排列，超出宽度时换行，每行居中
+	const gap = 12
+	var rows [][]int
+	rowWidth := float32(0)
+	for i, w := range widths {
+		if len(rows) == 0 || rowWidth+gap+w > area.w {
+			rows = append(rows, nil)
+			rowWidth = -gap
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], i)
+		rowWidth += gap + w
+	}
+	total := float32(len(rows))*(lineHeight+4) + padding - 4
+	y := area.y
+	if p.legend == LegendBottom {
+		y = area.y + area.h - total + padding
+	} else {
+		area.y += total
+	}
+	area.h -= total
+	for _, row := range rows {
+		w := float32(-gap)
+		for _, i := range row {
+			w += gap + widths[i]
+		}
+		x := area.x + (area.w-w)/2
+		for _, i := range row {
+			item(entries[i], x, y)
+			x += widths[i] + gap
+		}
+		y += lineHeight + 4
+	}
+	return area
+}
+
+// 在指针附近绘制悬浮提示，超出画布时移到指针的另一侧
+func (p *base[T]) paintTip(dc *widget.CanvasContext, c *colors, t *tip, width, height float32) {
+	const padding = 6
+	dc.Save().FontSize(p.textSize()).TextAlign(text.Start)
+	defer dc.Restore()
+	_, lineHeight := dc.MeasureText("0")
+	swatch := lineHeight * 0.6
+	boxWidth, boxHeight := float32(0), float32(0)
+	if t.title != "" {
+		dc.FontWeight(text.Bold)
+		boxWidth, _ = dc.MeasureText(t.title)
+		dc.FontWeight(text.Normal)
+		boxHeight = lineHeight
+	}
+	for _, row := range t.rows {
+		w, _ := dc.MeasureText(row.text)
+		boxWidth = float32(math.Max(float64(boxWidth), float64(swatch+4+w)))
+		boxHeight += lineHeight
+	}
+	boxWidth += 2 * padding
+	boxHeight += 2 * padding
+	x, y := p.hoverX+12, p.hoverY+12
+	if x+boxWidth > width {
+		x = p.hoverX - 12 - boxWidth
+	}
+	if y+boxHeight > height {
+		y = p.hoverY - 12 - boxHeight
+	}
+	x, y = float32(math.Max(float64(x), 0)), float32(math.Max(float64(y), 0))
+	fill(dc, withAlpha(c.surface, 0xf0)).FillRoundRect(x, y, boxWidth, boxHeight, 4)
+	stroke(dc, c.axis).LineWidth(1).StrokeRoundRect(x, y, boxWidth, boxHeight, 4)
+	x, y = x+padding, y+padding
+	if t.title != "" {
+		dc.FontWeight(text.Bold)
+		fill(dc, c.text).Text(x, y, t.title)
+		dc.FontWeight(text.Normal)
+		y += lineHeight
+	}
+	for _, row := range t.rows {
+		fill(dc, row.color).FillRoundRect(x, y+(lineHeight-swatch)/2, swatch, swatch, 2)
+		fill(dc, c.text).Text(x+swatch+4, y, row.text)
+		y += lineHeight
+	}
+}
+
+// 默认的数值格式，最多保留四位小数，很大或很小的数使用科学计数法
+func formatValue(v float64) string {
+	if abs := math.Abs(v); abs >= 1e9 || (abs > 0 && abs < 1e-4) {
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	s := strconv.FormatFloat(v, 'f', 4, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// 是否为可以绘制的数
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,220 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+
+	"github.com/Seikaijyu/gio/f32"
+)
+
+// 校验接口是否实现
+var _ widget.WidgetInterface = &LineChart{}
+
+// 折线图，开启Area后为面积图
+//
+// 悬浮提示显示横坐标最接近指针的数据点，大量数据点会按像素合并后再绘制
+type LineChart struct {
+	xy[*LineChart]
+	// 是否填充折线和零线之间的区域
+	fillArea bool
+	// 是否绘制数据点
+	showPoints bool
+	// 线宽，单位为dp
+	lineWidth float32
+}
+
+// 是否填充折线和零线之间的区域
+func (p *LineChart) Area(area bool) *LineChart {
+	p.fillArea = area
+	p.plot.Repaint()
+	return p
+}
+
+// 是否在折线上绘制数据点，数据点过密时不绘制
+func (p *LineChart) ShowPoints(show bool) *LineChart {
+	p.showPoints = show
+	p.plot.Repaint()
+	return p
+}
+
+// 线宽，默认为2dp
+func (p *LineChart) LineWidth(width float32) *LineChart {
+	p.lineWidth = width
+	p.plot.Repaint()
+	return p
+}
+
+// 绘制数据
+func (p *LineChart) paintPlot(dc *widget.CanvasContext, c *colors, width, height float32) {
+	g := p.paintBackdrop(dc, c, width, height, false, p.fillArea)
+	if !g.valid() {
+		return
+	}
+	dc.Save().ClipRect(g.rect.x, g.rect.y, g.w, g.h).LineWidth(p.lineWidth)
+	defer dc.Restore()
+	zero := g.py(math.Max(g.y.lo, math.Min(0, g.y.hi)))
+	for i, s := range p.series {
+		col := s.colorAt(c, i)
+		runs := project(&g, s.visible(), true)
+		if p.fillArea {
+			fill(dc, withAlpha(col, 0x40)).BeginPath()
+			for _, run := range runs {
+				dc.MoveTo(run[0].X, zero)
+				for _, pt := range run {
+					dc.LineTo(pt.X, pt.Y)
+				}
+				dc.LineTo(run[len(run)-1].X, zero).ClosePath()
+			}
+			dc.Fill()
+		}
+		stroke(dc, col).BeginPath()
+		for _, run := range runs {
+			dc.MoveTo(run[0].X, run[0].Y)
+			for _, pt := range run[1:] {
+				dc.LineTo(pt.X, pt.Y)
+			}
+		}
+		dc.Stroke()
+		if p.showPoints && len(s.visible()) <= int(g.w/4) {
+			fill(dc, col)
+			dots(dc, runs, p.lineWidth+1)
+		}
+	}
+}
+
+// 标记横坐标最接近指针的数据点
+func (p *LineChart) lookup(dc *widget.CanvasContext, c *colors, x, y float32) (*tip, bool) {
+	g := &p.geom
+	if !g.valid() || !g.contains(x, y) {
+		return nil, false
+	}
+	target := g.x.value(x, g.rect.x, g.rect.x+g.w)
+	found := make([]int, len(p.series))
+	best, nearest := math.Inf(1), 0.0
+	for i, s := range p.series {
+		found[i] = s.nearestX(target)
+		if found[i] < 0 || !finite(s.visible()[found[i]].Y) {
+			found[i] = -1
+			continue
+		}
+		pt := s.visible()[found[i]]
+		if d := math.Abs(float64(g.px(pt.X) - x)); d < best {
+			best, nearest = d, pt.X
+		}
+	}
+	if math.IsInf(best, 1) {
+		return nil, false
+	}
+	sx := g.px(nearest)
+	stroke(dc, c.axis).LineWidth(1).Line(sx, g.rect.y, sx, g.rect.y+g.h)
+	t := &tip{title: p.formatX(nearest)}
+	for i, s := range p.series {
+		if found[i] < 0 {
+			continue
+		}
+		// 其他系列只显示横坐标足够接近的数据点
+		pt := s.visible()[found[i]]
+		if math.Abs(float64(g.px(pt.X)-sx)) > 12 {
+			continue
+		}
+		col := s.colorAt(c, i)
+		marker(dc, c, col, g.px(pt.X), g.py(pt.Y), p.lineWidth+2)
+		t.rows = append(t.rows, tipRow{color: col, text: rowText(s.name, p.formatY(pt.Y))})
+	}
+	return t, true
+}
+
+// 把数据点转换为屏幕坐标，遇到无法绘制的数时断开折线，merge为true时合并同一列像素中过多的点
+func project(g *plotArea, pts []Point, merge bool) [][]f32.Point {
+	var runs [][]f32.Point
+	var run []f32.Point
+	flush := func() {
+		if len(run) > 0 {
+			if merge && len(run) > 2*int(g.w) {
+				run = decimate(run)
+			}
+			runs = append(runs, run)
+		}
+		run = nil
+	}
+	for _, pt := range pts {
+		if !finite(pt.X) || !finite(pt.Y) {
+			flush()
+			continue
+		}
+		run = append(run, f32.Pt(g.px(pt.X), g.py(pt.Y)))
+	}
+	flush()
+	return runs
+}
+
+// 合并同一列像素中的点，每列只保留第一个、最低、最高和最后一个点，折线的形状保持不变
+func decimate(run []f32.Point) []f32.Point {
+	out := make([]f32.Point, 0, len(run)/4)
+	for i := 0; i < len(run); {
+		column := math.Floor(float64(run[i].X))
+		j, lo, hi := i, i, i
+		for ; j < len(run) && math.Floor(float64(run[j].X)) == column; j++ {
+			if run[j].Y < run[lo].Y {
+				lo = j
+			}
+			if run[j].Y > run[hi].Y {
+				hi = j
+			}
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		last := -1
+		for _, k := range [4]int{i, lo, hi, j - 1} {
+			if k != last {
+				out = append(out, run[k])
+				last = k
+			}
+		}
+		i = j
+	}
+	return out
+}
+
+// 在一次填充中绘制所有数据点
+func dots(dc *widget.CanvasContext, runs [][]f32.Point, radius float32) {
+	dc.BeginPath()
+	for _, run := range runs {
+		for _, pt := range run {
+			dc.MoveTo(pt.X+radius, pt.Y).Arc(pt.X, pt.Y, radius, 0, 360).ClosePath()
+		}
+	}
+	dc.Fill()
+}
+
+// 悬浮时标记数据点，带有背景色的描边使标记和折线区分开
+func marker(dc *widget.CanvasContext, c *colors, col color.NRGBA, x, y, radius float32) {
+	fill(dc, c.surface).FillCircle(x, y, radius+2)
+	fill(dc, col).FillCircle(x, y, radius)
+}
+
+// 提示中的一行，没有名称时只显示数值
+func rowText(name, value string) string {
+	if name == "" {
+		return value
+	}
+	return name + ": " + value
+}
+
+// 创建折线图
+func NewLineChart() *LineChart {
+	p := &LineChart{lineWidth: 2}
+	p.init(p)
+	p.grid = true
+	p.paintData = p.paintPlot
+	p.hit = p.lookup
+	return p
+}
+
+// 创建面积图
+func NewAreaChart() *LineChart {
+	return NewLineChart().Area(true)
+}
@@ -0,0 +1,262 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/Seikaijyu/nenki.ui/utils"
+	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+)
+
+// 校验接口是否实现
+var _ widget.WidgetInterface = &PieChart{}
+
+// 饼图的扇区
+type pieSlice struct {
+	// 名称
+	label string
+	// 值，不大于0时不绘制
+	value float64
+	// 颜色，为nil时使用图表的系列颜色
+	color *color.NRGBA
+}
+
+// 饼图，设置Donut后为环形图，扇区从正上方开始顺时针排列
+//
+// 所有修改数据的方法都可以在任意goroutine中调用
+type PieChart struct {
+	base[*PieChart]
+	// 扇区
+	slices []pieSlice
+	// 内半径和外半径的比例，0为饼图
+	donut float32
+	// 是否在扇区上显示百分比
+	labels bool
+	// 值的格式，为nil时使用默认格式
+	format func(float64) string
+	// 上次绘制时的圆心和半径
+	cx, cy, radius float32
+}
+
+// 修改数据，fn在写锁中执行
+func (p *PieChart) modify(fn func()) *PieChart {
+	p.data.mu.Lock()
+	fn()
+	p.data.mu.Unlock()
+	p.data.changed()
+	return p
+}
+
+// 设置扇区的值，扇区不存在时追加到最后
+func (p *PieChart) Slice(label string, value float64) *PieChart {
+	return p.modify(func() {
+		for i := range p.slices {
+			if p.slices[i].label == label {
+				p.slices[i].value = value
+				return
+			}
+		}
+		p.slices = append(p.slices, pieSlice{label: label, value: value})
+	})
+}
+
+// 设置扇区的颜色，默认使用图表的系列颜色
+func (p *PieChart) SliceColor(label string, r, g, b, a uint8) *PieChart {
+	return p.modify(func() {
+		for i := range p.slices {
+			if p.slices[i].label == label {
+				p.slices[i].color = &color.NRGBA{R: r, G: g, B: b, A: a}
+				return
+			}
+		}
+	})
+}
+
+// 移除扇区
+func (p *PieChart) RemoveSlice(label string) *PieChart {
+	return p.modify(func() {
+		for i := range p.slices {
+			if p.slices[i].label == label {
+				p.slices = append(p.slices[:i], p.slices[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// 清空所有扇区
+func (p *PieChart) Clear() *PieChart {
+	return p.modify(func() {
+		p.slices = nil
+	})
+}
+
+// 环形图内半径和外半径的比例，范围为0到0.9，0为饼图
+func (p *PieChart) Donut(ratio float32) *PieChart {
+	p.donut = float32(math.Min(math.Max(float64(ratio), 0), 0.9))
+	p.plot.Repaint()
+	return p
+}
+
+// 是否在足够大的扇区上显示百分比
+func (p *PieChart) Labels(show bool) *PieChart {
+	p.labels = show
+	p.plot.Repaint()
+	return p
+}
+
+// 悬浮提示中值的格式
+func (p *PieChart) Format(format func(v float64) string) *PieChart {
+	p.format = format
+	p.overlay.Repaint()
+	return p
+}
+
+// 第i个扇区的颜色
+func (p *PieChart) colorAt(c *colors, i int) color.NRGBA {
+	if p.slices[i].color != nil {
+		return *p.slices[i].color
+	}
+	return c.series(i)
+}
+
+// 所有正数值的和
+func (p *PieChart) total() float64 {
+	total := 0.0
+	for _, s := range p.slices {
+		if s.value > 0 && finite(s.value) {
+			total += s.value
+		}
+	}
+	return total
+}
+
+// 依次回调每个扇区的下标、起始角度和结束角度，角度单位为度，0度指向右边
+func (p *PieChart) sectors(fn func(i int, start, end float32)) {
+	total := p.total()
+	if total <= 0 {
+		return
+	}
+	angle := float32(-90)
+	for i, s := range p.slices {
+		if s.value <= 0 || !finite(s.value) {
+			continue
+		}
+		sweep := float32(s.value / total * 360)
+		fn(i, angle, angle+sweep)
+		angle += sweep
+	}
+}
+
+// 填充扇区，设置了内半径时填充圆环的一段
+func (p *PieChart) sector(dc *widget.CanvasContext, radius, start, end float32) {
+	inner := p.radius * p.donut
+	if inner <= 0 {
+		dc.FillSector(p.cx, p.cy, radius, start, end)
+		return
+	}
+	dc.BeginPath().Arc(p.cx, p.cy, radius, start, end).Arc(p.cx, p.cy, inner, end, start).ClosePath().Fill()
+}
+
+// 绘制数据
+func (p *PieChart) paintPlot(dc *widget.CanvasContext, c *colors, width, height float32) {
+	entries := make([]legendEntry, len(p.slices))
+	for i, s := range p.slices {
+		entries[i] = legendEntry{name: s.label, color: p.colorAt(c, i)}
+	}
+	area := p.paintFrame(dc, c, width, height, entries)
+	p.cx, p.cy = area.x+area.w/2, area.y+area.h/2
+	// 留出悬浮时放大扇区的空间
+	p.radius = float32(math.Min(float64(area.w), float64(area.h)))/2 - 4
+	if p.radius <= 0 {
+		return
+	}
+	total := p.total()
+	p.sectors(func(i int, start, end float32) {
+		fill(dc, p.colorAt(c, i))
+		p.sector(dc, p.radius, start, end)
+	})
+	if !p.labels {
+		return
+	}
+	dc.Save().FontSize(p.textSize()).TextAlign(text.Middle)
+	defer dc.Restore()
+	_, lineHeight := dc.MeasureText("0")
+	p.sectors(func(i int, start, end float32) {
+		// 太小的扇区放不下文字
+		if end-start < 18 {
+			return
+		}
+		mid := float64(start+end) / 2 * math.Pi / 180
+		r := p.radius * (1 + p.donut) / 2
+		x, y := p.cx+r*float32(math.Cos(mid)), p.cy+r*float32(math.Sin(mid))
+		col := p.colorAt(c, i)
+		label := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		if utils.FromColor(col).Luminance() > 0.5 {
+			label = color.NRGBA{A: 0xde}
+		}
+		fill(dc, label).Text(x, y-lineHeight/2, percent(p.slices[i].value/total))
+	})
+}
+
+// 放大指针所在的扇区，提示中显示值和百分比
+func (p *PieChart) lookup(dc *widget.CanvasContext, c *colors, x, y float32) (*tip, bool) {
+	if p.radius <= 0 {
+		return nil, false
+	}
+	dx, dy := float64(x-p.cx), float64(y-p.cy)
+	d := float32(math.Hypot(dx, dy))
+	if d > p.radius || d < p.radius*p.donut {
+		return nil, false
+	}
+	// 和扇区使用相同的角度范围，从-90度开始
+	angle := float32(math.Atan2(dy, dx) * 180 / math.Pi)
+	if angle < -90 {
+		angle += 360
+	}
+	found := -1
+	var from, to float32
+	p.sectors(func(i int, start, end float32) {
+		if angle >= start && angle < end {
+			found, from, to = i, start, end
+		}
+	})
+	if found < 0 {
+		return nil, false
+	}
+	s := p.slices[found]
+	col := p.colorAt(c, found)
+	fill(dc, c.surface)
+	p.sector(dc, p.radius+4, from, to)
+	fill(dc, col)
+	p.sector(dc, p.radius+3, from, to)
+	value := formatValue(s.value)
+	if p.format != nil {
+		value = p.format(s.value)
+	}
+	return &tip{
+		title: s.label,
+		rows:  []tipRow{{color: col, text: value + " (" + percent(s.value/p.total()) + ")"}},
+	}, true
+}
+
+// 格式化百分比，保留一位小数
+func percent(v float64) string {
+	return strconv.FormatFloat(v*100, 'f', 1, 64) + "%"
+}
+
+// 创建饼图
+func NewPieChart() *PieChart {
+	p := &PieChart{}
+	p.init(p)
+	p.paintData = p.paintPlot
+	p.hit = p.lookup
+	return p
+}
+
+// 创建环形图
+func NewDonutChart() *PieChart {
+	return NewPieChart().Donut(0.6)
+}
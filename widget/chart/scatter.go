@@ -0,0 +1,80 @@
+package chart
+
+import (
+	"math"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 校验接口是否实现
+var _ widget.WidgetInterface = &ScatterChart{}
+
+// 散点图，悬浮提示显示距离指针最近的数据点
+type ScatterChart struct {
+	xy[*ScatterChart]
+	// 数据点的半径，单位为dp
+	pointSize float32
+}
+
+// 数据点的半径，默认为3dp
+func (p *ScatterChart) PointSize(radius float32) *ScatterChart {
+	p.pointSize = radius
+	p.plot.Repaint()
+	return p
+}
+
+// 绘制数据
+func (p *ScatterChart) paintPlot(dc *widget.CanvasContext, c *colors, width, height float32) {
+	g := p.paintBackdrop(dc, c, width, height, true, false)
+	if !g.valid() {
+		return
+	}
+	dc.Save().ClipRect(g.rect.x, g.rect.y, g.w, g.h)
+	defer dc.Restore()
+	for i, s := range p.series {
+		fill(dc, withAlpha(s.colorAt(c, i), 0xcc))
+		dots(dc, project(&g, s.visible(), false), p.pointSize)
+	}
+}
+
+// 标记距离指针最近的数据点，超过一定距离时不显示
+func (p *ScatterChart) lookup(dc *widget.CanvasContext, c *colors, x, y float32) (*tip, bool) {
+	g := &p.geom
+	if !g.valid() || !g.contains(x, y) {
+		return nil, false
+	}
+	limit := float64(p.pointSize + 16)
+	best, series := limit*limit, -1
+	var nearest Point
+	for i, s := range p.series {
+		for _, pt := range s.visible() {
+			if !finite(pt.X) || !finite(pt.Y) {
+				continue
+			}
+			dx, dy := float64(g.px(pt.X)-x), float64(g.py(pt.Y)-y)
+			if d := dx*dx + dy*dy; d < best {
+				best, series, nearest = d, i, pt
+			}
+		}
+	}
+	if series < 0 {
+		return nil, false
+	}
+	s := p.series[series]
+	col := s.colorAt(c, series)
+	marker(dc, c, col, g.px(nearest.X), g.py(nearest.Y), float32(math.Max(float64(p.pointSize), 3))+1)
+	return &tip{
+		title: s.name,
+		rows:  []tipRow{{color: col, text: p.formatX(nearest.X) + ", " + p.formatY(nearest.Y)}},
+	}, true
+}
+
+// 创建散点图
+func NewScatterChart() *ScatterChart {
+	p := &ScatterChart{pointSize: 3}
+	p.init(p)
+	p.grid = true
+	p.paintData = p.paintPlot
+	p.hit = p.lookup
+	return p
+}
@@ -0,0 +1,289 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 数据点
+type Point struct {
+	X, Y float64
+}
+
+// 折线图和散点图的数据系列，所有方法都可以在任意goroutine中调用，修改数据后窗口会自动重绘
+type Series struct {
+	// 图表数据
+	data *store
+	// 名称，显示在图例和提示中
+	name string
+	// 颜色，为nil时使用图表的系列颜色
+	color *color.NRGBA
+	// 数据点，设置了max时前面可能有还没有清理的旧数据点
+	points []Point
+	// 最多保留的数据点数量，0表示不限制
+	max int
+	// 横坐标是否按追加的顺序递增，递增时使用二分查找
+	sorted bool
+}
+
+// 创建数据系列
+func newSeries(data *store, name string) *Series {
+	return &Series{data: data, name: name, sorted: true}
+}
+
+// 修改数据，fn在写锁中执行
+func (p *Series) modify(fn func()) *Series {
+	p.data.mu.Lock()
+	fn()
+	p.data.mu.Unlock()
+	p.data.changed()
+	return p
+}
+
+// 追加一个数据点，超过MaxPoints时丢弃最早的点
+func (p *Series) Append(x, y float64) *Series {
+	return p.AppendPoints(Point{X: x, Y: y})
+}
+
+// 追加多个数据点，超过MaxPoints时丢弃最早的点
+func (p *Series) AppendPoints(points ...Point) *Series {
+	return p.modify(func() {
+		for _, pt := range points {
+			if n := len(p.points); n > 0 && pt.X < p.points[n-1].X {
+				p.sorted = false
+			}
+			p.points = append(p.points, pt)
+		}
+		p.trim()
+	})
+}
+
+// 替换所有数据点
+func (p *Series) Points(points ...Point) *Series {
+	return p.modify(func() {
+		p.points = append([]Point(nil), points...)
+		p.sorted = sort.SliceIsSorted(p.points, func(i, j int) bool {
+			return p.points[i].X < p.points[j].X
+		})
+		p.trim()
+	})
+}
+
+// 清空数据点
+func (p *Series) Clear() *Series {
+	return p.modify(func() {
+		p.points = p.points[:0]
+		p.sorted = true
+	})
+}
+
+// 最多保留的数据点数量，用于滚动显示的实时数据，0表示不限制
+func (p *Series) MaxPoints(max int) *Series {
+	return p.modify(func() {
+		p.max = maxInt(max, 0)
+		p.trim()
+	})
+}
+
+// 名称，显示在图例和提示中
+func (p *Series) Name(name string) *Series {
+	return p.modify(func() {
+		p.name = name
+	})
+}
+
+// 颜色，默认使用图表的系列颜色
+func (p *Series) Color(r, g, b, a uint8) *Series {
+	return p.modify(func() {
+		p.color = &color.NRGBA{R: r, G: g, B: b, A: a}
+	})
+}
+
+// 获取名称
+func (p *Series) GetName() string {
+	p.data.mu.RLock()
+	defer p.data.mu.RUnlock()
+	return p.name
+}
+
+// 获取所有数据点的副本
+func (p *Series) GetPoints() []Point {
+	p.data.mu.RLock()
+	defer p.data.mu.RUnlock()
+	return append([]Point(nil), p.visible()...)
+}
+
+// 获取数据点的数量
+func (p *Series) Len() int {
+	p.data.mu.RLock()
+	defer p.data.mu.RUnlock()
+	return len(p.visible())
+}
+
+// 丢弃超出数量的数据点，超出一倍时才移动数据，使追加的平均开销为常数
+func (p *Series) trim() {
+	if p.max > 0 && len(p.points) >= 2*p.max {
+		p.points = append(p.points[:0], p.points[len(p.points)-p.max:]...)
+	}
+}
+
+// 需要显示的数据点
+func (p *Series) visible() []Point {
+	if p.max > 0 && len(p.points) > p.max {
+		return p.points[len(p.points)-p.max:]
+	}
+	return p.points
+}
+
+// 系列的颜色
+func (p *Series) colorAt(c *colors, i int) color.NRGBA {
+	if p.color != nil {
+		return *p.color
+	}
+	return c.series(i)
+}
+
+// 横坐标最接近x的数据点的下标，没有数据时返回-1
+func (p *Series) nearestX(x float64) int {
+	pts := p.visible()
+	if len(pts) == 0 {
+		return -1
+	}
+	if p.sorted {
+		i := sort.Search(len(pts), func(i int) bool { return pts[i].X >= x })
+		switch {
+		case i == len(pts):
+			return i - 1
+		case i > 0 && x-pts[i-1].X <= pts[i].X-x:
+			return i - 1
+		}
+		return i
+	}
+	best, dist := -1, math.Inf(1)
+	for i, pt := range pts {
+		if d := math.Abs(pt.X - x); d < dist && finite(pt.Y) {
+			best, dist = i, d
+		}
+	}
+	return best
+}
+
+// 多个系列的数据，折线图和散点图共用
+type xy[T any] struct {
+	cartesian[T]
+	// 数据系列
+	series []*Series
+	// 横轴刻度和提示的格式，为nil时使用默认格式
+	xFormat func(float64) string
+	// 手动设置的横轴范围
+	xMin, xMax float64
+	// 是否手动设置了横轴范围
+	xFixed bool
+	// 上次绘制时的绘图区
+	geom plotArea
+}
+
+// 添加数据系列，名称显示在图例和提示中
+func (p *xy[T]) AddSeries(name string) *Series {
+	s := newSeries(p.data, name)
+	p.data.mu.Lock()
+	p.series = append(p.series, s)
+	p.data.mu.Unlock()
+	p.data.changed()
+	return s
+}
+
+// 移除数据系列
+func (p *xy[T]) RemoveSeries(series *Series) T {
+	p.data.mu.Lock()
+	for i, s := range p.series {
+		if s == series {
+			p.series = append(p.series[:i], p.series[i+1:]...)
+			break
+		}
+	}
+	p.data.mu.Unlock()
+	p.data.changed()
+	return p.self
+}
+
+// 获取所有数据系列
+func (p *xy[T]) GetSeries() []*Series {
+	p.data.mu.RLock()
+	defer p.data.mu.RUnlock()
+	return append([]*Series(nil), p.series...)
+}
+
+// 横轴刻度标签和悬浮提示中横坐标的格式，例如把时间戳格式化为时间
+func (p *xy[T]) XFormat(format func(v float64) string) T {
+	p.xFormat = format
+	p.plot.Repaint()
+	return p.self
+}
+
+// 固定横轴的范围，min不小于max时根据数据自动计算
+func (p *xy[T]) XRange(min, max float64) T {
+	p.xMin, p.xMax, p.xFixed = min, max, min < max
+	p.plot.Repaint()
+	return p.self
+}
+
+// 格式化横轴的数值
+func (p *xy[T]) formatX(v float64) string {
+	if p.xFormat != nil {
+		return p.xFormat(v)
+	}
+	return formatValue(v)
+}
+
+// 图例
+func (p *xy[T]) entries(c *colors) []legendEntry {
+	entries := make([]legendEntry, len(p.series))
+	for i, s := range p.series {
+		entries[i] = legendEntry{name: s.name, color: s.colorAt(c, i)}
+	}
+	return entries
+}
+
+// 所有系列数据的范围，没有数据时返回0到1
+func (p *xy[T]) bounds() (x0, x1, y0, y1 float64) {
+	x0, y0 = math.Inf(1), math.Inf(1)
+	x1, y1 = math.Inf(-1), math.Inf(-1)
+	for _, s := range p.series {
+		for _, pt := range s.visible() {
+			if !finite(pt.X) || !finite(pt.Y) {
+				continue
+			}
+			x0, x1 = math.Min(x0, pt.X), math.Max(x1, pt.X)
+			y0, y1 = math.Min(y0, pt.Y), math.Max(y1, pt.Y)
+		}
+	}
+	if x0 > x1 {
+		x0, x1 = 0, 1
+	}
+	if y0 > y1 {
+		y0, y1 = 0, 1
+	}
+	return
+}
+
+// 绘制标题、图例和坐标轴，返回绘图区，nice为true时横轴范围扩展到刻度上
+func (p *xy[T]) paintBackdrop(dc *widget.CanvasContext, c *colors, width, height float32, nice, includeZero bool) plotArea {
+	x0, x1, y0, y1 := p.bounds()
+	if p.xFixed {
+		x0, x1 = p.xMin, p.xMax
+	}
+	if includeZero {
+		y0, y1 = math.Min(y0, 0), math.Max(y1, 0)
+	}
+	area := p.base.paintFrame(dc, c, width, height, p.entries(c))
+	p.geom = p.paintAxes(dc, c, area, func(length float32) axis {
+		return newAxis(x0, x1, maxInt(int(length/80), 2), nice && !p.xFixed, p.xFormat)
+	}, func(length float32) axis {
+		return p.yAxis(y0, y1, length)
+	}, false)
+	return p.geom
+}
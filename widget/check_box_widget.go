@@ -3,8 +3,11 @@ package widget
 import (
 	"image/color"
 
+	"github.com/Seikaijyu/nenki.ui/widget/text"
 	"github.com/Seikaijyu/nenki.ui/widget/theme"
 
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/io/semantic"
 	glayout "github.com/Seikaijyu/gio/layout"
 	gunit "github.com/Seikaijyu/gio/unit"
 	gwidget "github.com/Seikaijyu/gio/widget"
@@ -15,6 +18,16 @@ import (
 var _ WidgetInterface = &CheckBox{}
 
 type checkBoxConfig struct {
+	// 主题状态
+	themed themed
+	// 字体族
+	typeface typeface
+	// 字间距
+	letterSpacing float32
+	// 装饰线
+	decoration text.Decoration
+	// 是否禁用
+	disabled bool
 	// 记录选择的bool
 	checkedBool bool
 	// 焦点事件
@@ -35,6 +48,8 @@ type checkBoxConfig struct {
 
 // 复选框
 type CheckBox struct {
+	// 组件标识
+	identity
 	checkBoxWidget *gmaterial.CheckBoxStyle
 	checkBool      *gwidget.Bool
 	// 配置
@@ -50,6 +65,18 @@ func (p *CheckBox) Then(fn func(self *CheckBox)) *CheckBox {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *CheckBox) ID(id string) *CheckBox {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *CheckBox) Class(classes ...string) *CheckBox {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *CheckBox) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -60,6 +87,22 @@ func (p *CheckBox) Update(update bool) {
 	p.config.update = update
 }
 
+// 是否禁用组件，禁用时不响应任何输入
+func (p *CheckBox) Disabled(disabled bool) *CheckBox {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *CheckBox) GetState() State {
+	return stateOf(p.checkBoxWidget.CheckBox.Hovered(), p.checkBoxWidget.CheckBox.Focused(), p.config.disabled, p.checkBool.Value)
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *CheckBox) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *CheckBox) Destroy() {
 	p.config.update = false
@@ -78,11 +121,20 @@ func (p *CheckBox) Margin(Top, Left, Bottom, Right float32) *CheckBox {
 	return p
 }
 
+// 获取外边距
+func (p *CheckBox) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 重新设置父节点
 func (p *CheckBox) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		p.config.update = false
 	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
 	if p.config._hovered != nil && p.config._hoverValue != p.checkBoxWidget.CheckBox.Hovered() {
 		p.config._hovered(p, p.checkBoxWidget.CheckBox.Hovered(), p.checkBool.Value)
 		p.config._hoverValue = p.checkBoxWidget.CheckBox.Hovered()
@@ -94,11 +146,38 @@ func (p *CheckBox) Layout(gtx glayout.Context) glayout.Dimensions {
 	}
 
 	p.config.checkedBool = p.checkBool.Value
+	p.applyTheme()
+	p.checkBoxWidget.Font.Typeface = p.config.typeface.resolve(p.checkBoxWidget.Label)
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		// 有字间距或装饰线时自行绘制文本
+		if p.config.letterSpacing != 0 || p.config.decoration != text.DecorationNone {
+			return p.checkBool.Layout(gtx, p.layoutText)
+		}
 		return p.checkBoxWidget.Layout(gtx)
 	})
 }
 
+// 绘制勾选框和带有字间距或装饰线的文本
+func (p *CheckBox) layoutText(gtx glayout.Context) glayout.Dimensions {
+	semantic.CheckBox.Add(gtx.Ops)
+	icon := theme.Material().Icon.CheckBoxUnchecked
+	if p.checkBool.Value {
+		icon = theme.Material().Icon.CheckBoxChecked
+	}
+	style := p.checkBoxWidget
+	return checkable{
+		icon:          icon,
+		iconColor:     style.IconColor,
+		size:          style.Size,
+		label:         style.Label,
+		font:          style.Font,
+		textSize:      style.TextSize,
+		color:         style.Color,
+		letterSpacing: p.config.letterSpacing,
+		decoration:    p.config.decoration,
+	}.layout(gtx)
+}
+
 // 设置尺寸
 func (p *CheckBox) Size(size float32) *CheckBox {
 	if size < 16 {
@@ -129,15 +208,65 @@ func (p *CheckBox) OnChecked(fn func(p *CheckBox, check bool)) *CheckBox {
 	return p
 }
 
+// 设置字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *CheckBox) FontFamily(name string) *CheckBox {
+	p.config.typeface.set(name)
+	return p
+}
+
+// 设置字体粗细
+func (p *CheckBox) FontWeight(weight text.Weight) *CheckBox {
+	p.checkBoxWidget.Font.Weight = weight
+	return p
+}
+
+// 设置是否使用斜体
+func (p *CheckBox) Italic(italic bool) *CheckBox {
+	p.checkBoxWidget.Font.Style = gfont.Regular
+	if italic {
+		p.checkBoxWidget.Font.Style = gfont.Italic
+	}
+	return p
+}
+
+// 设置文本样式
+func (p *CheckBox) TextStyle(style text.Style) *CheckBox {
+	p.FontFamily(style.Family)
+	p.FontWeight(style.Weight)
+	p.Italic(style.Italic)
+	p.LetterSpacing(style.LetterSpacing)
+	p.Decoration(style.Decoration)
+	return p
+}
+
+// 设置字间距，字间距不参与换行计算
+func (p *CheckBox) LetterSpacing(spacing float32) *CheckBox {
+	p.config.letterSpacing = spacing
+	return p
+}
+
+// 设置装饰线，例如text.Underline|text.Strikethrough
+func (p *CheckBox) Decoration(decoration text.Decoration) *CheckBox {
+	p.config.decoration = decoration
+	return p
+}
+
 // 设置文字颜色
 func (p *CheckBox) FontColor(r, g, b, a uint8) *CheckBox {
 	p.checkBoxWidget.Color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
 	return p
 }
 
+// 获取文字颜色
+func (p *CheckBox) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.checkBoxWidget.Color)
+}
+
 // 设置勾选框左边标记的颜色
 func (p *CheckBox) CheckMarkColor(r, g, b, a uint8) *CheckBox {
 	p.checkBoxWidget.IconColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
 	return p
 }
 
@@ -164,14 +293,28 @@ func (p *CheckBox) OnFocused(fn func(p *CheckBox, focus bool, value bool)) *Chec
 	return p
 }
 
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *CheckBox) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideFontColor) {
+		p.checkBoxWidget.Color = th.Text
+	}
+	if !p.config.themed.overrides.has(overrideMarkColor) {
+		p.checkBoxWidget.IconColor = th.Primary
+	}
+}
+
 // 创建复选框
 func NewCheckBox(text string) *CheckBox {
 	checkBool := gwidget.Bool{}
-	widget := gmaterial.CheckBox(theme.NewTheme(), &checkBool, text)
+	widget := gmaterial.CheckBox(theme.Material(), &checkBool, text)
 	checkBox := &CheckBox{
 		checkBool:      &checkBool,
 		checkBoxWidget: &widget,
-		config:         &checkBoxConfig{update: true, checkedBool: false},
+		config:         &checkBoxConfig{update: true, checkedBool: false, themed: newThemed(), typeface: newTypeface()},
 		margin:         &glayout.Inset{},
 	}
 	return checkBox.Size(16)
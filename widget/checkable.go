@@ -0,0 +1,71 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	gfont "github.com/Seikaijyu/gio/font"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	gunit "github.com/Seikaijyu/gio/unit"
+	gwidget "github.com/Seikaijyu/gio/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+)
+
+// 带有字间距或装饰线的勾选框和单选框，布局与gio的勾选框一致，只是自行绘制文本
+type checkable struct {
+	// 图标
+	icon *gwidget.Icon
+	// 图标颜色
+	iconColor color.NRGBA
+	// 图标大小
+	size gunit.Dp
+	// 文本
+	label string
+	// 字体
+	font gfont.Font
+	// 文字大小
+	textSize gunit.Sp
+	// 文字颜色
+	color color.NRGBA
+	// 字间距
+	letterSpacing float32
+	// 装饰线
+	decoration text.Decoration
+}
+
+// 绘制图标和文本
+func (c checkable) layout(gtx glayout.Context) glayout.Dimensions {
+	return glayout.Flex{Alignment: glayout.Middle}.Layout(gtx,
+		glayout.Rigid(func(gtx glayout.Context) glayout.Dimensions {
+			defer op.Offset(image.Pt(0, 2)).Push(gtx.Ops).Pop()
+			size := gtx.Dp(c.size)
+			col := c.iconColor
+			if gtx.Queue == nil {
+				col = disabledColor(col)
+			}
+			gtx.Constraints.Min = image.Point{X: size}
+			c.icon.Layout(gtx, col)
+			return glayout.Dimensions{Size: image.Point{X: size, Y: size}}
+		}),
+		glayout.Rigid(func(gtx glayout.Context) glayout.Dimensions {
+			return textpaint.Label{
+				LetterSpacing: gunit.Sp(c.letterSpacing),
+				Decoration:    c.decoration,
+			}.Layout(gtx, theme.Shaper, c.font, c.textSize, c.label, c.color)
+		}),
+	)
+}
+
+// 禁用时的图标颜色，与gio的勾选框一致：向亮度混合并降低透明度
+func disabledColor(c color.NRGBA) color.NRGBA {
+	lum := byte((13933*int(c.R) + 46871*int(c.G) + 4732*int(c.B)) / (13933 + 46871 + 4732))
+	mix := func(a, b byte) byte {
+		return byte((int(a)*80 + int(b)*(256-80)) / 256)
+	}
+	c = color.NRGBA{R: mix(c.R, lum), G: mix(c.G, lum), B: mix(c.B, lum), A: c.A}
+	c.A = uint8(uint32(c.A) * (128 + 32) / 0xff)
+	return c
+}
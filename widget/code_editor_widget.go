@@ -0,0 +1,965 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/syntax"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	"github.com/Seikaijyu/gio/io/key"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gtext "github.com/Seikaijyu/gio/text"
+	gunit "github.com/Seikaijyu/gio/unit"
+	gwidget "github.com/Seikaijyu/gio/widget"
+	"golang.org/x/image/math/fixed"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &CodeEditor{}
+
+// 可以匹配的括号
+const codeBrackets = "()[]{}"
+
+// 查找括号匹配时最多扫描的字符数量
+const codeBracketScan = 20000
+
+type codeEditorConfig struct {
+	// 主题状态
+	themed themed
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 词法分析器，为nil时不进行语法高亮
+	tokenizer syntax.Tokenizer
+	// 语法高亮配色
+	colors *syntax.Colors
+	// 普通文字颜色
+	textColor color.NRGBA
+	// 是否显示行号
+	lineNumbers bool
+	// 行号颜色
+	lineNumberColor color.NRGBA
+	// 行号区域背景颜色
+	gutterColor color.NRGBA
+	// 是否高亮当前行
+	highlightLine bool
+	// 当前行高亮颜色
+	lineColor color.NRGBA
+	// 是否高亮匹配的括号
+	bracketMatching bool
+	// 匹配括号的高亮颜色
+	bracketColor color.NRGBA
+	// 制表符宽度
+	tabSize int
+	// 按下Tab时是否插入空格
+	insertSpaces bool
+	// 换行时是否自动缩进
+	autoIndent bool
+}
+
+// 代码编辑框中的一行
+type codeLine struct {
+	// 行首在全文中的字符位置
+	start int
+	// 行文本，不包含换行符
+	text string
+	// 字符数量
+	runes int
+	// 语法记号
+	tokens []syntax.Token
+	// 行首和行尾的分析状态
+	state, end syntax.State
+}
+
+// 代码编辑框，在编辑框的基础上增加行号、语法高亮、括号匹配和查找替换
+type CodeEditor struct {
+	// 组件标识
+	identity
+	// 配置
+	config *codeEditorConfig
+	// 外边距
+	margin *glayout.Inset
+	// 内部的编辑框，文字由代码编辑框着色后绘制
+	editor *Editor
+	// 分析过的全文
+	text string
+	// 是否需要重新分析
+	dirty bool
+	// 按行分析的结果
+	lines []codeLine
+	// 拦截的回车键
+	enters []key.Event
+	// 上一帧第一个可见的行，用于加快查找可见行
+	firstVisible int
+	// 复用的区域和字形
+	regions []gwidget.Region
+	glyphs  []gtext.Glyph
+}
+
+// 绑定函数
+func (p *CodeEditor) Then(fn func(self *CodeEditor)) *CodeEditor {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *CodeEditor) ID(id string) *CodeEditor {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *CodeEditor) Class(classes ...string) *CodeEditor {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *CodeEditor) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *CodeEditor) Destroy() {
+	p.config.update = false
+	p.editor.Destroy()
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *CodeEditor) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 是否更新组件
+func (p *CodeEditor) Update(update bool) {
+	p.config.update = update
+}
+
+// 外边距
+func (p *CodeEditor) Margin(Top, Left, Bottom, Right float32) *CodeEditor {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *CodeEditor) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 获取内部的编辑框，用于设置只读、撤销、事件等编辑框的功能
+//
+// 编辑框的文字颜色由代码编辑框控制，请使用TextColor设置
+func (p *CodeEditor) GetEditor() *Editor {
+	return p.editor
+}
+
+// 设置代码
+func (p *CodeEditor) Text(text string) *CodeEditor {
+	p.editor.Text(text)
+	return p
+}
+
+// 获取代码
+func (p *CodeEditor) GetText() string {
+	return p.editor.GetText()
+}
+
+// 设置词法分析器，为nil时不进行语法高亮，syntax包中提供了JSON、Go和SQL的词法分析器
+func (p *CodeEditor) Tokenizer(tokenizer syntax.Tokenizer) *CodeEditor {
+	p.config.tokenizer = tokenizer
+	p.dirty = true
+	return p
+}
+
+// 设置语法高亮配色，默认根据主题的背景颜色选择syntax.Light或syntax.Dark
+func (p *CodeEditor) SyntaxColors(colors *syntax.Colors) *CodeEditor {
+	p.config.colors = colors
+	p.config.themed.overrides.set(overrideSyntaxColors)
+	return p
+}
+
+// 设置普通文字颜色
+func (p *CodeEditor) TextColor(r, g, b, a uint8) *CodeEditor {
+	p.config.textColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
+	return p
+}
+
+// 设置字体大小
+func (p *CodeEditor) FontSize(size float32) *CodeEditor {
+	p.editor.FontSize(size)
+	return p
+}
+
+// 获取字体大小
+func (p *CodeEditor) GetFontSize() float32 {
+	return p.editor.GetFontSize()
+}
+
+// 设置字体族，默认为系统的等宽字体
+func (p *CodeEditor) FontFamily(name string) *CodeEditor {
+	p.editor.FontFamily(name)
+	return p
+}
+
+// 是否显示行号
+func (p *CodeEditor) LineNumbers(show bool) *CodeEditor {
+	p.config.lineNumbers = show
+	return p
+}
+
+// 设置行号颜色
+func (p *CodeEditor) LineNumberColor(r, g, b, a uint8) *CodeEditor {
+	p.config.lineNumberColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideLineNumberColor)
+	return p
+}
+
+// 设置行号区域的背景颜色
+func (p *CodeEditor) GutterColor(r, g, b, a uint8) *CodeEditor {
+	p.config.gutterColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBackground)
+	return p
+}
+
+// 是否高亮光标所在的行
+func (p *CodeEditor) HighlightLine(highlight bool) *CodeEditor {
+	p.config.highlightLine = highlight
+	return p
+}
+
+// 设置当前行的高亮颜色
+func (p *CodeEditor) LineColor(r, g, b, a uint8) *CodeEditor {
+	p.config.lineColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideLineColor)
+	return p
+}
+
+// 是否高亮与光标处括号匹配的括号
+func (p *CodeEditor) BracketMatching(matching bool) *CodeEditor {
+	p.config.bracketMatching = matching
+	return p
+}
+
+// 设置匹配括号的高亮颜色
+func (p *CodeEditor) BracketColor(r, g, b, a uint8) *CodeEditor {
+	p.config.bracketColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
+	return p
+}
+
+// 设置查找结果的高亮颜色，当前结果使用更高的透明度
+func (p *CodeEditor) MatchColor(r, g, b, a uint8) *CodeEditor {
+	p.editor.MatchColor(r, g, b, a)
+	return p
+}
+
+// 设置制表符宽度，默认为4
+func (p *CodeEditor) TabSize(size int) *CodeEditor {
+	if size > 0 {
+		p.config.tabSize = size
+	}
+	return p
+}
+
+// 按下Tab时是否插入空格，默认插入空格，否则插入制表符
+func (p *CodeEditor) InsertSpaces(spaces bool) *CodeEditor {
+	p.config.insertSpaces = spaces
+	return p
+}
+
+// 换行时是否保持上一行的缩进，默认启用
+func (p *CodeEditor) AutoIndent(indent bool) *CodeEditor {
+	p.config.autoIndent = indent
+	return p
+}
+
+// 查找文字并高亮所有匹配，与编辑框的Find相同，更多查找功能请使用GetEditor
+func (p *CodeEditor) Find(query string) *CodeEditor {
+	p.editor.Find(query)
+	return p
+}
+
+// 查找时是否区分大小写，默认不区分
+func (p *CodeEditor) FindCaseSensitive(caseSensitive bool) *CodeEditor {
+	p.editor.FindCaseSensitive(caseSensitive)
+	return p
+}
+
+// 选中下一个匹配
+func (p *CodeEditor) FindNext() *CodeEditor {
+	p.editor.FindNext()
+	return p
+}
+
+// 选中上一个匹配
+func (p *CodeEditor) FindPrevious() *CodeEditor {
+	p.editor.FindPrevious()
+	return p
+}
+
+// 获取匹配的数量
+func (p *CodeEditor) GetMatchCount() int {
+	return p.editor.GetMatchCount()
+}
+
+// 替换当前选中的匹配并选中下一个匹配
+func (p *CodeEditor) ReplaceCurrent(replacement string) *CodeEditor {
+	p.editor.ReplaceCurrent(replacement)
+	return p
+}
+
+// 替换所有匹配，作为一步撤销，返回替换的数量
+func (p *CodeEditor) ReplaceAll(replacement string) int {
+	return p.editor.ReplaceAll(replacement)
+}
+
+// 渲染
+func (p *CodeEditor) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	p.applyTheme()
+	return p.margin.Layout(gtx, p.layout)
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *CodeEditor) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	overrides := p.config.themed.overrides
+	if !overrides.has(overrideFontColor) {
+		p.config.textColor = th.Text
+	}
+	if !overrides.has(overrideSyntaxColors) {
+		p.config.colors = syntax.Light()
+		if luminance(th.Background) < 0.5 {
+			p.config.colors = syntax.Dark()
+		}
+	}
+	if !overrides.has(overrideLineNumberColor) {
+		p.config.lineNumberColor = th.TextSecondary
+	}
+	if !overrides.has(overrideBackground) {
+		p.config.gutterColor = withAlpha(th.Text, 0x0a)
+	}
+	if !overrides.has(overrideLineColor) {
+		p.config.lineColor = withAlpha(th.Text, 0x0d)
+	}
+	if !overrides.has(overrideMarkColor) {
+		p.config.bracketColor = withAlpha(th.Primary, 0x40)
+	}
+}
+
+// 按行重新分析代码，只重新分析改变的行
+func (p *CodeEditor) sync() {
+	text := p.editor.GetText()
+	if !p.dirty && text == p.text && p.lines != nil {
+		return
+	}
+	reuse := !p.dirty
+	p.dirty = false
+	p.text = text
+	old := p.lines
+	p.lines = make([]codeLine, 0, len(old))
+	start := 0
+	var state syntax.State
+	for i, s := range strings.Split(text, "\n") {
+		line := codeLine{start: start, text: s, runes: utf8.RuneCountInString(s), state: state}
+		if reuse && i < len(old) && old[i].text == s && old[i].state == state {
+			line.tokens, line.end = old[i].tokens, old[i].end
+		} else if p.config.tokenizer != nil {
+			line.tokens, line.end = p.config.tokenizer.Tokenize(s, state)
+		}
+		state = line.end
+		start += line.runes + 1
+		p.lines = append(p.lines, line)
+	}
+}
+
+// 获取字符位置所在的行
+func (p *CodeEditor) lineAt(pos int) int {
+	lo, hi := 0, len(p.lines)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if p.lines[mid].start <= pos {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
+
+// 渲染行号、高亮和编辑框
+func (p *CodeEditor) layout(gtx glayout.Context) glayout.Dimensions {
+	editor := p.editor.editorMaterial
+	p.handleTab(gtx)
+
+	// 无法获取编辑框的滚动位置时无法对齐行号和着色的文字，由编辑框自己绘制文字，不显示行号和高亮
+	if _, ok := editorScroll(editor.Editor); !ok {
+		editor.Color = p.config.textColor
+		return p.editor.Layout(gtx)
+	}
+	textSize := fixed.I(gtx.Sp(editor.TextSize))
+	gutter := 0
+	if p.config.lineNumbers {
+		gutter = p.gutterWidth(gtx, textSize)
+	}
+	egtx := gtx
+	egtx.Constraints.Max.X = maxInt(gtx.Constraints.Max.X-gutter, 0)
+	egtx.Constraints.Min.X = egtx.Constraints.Max.X
+	egtx.Constraints.Min.Y = gtx.Constraints.Min.Y
+	// 回车由代码编辑框处理以保持缩进
+	egtx = interceptKeys(egtx, func(e key.Event) bool {
+		if !p.config.autoIndent || e.Modifiers != 0 || (e.Name != key.NameReturn && e.Name != key.NameEnter) {
+			return false
+		}
+		p.enters = append(p.enters, e)
+		return true
+	})
+	macro := op.Record(gtx.Ops)
+	dims := p.editor.Layout(egtx)
+	call := macro.Stop()
+	if p.handleEnter() {
+		op.InvalidateOp{}.Add(gtx.Ops)
+	}
+	// 编辑框在渲染时可能已经修改了文字，渲染后再分析，每一帧只分析一次
+	p.sync()
+
+	size := gtx.Constraints.Constrain(image.Pt(gutter+dims.Size.X, dims.Size.Y))
+	defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+	// 编辑框不处理的Tab交给包裹编辑框的区域
+	var keys key.Set
+	if editor.Editor.Focused() {
+		keys = "(Shift)-Tab"
+	}
+	key.InputOp{Tag: &p.lines, Keys: keys}.Add(gtx.Ops)
+	if gutter > 0 {
+		paint.FillShape(gtx.Ops, p.config.gutterColor, clip.Rect{Max: image.Pt(gutter, size.Y)}.Op())
+	}
+
+	params := gtext.Parameters{
+		Font:            editor.Font,
+		PxPerEm:         textSize,
+		MaxWidth:        egtx.Constraints.Max.X,
+		MinWidth:        egtx.Constraints.Min.X,
+		WrapPolicy:      editor.Editor.WrapPolicy,
+		Locale:          gtx.Locale,
+		LineHeight:      fixed.I(gtx.Sp(editor.LineHeight)),
+		LineHeightScale: editor.LineHeightScale,
+	}
+	scroll, _ := editorScroll(editor.Editor)
+	visible := p.visibleLines()
+	caretLine := -1
+	start, end := editor.Editor.Selection()
+	if len(p.lines) > 0 {
+		caretLine = p.lineAt(start)
+	}
+
+	area := op.Offset(image.Pt(gutter, 0)).Push(gtx.Ops)
+	textArea := clip.Rect{Max: dims.Size}.Push(gtx.Ops)
+	if p.config.highlightLine && caretLine >= 0 && start == end && editor.Editor.Focused() {
+		line := p.lines[caretLine]
+		if rect, ok := p.bounds(line.start, line.start+line.runes, scroll); ok {
+			rect.Min.X, rect.Max.X = 0, dims.Size.X
+			paint.FillShape(gtx.Ops, p.config.lineColor, clip.Rect(rect).Op())
+		}
+	}
+	if p.config.bracketMatching && start == end {
+		p.paintBrackets(gtx, start, scroll)
+	}
+	call.Add(gtx.Ops)
+	origins := make([]int, len(visible))
+	for i, index := range visible {
+		origins[i] = p.paintLine(gtx, index, params, scroll)
+	}
+	textArea.Pop()
+	area.Pop()
+
+	if gutter > 0 {
+		for i, index := range visible {
+			if origins[i] == noOrigin {
+				continue
+			}
+			col := p.config.lineNumberColor
+			if index == caretLine {
+				col = p.config.textColor
+			}
+			p.paintLineNumber(gtx, index+1, gutter, origins[i], editor.TextSize, col)
+		}
+	}
+	return glayout.Dimensions{Size: size, Baseline: dims.Baseline}
+}
+
+// 无法确定行首位置
+const noOrigin = -1 << 31
+
+// 获取编辑框中可见的行
+func (p *CodeEditor) visibleLines() []int {
+	editor := p.editor.editorMaterial.Editor
+	isVisible := func(i int) bool {
+		line := p.lines[i]
+		p.regions = editor.Regions(line.start, line.start+line.runes, p.regions[:0])
+		return len(p.regions) > 0
+	}
+	first := -1
+	if p.firstVisible < len(p.lines) && isVisible(p.firstVisible) {
+		first = p.firstVisible
+		for first > 0 && isVisible(first-1) {
+			first--
+		}
+	} else {
+		for i := range p.lines {
+			if isVisible(i) {
+				first = i
+				break
+			}
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	p.firstVisible = first
+	var visible []int
+	for i := first; i < len(p.lines) && isVisible(i); i++ {
+		visible = append(visible, i)
+	}
+	return visible
+}
+
+// 获取字符范围在编辑框中的包围矩形
+func (p *CodeEditor) bounds(start, end int, scroll image.Point) (image.Rectangle, bool) {
+	p.regions = p.editor.editorMaterial.Editor.Regions(start, end, p.regions[:0])
+	if len(p.regions) == 0 {
+		return image.Rectangle{}, false
+	}
+	rect := p.regions[0].Bounds
+	for _, r := range p.regions[1:] {
+		rect = rect.Union(r.Bounds)
+	}
+	return rect.Sub(scroll), true
+}
+
+// 填充字符范围所在的区域
+func (p *CodeEditor) fill(gtx glayout.Context, start, end int, scroll image.Point, col color.NRGBA) {
+	p.regions = p.editor.editorMaterial.Editor.Regions(start, end, p.regions[:0])
+	for _, r := range p.regions {
+		paint.FillShape(gtx.Ops, col, clip.Rect(r.Bounds.Sub(scroll)).Op())
+	}
+}
+
+// 高亮光标前后的括号以及与之匹配的括号
+func (p *CodeEditor) paintBrackets(gtx glayout.Context, caret int, scroll image.Point) {
+	for _, pos := range [2]int{caret - 1, caret} {
+		r, ok := p.runeAt(pos)
+		if !ok {
+			continue
+		}
+		i := strings.IndexRune(codeBrackets, r)
+		if i < 0 {
+			continue
+		}
+		if match := p.matchBracket(pos, i); match >= 0 {
+			p.fill(gtx, pos, pos+1, scroll, p.config.bracketColor)
+			p.fill(gtx, match, match+1, scroll, p.config.bracketColor)
+			return
+		}
+	}
+}
+
+// 获取字符位置的字符
+func (p *CodeEditor) runeAt(pos int) (rune, bool) {
+	c, ok := p.cursorAt(pos)
+	if !ok {
+		return 0, false
+	}
+	return c.rune(), true
+}
+
+// 逐个字符移动的位置，行尾的换行符也作为一个字符
+type codeCursor struct {
+	lines []codeLine
+	// 所在行
+	line int
+	// 在行中的字符位置
+	col int
+	// 在行文本中的字节位置
+	offset int
+}
+
+// 获取字符位置的游标，位置超出文字范围时返回false
+func (p *CodeEditor) cursorAt(pos int) (codeCursor, bool) {
+	if pos < 0 || len(p.lines) == 0 {
+		return codeCursor{}, false
+	}
+	c := codeCursor{lines: p.lines, line: p.lineAt(pos)}
+	line := p.lines[c.line]
+	c.col = pos - line.start
+	if c.col > line.runes {
+		return codeCursor{}, false
+	}
+	c.offset = len(line.text)
+	n := 0
+	for i := range line.text {
+		if n == c.col {
+			c.offset = i
+			break
+		}
+		n++
+	}
+	return c, true
+}
+
+// 游标处的字符
+func (c *codeCursor) rune() rune {
+	line := c.lines[c.line]
+	if c.col == line.runes {
+		return '\n'
+	}
+	r, _ := utf8.DecodeRuneInString(line.text[c.offset:])
+	return r
+}
+
+// 移动到下一个字符，已经是最后一个字符时返回false
+func (c *codeCursor) next() bool {
+	line := c.lines[c.line]
+	if c.col < line.runes {
+		_, size := utf8.DecodeRuneInString(line.text[c.offset:])
+		c.col, c.offset = c.col+1, c.offset+size
+		return true
+	}
+	if c.line+1 >= len(c.lines) {
+		return false
+	}
+	c.line, c.col, c.offset = c.line+1, 0, 0
+	return true
+}
+
+// 移动到上一个字符，已经是第一个字符时返回false
+func (c *codeCursor) prev() bool {
+	if c.col > 0 {
+		_, size := utf8.DecodeLastRuneInString(c.lines[c.line].text[:c.offset])
+		c.col, c.offset = c.col-1, c.offset-size
+		return true
+	}
+	if c.line == 0 {
+		return false
+	}
+	c.line--
+	line := c.lines[c.line]
+	c.col, c.offset = line.runes, len(line.text)
+	return true
+}
+
+// 查找与pos处括号匹配的括号，kind为括号在codeBrackets中的位置，没有时返回-1
+func (p *CodeEditor) matchBracket(pos, kind int) int {
+	open, close := rune(codeBrackets[kind&^1]), rune(codeBrackets[kind|1])
+	step, move := 1, (*codeCursor).next
+	if kind&1 == 1 {
+		step, move = -1, (*codeCursor).prev
+	}
+	c, ok := p.cursorAt(pos)
+	if !ok {
+		return -1
+	}
+	depth := 0
+	for i, n := pos, 0; n < codeBracketScan; i, n = i+step, n+1 {
+		switch c.rune() {
+		case open:
+			depth += step
+		case close:
+			depth -= step
+		}
+		if depth == 0 {
+			return i
+		}
+		if !move(&c) {
+			return -1
+		}
+	}
+	return -1
+}
+
+// 绘制一行带语法高亮的文字，返回第一行基线在编辑框中的位置
+func (p *CodeEditor) paintLine(gtx glayout.Context, index int, params gtext.Parameters, scroll image.Point) int {
+	line := p.lines[index]
+	editor := p.editor.editorMaterial.Editor
+	// 行首和行尾至少有一个可见，通过它们确定整行的位置
+	origin := noOrigin
+	p.regions = editor.Regions(line.start, line.start, p.regions[:0])
+	if len(p.regions) > 0 {
+		r := p.regions[0]
+		origin = r.Bounds.Max.Y - r.Baseline - scroll.Y
+	}
+	if line.runes == 0 {
+		return origin
+	}
+	theme.Shaper.LayoutString(params, line.text)
+	glyphs := p.glyphs[:0]
+	for g, ok := theme.Shaper.NextGlyph(); ok; g, ok = theme.Shaper.NextGlyph() {
+		glyphs = append(glyphs, g)
+	}
+	p.glyphs = glyphs
+	if len(glyphs) == 0 {
+		return origin
+	}
+	offset := origin
+	if origin != noOrigin {
+		offset = origin - int(glyphs[0].Y)
+	} else {
+		p.regions = editor.Regions(line.start+line.runes, line.start+line.runes, p.regions[:0])
+		if len(p.regions) == 0 {
+			return origin
+		}
+		r := p.regions[0]
+		offset = r.Bounds.Max.Y - r.Baseline - scroll.Y - int(glyphs[len(glyphs)-1].Y)
+	}
+	kinds := p.kinds(line)
+	colors := p.config.colors
+	if colors == nil {
+		colors = &syntax.Colors{}
+	}
+	stack := op.Offset(image.Pt(-scroll.X, offset)).Push(gtx.Ops)
+	rune0, runStart := 0, 0
+	var runColor color.NRGBA
+	clusterRune := 0
+	for i, g := range glyphs {
+		col := colors.Of(syntax.Plain)
+		if clusterRune < len(kinds) {
+			col = colors.Of(kinds[clusterRune])
+		}
+		if col.A == 0 {
+			col = p.config.textColor
+		}
+		// 颜色或行改变时绘制之前的字形
+		if i > runStart && (col != runColor || g.Y != glyphs[runStart].Y) {
+			textpaint.Glyphs(gtx, theme.Shaper, glyphs[runStart:i], runColor)
+			runStart = i
+		}
+		runColor = col
+		if g.Flags&gtext.FlagClusterBreak != 0 {
+			rune0 += int(g.Runes)
+			clusterRune = rune0
+		}
+	}
+	textpaint.Glyphs(gtx, theme.Shaper, glyphs[runStart:], runColor)
+	stack.Pop()
+	return origin
+}
+
+// 将一行的记号展开为每个字符的类型
+func (p *CodeEditor) kinds(line codeLine) []syntax.Kind {
+	kinds := make([]syntax.Kind, 0, line.runes)
+	t := 0
+	for i := range line.text {
+		for t < len(line.tokens) && line.tokens[t].End <= i {
+			t++
+		}
+		kind := syntax.Plain
+		if t < len(line.tokens) && line.tokens[t].Start <= i {
+			kind = line.tokens[t].Kind
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// 计算行号区域的宽度
+func (p *CodeEditor) gutterWidth(gtx glayout.Context, textSize fixed.Int26_6) int {
+	digits := maxInt(len(strconv.Itoa(len(p.lines))), 2)
+	params := gtext.Parameters{Font: p.editor.editorMaterial.Font, PxPerEm: textSize, MaxWidth: 1 << 24, Locale: gtx.Locale}
+	theme.Shaper.LayoutString(params, "0")
+	var advance fixed.Int26_6
+	for g, ok := theme.Shaper.NextGlyph(); ok; g, ok = theme.Shaper.NextGlyph() {
+		advance += g.Advance
+	}
+	return (advance * fixed.Int26_6(digits)).Ceil() + gtx.Dp(16)
+}
+
+// 在行号区域中右对齐绘制行号，baseline为基线位置
+func (p *CodeEditor) paintLineNumber(gtx glayout.Context, number, gutter, baseline int, size gunit.Sp, col color.NRGBA) {
+	padding := gtx.Dp(8)
+	lgtx := gtx
+	lgtx.Constraints = glayout.Constraints{
+		Min: image.Pt(gutter-padding, 0),
+		Max: image.Pt(gutter-padding, 1<<24),
+	}
+	macro := op.Record(gtx.Ops)
+	label := textpaint.Label{Alignment: text.End, MaxLines: 1}
+	dims := label.Layout(lgtx, theme.Shaper, p.editor.editorMaterial.Font, size, strconv.Itoa(number), col)
+	call := macro.Stop()
+	stack := op.Offset(image.Pt(0, baseline-(dims.Size.Y-dims.Baseline))).Push(gtx.Ops)
+	call.Add(gtx.Ops)
+	stack.Pop()
+}
+
+// 获取缩进单位
+func (p *CodeEditor) indentUnit() string {
+	if p.config.insertSpaces {
+		return strings.Repeat(" ", p.config.tabSize)
+	}
+	return "\t"
+}
+
+// 处理Tab和Shift+Tab，多行选择时缩进或取消缩进所有选中的行
+func (p *CodeEditor) handleTab(gtx glayout.Context) {
+	for _, e := range gtx.Events(&p.lines) {
+		e, ok := e.(key.Event)
+		if !ok || e.State != key.Press || e.Name != key.NameTab || !p.editable() {
+			continue
+		}
+		p.sync()
+		start, end := p.editor.GetSelection()
+		if start > end {
+			start, end = end, start
+		}
+		first, last := p.lineAt(start), p.lineAt(end)
+		shift := e.Modifiers.Contain(key.ModShift)
+		if first == last && !shift {
+			// 插入到下一个制表位
+			indent := p.indentUnit()
+			if p.config.insertSpaces {
+				col := start - p.lines[first].start
+				indent = strings.Repeat(" ", p.config.tabSize-col%p.config.tabSize)
+			}
+			p.editor.Insert(indent)
+			continue
+		}
+		// 选择区域结束在行首时不处理该行
+		if last > first && end == p.lines[last].start {
+			last--
+		}
+		p.indentLines(first, last, shift)
+	}
+}
+
+// 缩进或取消缩进指定范围的行，作为一步撤销，完成后选中这些行
+func (p *CodeEditor) indentLines(first, last int, outdent bool) {
+	p.editor.syncHistory()
+	unit := p.indentUnit()
+	delta := 0
+	for i := last; i >= first; i-- {
+		line := p.lines[i]
+		if !outdent {
+			p.editor.SetCaret(line.start, line.start).Insert(unit)
+			delta += utf8.RuneCountInString(unit)
+			continue
+		}
+		// 删除一个制表符或最多一个缩进单位的空格
+		n := 0
+		for n < len(line.text) && n < p.config.tabSize && line.text[n] == ' ' {
+			n++
+		}
+		if n == 0 && strings.HasPrefix(line.text, "\t") {
+			n = 1
+		}
+		if n > 0 {
+			p.editor.SetCaret(line.start, line.start+n).Insert("")
+			delta -= n
+		}
+	}
+	p.editor.syncHistory()
+	end := p.lines[last].start + p.lines[last].runes + delta
+	p.editor.SetCaret(end, p.lines[first].start)
+}
+
+// 处理拦截的回车，保持当前行的缩进，在左括号后增加一级缩进，返回是否修改了文字
+func (p *CodeEditor) handleEnter() bool {
+	enters := p.enters
+	p.enters = nil
+	changed := false
+	for _, e := range enters {
+		if e.State != key.Press || !p.editable() {
+			continue
+		}
+		p.sync()
+		start, end := p.editor.GetSelection()
+		caret := minInt(start, end)
+		line := p.lines[p.lineAt(caret)]
+		before := string([]rune(line.text)[:caret-line.start])
+		indent := before[:len(before)-len(strings.TrimLeft(before, " \t"))]
+		trimmed := strings.TrimRight(before, " \t")
+		insert := "\n" + indent
+		if trimmed != "" && strings.IndexByte("([{", trimmed[len(trimmed)-1]) >= 0 {
+			insert += p.indentUnit()
+			// 光标后是对应的右括号时，右括号移动到新的一行
+			if r, ok := p.runeAt(maxInt(start, end)); ok && strings.IndexRune(")]}", r) >= 0 {
+				p.editor.Insert(insert + "\n" + indent)
+				pos := caret + utf8.RuneCountInString(insert)
+				p.editor.SetCaret(pos, pos)
+				changed = true
+				continue
+			}
+		}
+		p.editor.Insert(insert)
+		changed = true
+	}
+	return changed
+}
+
+// 是否可以通过键盘修改文字
+func (p *CodeEditor) editable() bool {
+	return !p.editor.config.disabled && !p.editor.editorMaterial.Editor.ReadOnly
+}
+
+// 颜色的相对亮度，范围为0到1
+func luminance(c color.NRGBA) float32 {
+	return (0.2126*float32(c.R) + 0.7152*float32(c.G) + 0.0722*float32(c.B)) / 255
+}
+
+// 创建代码编辑框
+func NewCodeEditor() *CodeEditor {
+	editor := NewEditor("").FontFamily("monospace").WrapPolicy(text.WrapGraphemes)
+	// 文字由代码编辑框着色后绘制，编辑框只绘制光标和选择区域
+	editor.TextColor(0, 0, 0, 0)
+	code := &CodeEditor{
+		editor: editor,
+		margin: &glayout.Inset{},
+		config: &codeEditorConfig{
+			update:          true,
+			themed:          newThemed(),
+			lineNumbers:     true,
+			highlightLine:   true,
+			bracketMatching: true,
+			tabSize:         4,
+			insertSpaces:    true,
+			autoIndent:      true,
+		},
+	}
+	editor.SetParent(code)
+	return code
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,724 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/Seikaijyu/nenki.ui/utils"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	"github.com/Seikaijyu/gio/f32"
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gtext "github.com/Seikaijyu/gio/text"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &ColorPicker{}
+
+// 默认的色板
+var defaultSwatches = []string{
+	"#f44336", "#e91e63", "#9c27b0", "#673ab7", "#3f51b5", "#2196f3", "#03a9f4", "#00bcd4",
+	"#009688", "#4caf50", "#8bc34a", "#cddc39", "#ffeb3b", "#ffc107", "#ff9800", "#ff5722",
+	"#795548", "#9e9e9e", "#607d8b", "#000000", "#ffffff",
+}
+
+type colorPickerConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 颜色改变事件
+	_changed func(r, g, b, a uint8)
+	// 组件宽度
+	width gunit.Dp
+	// 是否可以调整透明度
+	showAlpha bool
+	// 最多保存的最近使用颜色数量
+	recentMax int
+	// 输入框修改了颜色但还没有记录到最近使用
+	edited bool
+}
+
+// 颜色选择器，由饱和度/明度方块、色相和透明度滑块、十六进制和RGB输入框、色板以及最近使用的颜色组成
+//
+// 颜色字符串使用utils.ParseColor解析，所有颜色都使用(r, g, b, a uint8)表示
+type ColorPicker struct {
+	// 组件标识
+	identity
+	// 配置
+	config *colorPickerConfig
+	// 外边距
+	margin *glayout.Inset
+	// 色相，0-360
+	hue float32
+	// 饱和度和明度，0-1
+	saturation, brightness float32
+	// 当前颜色
+	color color.NRGBA
+	// 饱和度/明度方块、色相滑块和透明度滑块的拖动
+	squareDrag, hueDrag, alphaDrag gesture.Drag
+	// 上次渲染时方块和滑块的大小，用于将拖动位置转换为数值
+	squareSize, barSize image.Point
+	// 十六进制输入框
+	hex *Editor
+	// R、G、B、A输入框
+	channels [4]*Editor
+	// 上一帧获得焦点的输入框
+	focused *Editor
+	// 色板
+	swatches []color.NRGBA
+	// 最近使用的颜色，最新的在前
+	recent []color.NRGBA
+	// 色板和最近使用颜色的点击
+	swatchClicks, recentClicks []gesture.Click
+	// 边框颜色
+	borderColor color.NRGBA
+	// 说明文字颜色
+	labelColor color.NRGBA
+}
+
+// 绑定函数
+func (p *ColorPicker) Then(fn func(self *ColorPicker)) *ColorPicker {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *ColorPicker) ID(id string) *ColorPicker {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *ColorPicker) Class(classes ...string) *ColorPicker {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *ColorPicker) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *ColorPicker) Destroy() {
+	p.config.update = false
+	p.hex.Destroy()
+	for _, editor := range p.channels {
+		editor.Destroy()
+	}
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *ColorPicker) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 注册颜色改变事件，拖动、点击色板或在输入框中输入后触发，使用Value或Hex设置颜色时不触发
+func (p *ColorPicker) OnColorChanged(fn func(r, g, b, a uint8)) *ColorPicker {
+	p.config._changed = fn
+	return p
+}
+
+// 是否更新组件
+func (p *ColorPicker) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不响应任何输入
+func (p *ColorPicker) Disabled(disabled bool) *ColorPicker {
+	p.config.disabled = disabled
+	p.hex.Disabled(disabled)
+	for _, editor := range p.channels {
+		editor.Disabled(disabled)
+	}
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *ColorPicker) GetState() State {
+	return stateOf(false, p.focused != nil, p.config.disabled, false)
+}
+
+// 外边距
+func (p *ColorPicker) Margin(Top, Left, Bottom, Right float32) *ColorPicker {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *ColorPicker) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 组件宽度，默认为240
+func (p *ColorPicker) Width(width float32) *ColorPicker {
+	p.config.width = gunit.Dp(width)
+	return p
+}
+
+// 设置颜色，不会触发事件
+func (p *ColorPicker) Value(r, g, b, a uint8) *ColorPicker {
+	p.setColor(color.NRGBA{R: r, G: g, B: b, A: a}, nil, false)
+	return p
+}
+
+// 获取颜色
+func (p *ColorPicker) GetValue() (r, g, b, a uint8) {
+	return p.color.R, p.color.G, p.color.B, p.color.A
+}
+
+// 使用颜色字符串设置颜色，例如#ff0000、#ff000080、rgb(255, 0, 0)或red，不会触发事件
+//
+// 无效的颜色会被忽略
+func (p *ColorPicker) Hex(hex string) *ColorPicker {
+	c, err := utils.ParseColor(hex)
+	if err != nil {
+		return p
+	}
+	return p.Value(c.RGBA8())
+}
+
+// 获取十六进制颜色，不透明时为#rrggbb，否则为#rrggbbaa
+func (p *ColorPicker) GetHex() string {
+	return colorHex(p.color)
+}
+
+// 是否可以调整透明度，默认可以，关闭时颜色总是不透明
+func (p *ColorPicker) ShowAlpha(show bool) *ColorPicker {
+	p.config.showAlpha = show
+	if !show && p.color.A != 0xff {
+		c := p.color
+		c.A = 0xff
+		p.setColor(c, nil, false)
+	}
+	return p
+}
+
+// 设置色板，颜色格式与Hex相同，无效的颜色会被跳过，没有颜色时不显示色板
+func (p *ColorPicker) Swatches(hex ...string) *ColorPicker {
+	p.swatches = p.swatches[:0]
+	for _, h := range hex {
+		c, err := utils.ParseColor(h)
+		if err != nil {
+			continue
+		}
+		p.swatches = append(p.swatches, c.NRGBA())
+	}
+	return p
+}
+
+// 最多保存的最近使用颜色数量，默认为8，为0时不显示最近使用的颜色
+func (p *ColorPicker) RecentColors(max int) *ColorPicker {
+	p.config.recentMax = maxInt(max, 0)
+	if len(p.recent) > p.config.recentMax {
+		p.recent = p.recent[:p.config.recentMax]
+	}
+	return p
+}
+
+// 添加最近使用的颜色，例如恢复上次保存的记录
+func (p *ColorPicker) AddRecentColor(r, g, b, a uint8) *ColorPicker {
+	p.remember(color.NRGBA{R: r, G: g, B: b, A: a})
+	return p
+}
+
+// 获取最近使用的颜色，使用十六进制字符串表示，最新的在前
+func (p *ColorPicker) GetRecentColors() []string {
+	colors := make([]string, len(p.recent))
+	for i, c := range p.recent {
+		colors[i] = colorHex(c)
+	}
+	return colors
+}
+
+// 边框颜色，用于色板、滑块和预览
+func (p *ColorPicker) BorderColor(r, g, b, a uint8) *ColorPicker {
+	p.borderColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBorderColor)
+	return p
+}
+
+// 渲染组件
+func (p *ColorPicker) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	p.update(gtx)
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		width := minInt(gtx.Dp(p.config.width), gtx.Constraints.Max.X)
+		gtx.Constraints = glayout.Constraints{Min: image.Pt(width, 0), Max: image.Pt(width, gtx.Constraints.Max.Y)}
+		gap := glayout.Spacer{Height: 8}.Layout
+		children := []glayout.FlexChild{
+			glayout.Rigid(p.layoutSquare),
+			glayout.Rigid(gap),
+			glayout.Rigid(p.layoutSliders),
+			glayout.Rigid(gap),
+			glayout.Rigid(p.layoutInputs),
+		}
+		if len(p.swatches) > 0 {
+			children = append(children, glayout.Rigid(gap), glayout.Rigid(func(gtx glayout.Context) glayout.Dimensions {
+				return p.layoutSwatches(gtx, p.swatches, &p.swatchClicks)
+			}))
+		}
+		if p.config.recentMax > 0 && len(p.recent) > 0 {
+			children = append(children, glayout.Rigid(gap), glayout.Rigid(func(gtx glayout.Context) glayout.Dimensions {
+				return p.layoutSwatches(gtx, p.recent, &p.recentClicks)
+			}))
+		}
+		return glayout.Flex{Axis: glayout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// 处理拖动、色板的点击和输入框失去焦点
+func (p *ColorPicker) update(gtx glayout.Context) {
+	for _, e := range p.squareDrag.Update(gtx.Metric, gtx, gesture.Both) {
+		p.dragged(e, p.squareSize, func(x, y float32) {
+			p.setHSV(p.hue, x, 1-y, p.color.A)
+		})
+	}
+	for _, e := range p.hueDrag.Update(gtx.Metric, gtx, gesture.Horizontal) {
+		p.dragged(e, p.barSize, func(x, _ float32) {
+			p.setHSV(x*360, p.saturation, p.brightness, p.color.A)
+		})
+	}
+	for _, e := range p.alphaDrag.Update(gtx.Metric, gtx, gesture.Horizontal) {
+		p.dragged(e, p.barSize, func(x, _ float32) {
+			p.setHSV(p.hue, p.saturation, p.brightness, uint8(math.Round(float64(x*0xff))))
+		})
+	}
+	for i := range p.swatchClicks {
+		for _, e := range p.swatchClicks[i].Update(gtx) {
+			if e.Kind == gesture.KindClick && i < len(p.swatches) {
+				p.pick(p.swatches[i])
+			}
+		}
+	}
+	for i := range p.recentClicks {
+		for _, e := range p.recentClicks[i].Update(gtx) {
+			if e.Kind == gesture.KindClick && i < len(p.recent) {
+				p.pick(p.recent[i])
+			}
+		}
+	}
+	// 输入框失去焦点时修正输入，并记录输入的颜色
+	focused := p.focusedInput()
+	if p.focused != nil && p.focused != focused {
+		p.syncInputs(nil)
+		if p.config.edited {
+			p.remember(p.color)
+		}
+	}
+	p.focused = focused
+}
+
+// 处理拖动事件，fn的参数为指针在区域中的相对位置，范围为0-1，松开时记录颜色
+func (p *ColorPicker) dragged(e pointer.Event, size image.Point, fn func(x, y float32)) {
+	switch e.Kind {
+	case pointer.Press, pointer.Drag:
+		fn(clamp01(e.Position.X/float32(maxInt(size.X, 1))), clamp01(e.Position.Y/float32(maxInt(size.Y, 1))))
+	case pointer.Release:
+		p.remember(p.color)
+	}
+}
+
+// 选择色板中的颜色
+func (p *ColorPicker) pick(c color.NRGBA) {
+	if !p.config.showAlpha {
+		c.A = 0xff
+	}
+	p.setColor(c, nil, true)
+	p.remember(c)
+}
+
+// 获得焦点的输入框，没有时返回nil
+func (p *ColorPicker) focusedInput() *Editor {
+	if p.hex.editorMaterial.Editor.Focused() {
+		return p.hex
+	}
+	for _, editor := range p.channels {
+		if editor.editorMaterial.Editor.Focused() {
+			return editor
+		}
+	}
+	return nil
+}
+
+// 使用HSV设置颜色并触发事件
+func (p *ColorPicker) setHSV(h, s, v float32, a uint8) {
+	p.hue, p.saturation, p.brightness = clampRange(h, 0, 360), clamp01(s), clamp01(v)
+	if !p.config.showAlpha {
+		a = 0xff
+	}
+	c := hsvToRGB(p.hue, p.saturation, p.brightness)
+	c.A = a
+	p.apply(c, nil, true)
+}
+
+// 使用RGB设置颜色，except为正在输入的输入框，不会更新它的文字
+func (p *ColorPicker) setColor(c color.NRGBA, except *Editor, notify bool) {
+	h, s, v := rgbToHSV(c)
+	// 灰色没有色相，黑色没有饱和度，保留原来的值以免滑块跳动
+	if s > 0 && v > 0 {
+		p.hue = h
+	}
+	if v > 0 {
+		p.saturation = s
+	}
+	p.brightness = v
+	p.apply(c, except, notify)
+}
+
+// 更新颜色和输入框，颜色改变且notify为true时触发事件
+func (p *ColorPicker) apply(c color.NRGBA, except *Editor, notify bool) {
+	changed := c != p.color
+	p.color = c
+	p.syncInputs(except)
+	if changed && notify && p.config._changed != nil {
+		p.config._changed(c.R, c.G, c.B, c.A)
+	}
+}
+
+// 将颜色显示到输入框中，except为正在输入的输入框
+func (p *ColorPicker) syncInputs(except *Editor) {
+	set := func(editor *Editor, text string) {
+		if editor != except && editor.GetText() != text {
+			editor.Text(text)
+		}
+	}
+	set(p.hex, colorHex(p.color))
+	values := [4]uint8{p.color.R, p.color.G, p.color.B, p.color.A}
+	for i, editor := range p.channels {
+		set(editor, strconv.Itoa(int(values[i])))
+	}
+}
+
+// 记录最近使用的颜色，已经存在时移到最前面
+func (p *ColorPicker) remember(c color.NRGBA) {
+	p.config.edited = false
+	if p.config.recentMax <= 0 {
+		return
+	}
+	recent := []color.NRGBA{c}
+	for _, r := range p.recent {
+		if r != c && len(recent) < p.config.recentMax {
+			recent = append(recent, r)
+		}
+	}
+	p.recent = recent
+}
+
+// 十六进制输入框的内容改变
+func (p *ColorPicker) hexChanged(text string) {
+	if !p.hex.editorMaterial.Editor.Focused() {
+		return
+	}
+	// 只在输入了完整的颜色时生效
+	c, err := utils.ParseHex(text)
+	if err != nil {
+		return
+	}
+	if !p.config.showAlpha {
+		c.A = 0xff
+	}
+	p.setColor(c.NRGBA(), p.hex, true)
+	p.config.edited = true
+}
+
+// R、G、B、A输入框的内容改变
+func (p *ColorPicker) channelChanged(i int, text string) {
+	if !p.channels[i].editorMaterial.Editor.Focused() {
+		return
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return
+	}
+	value = minInt(maxInt(value, 0), 0xff)
+	c := p.color
+	switch i {
+	case 0:
+		c.R = uint8(value)
+	case 1:
+		c.G = uint8(value)
+	case 2:
+		c.B = uint8(value)
+	case 3:
+		c.A = uint8(value)
+	}
+	p.setColor(c, p.channels[i], true)
+	p.config.edited = true
+}
+
+// 渲染饱和度/明度方块
+func (p *ColorPicker) layoutSquare(gtx glayout.Context) glayout.Dimensions {
+	size := image.Pt(gtx.Constraints.Max.X, gtx.Dp(160))
+	p.squareSize = size
+	rect := image.Rectangle{Max: size}
+	defer clip.Rect(rect).Push(gtx.Ops).Pop()
+	paint.ColorOp{Color: hsvToRGB(p.hue, 1, 1)}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	// 从左到右饱和度增加，从上到下明度降低
+	paint.LinearGradientOp{
+		Stop1: f32.Pt(0, 0), Color1: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		Stop2: f32.Pt(float32(size.X), 0), Color2: color.NRGBA{R: 0xff, G: 0xff, B: 0xff},
+	}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	paint.LinearGradientOp{
+		Stop1: f32.Pt(0, 0), Color1: color.NRGBA{},
+		Stop2: f32.Pt(0, float32(size.Y)), Color2: color.NRGBA{A: 0xff},
+	}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	p.paintBorder(gtx, rect)
+	center := image.Pt(int(p.saturation*float32(size.X)), int((1-p.brightness)*float32(size.Y)))
+	p.paintHandle(gtx, center, gtx.Dp(6))
+	pointer.CursorCrosshair.Add(gtx.Ops)
+	p.squareDrag.Add(gtx.Ops)
+	return glayout.Dimensions{Size: size}
+}
+
+// 渲染预览、色相滑块和透明度滑块
+func (p *ColorPicker) layoutSliders(gtx glayout.Context) glayout.Dimensions {
+	barHeight, gap := gtx.Dp(12), gtx.Dp(8)
+	height := barHeight
+	if p.config.showAlpha {
+		height = barHeight*2 + gap
+	}
+	// 左侧的预览
+	preview := image.Rect(0, 0, height, height)
+	paintChecker(gtx, preview, gtx.Dp(4))
+	paint.FillShape(gtx.Ops, p.color, clip.Rect(preview).Op())
+	p.paintBorder(gtx, preview)
+
+	left := height + gap
+	width := maxInt(gtx.Constraints.Max.X-left, 1)
+	// 色相
+	hue := image.Rect(left, 0, left+width, barHeight)
+	p.barSize = hue.Size()
+	for i := 0; i < 6; i++ {
+		x0 := hue.Min.X + width*i/6
+		x1 := hue.Min.X + width*(i+1)/6
+		stack := clip.Rect(image.Rect(x0, hue.Min.Y, x1, hue.Max.Y)).Push(gtx.Ops)
+		paint.LinearGradientOp{
+			Stop1: f32.Pt(float32(x0), 0), Color1: hsvToRGB(float32(i)*60, 1, 1),
+			Stop2: f32.Pt(float32(x1), 0), Color2: hsvToRGB(float32(i+1)*60, 1, 1),
+		}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		stack.Pop()
+	}
+	p.paintBorder(gtx, hue)
+	p.paintHandle(gtx, image.Pt(hue.Min.X+int(p.hue/360*float32(width)), barHeight/2), barHeight/2)
+	p.addDrag(gtx, &p.hueDrag, hue)
+
+	// 透明度
+	if p.config.showAlpha {
+		alpha := hue.Add(image.Pt(0, barHeight+gap))
+		paintChecker(gtx, alpha, gtx.Dp(4))
+		opaque := p.color
+		opaque.A = 0xff
+		transparent := opaque
+		transparent.A = 0
+		stack := clip.Rect(alpha).Push(gtx.Ops)
+		paint.LinearGradientOp{
+			Stop1: f32.Pt(float32(alpha.Min.X), 0), Color1: transparent,
+			Stop2: f32.Pt(float32(alpha.Max.X), 0), Color2: opaque,
+		}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		stack.Pop()
+		p.paintBorder(gtx, alpha)
+		p.paintHandle(gtx, image.Pt(alpha.Min.X+int(float32(p.color.A)/0xff*float32(width)), alpha.Min.Y+barHeight/2), barHeight/2)
+		p.addDrag(gtx, &p.alphaDrag, alpha)
+	}
+	return glayout.Dimensions{Size: image.Pt(gtx.Constraints.Max.X, height)}
+}
+
+// 在区域中注册拖动，拖动位置相对于区域的左上角
+func (p *ColorPicker) addDrag(gtx glayout.Context, drag *gesture.Drag, rect image.Rectangle) {
+	stack := op.Offset(rect.Min).Push(gtx.Ops)
+	area := clip.Rect{Max: rect.Size()}.Push(gtx.Ops)
+	pointer.CursorPointer.Add(gtx.Ops)
+	drag.Add(gtx.Ops)
+	area.Pop()
+	stack.Pop()
+}
+
+// 渲染十六进制和R、G、B、A输入框
+func (p *ColorPicker) layoutInputs(gtx glayout.Context) glayout.Dimensions {
+	th := theme.Current()
+	labels := []string{"HEX", "R", "G", "B", "A"}
+	editors := []*Editor{p.hex, p.channels[0], p.channels[1], p.channels[2], p.channels[3]}
+	if !p.config.showAlpha {
+		labels, editors = labels[:4], editors[:4]
+	}
+	children := make([]glayout.FlexChild, 0, len(editors)*2)
+	for i := range editors {
+		label, editor := labels[i], editors[i]
+		weight := float32(1)
+		if i == 0 {
+			weight = 2.2
+		}
+		if i > 0 {
+			children = append(children, glayout.Rigid(glayout.Spacer{Width: 4}.Layout))
+		}
+		children = append(children, glayout.Flexed(weight, func(gtx glayout.Context) glayout.Dimensions {
+			return glayout.Flex{Axis: glayout.Vertical}.Layout(gtx,
+				glayout.Rigid(func(gtx glayout.Context) glayout.Dimensions {
+					return textpaint.Label{MaxLines: 1}.Layout(gtx, theme.Shaper, gfont.Font{}, gunit.Sp(th.Caption), label, p.labelColor)
+				}),
+				glayout.Rigid(editor.Layout),
+			)
+		}))
+	}
+	return glayout.Flex{}.Layout(gtx, children...)
+}
+
+// 渲染色板，颜色按宽度自动换行
+func (p *ColorPicker) layoutSwatches(gtx glayout.Context, colors []color.NRGBA, clicks *[]gesture.Click) glayout.Dimensions {
+	for len(*clicks) < len(colors) {
+		*clicks = append(*clicks, gesture.Click{})
+	}
+	size, gap := gtx.Dp(20), gtx.Dp(4)
+	columns := maxInt((gtx.Constraints.Max.X+gap)/(size+gap), 1)
+	for i, c := range colors {
+		off := image.Pt(i%columns*(size+gap), i/columns*(size+gap))
+		rect := image.Rectangle{Min: off, Max: off.Add(image.Pt(size, size))}
+		if c.A != 0xff {
+			paintChecker(gtx, rect, gtx.Dp(4))
+		}
+		paint.FillShape(gtx.Ops, c, clip.Rect(rect).Op())
+		border := p.borderColor
+		if c == p.color {
+			border = p.labelColor
+		}
+		paint.FillShape(gtx.Ops, border, clip.Stroke{Path: clip.Rect(rect).Path(), Width: float32(gtx.Dp(1))}.Op())
+		area := clip.Rect(rect).Push(gtx.Ops)
+		pointer.CursorPointer.Add(gtx.Ops)
+		(*clicks)[i].Add(gtx.Ops)
+		area.Pop()
+	}
+	rows := (len(colors) + columns - 1) / columns
+	return glayout.Dimensions{Size: image.Pt(gtx.Constraints.Max.X, maxInt(rows*(size+gap)-gap, 0))}
+}
+
+// 绘制边框
+func (p *ColorPicker) paintBorder(gtx glayout.Context, rect image.Rectangle) {
+	paint.FillShape(gtx.Ops, p.borderColor, clip.Stroke{Path: clip.Rect(rect).Path(), Width: float32(gtx.Dp(1))}.Op())
+}
+
+// 绘制拖动的圆点，白色圆环外加深色描边，在任何颜色上都能看清
+func (p *ColorPicker) paintHandle(gtx glayout.Context, center image.Point, radius int) {
+	rect := image.Rectangle{Min: center.Sub(image.Pt(radius, radius)), Max: center.Add(image.Pt(radius, radius))}
+	width := float32(gtx.Dp(1))
+	paint.FillShape(gtx.Ops, color.NRGBA{A: 0x80}, clip.Stroke{Path: clip.Ellipse(rect.Inset(-1)).Path(gtx.Ops), Width: width}.Op())
+	paint.FillShape(gtx.Ops, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, clip.Stroke{Path: clip.Ellipse(rect).Path(gtx.Ops), Width: width * 2}.Op())
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *ColorPicker) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideBorderColor) {
+		p.borderColor = withAlpha(th.Text, 0x40)
+	}
+	p.labelColor = th.TextSecondary
+}
+
+// 创建颜色选择器，默认颜色为不透明的白色，使用默认色板
+func NewColorPicker() *ColorPicker {
+	p := &ColorPicker{
+		margin: &glayout.Inset{},
+		config: &colorPickerConfig{update: true, themed: newThemed(), width: 240, showAlpha: true, recentMax: 8},
+	}
+	p.hex = NewEditor("").SingleLine(true).Submit(true).AllowOnly("#0123456789abcdefABCDEF").MaxLength(9).
+		OnChange(func(_ *Editor, text string) {
+			p.hexChanged(text)
+		}).
+		OnSubmit(func(_ *Editor, _ string) {
+			p.syncInputs(nil)
+			p.remember(p.color)
+		})
+	p.hex.SetParent(p)
+	for i := range p.channels {
+		i := i
+		p.channels[i] = NewEditor("").SingleLine(true).Submit(true).AllowOnly("0123456789").MaxLength(3).
+			Alignment(gtext.Middle).
+			OnChange(func(_ *Editor, text string) {
+				p.channelChanged(i, text)
+			}).
+			OnSubmit(func(_ *Editor, _ string) {
+				p.syncInputs(nil)
+				p.remember(p.color)
+			})
+		p.channels[i].SetParent(p)
+	}
+	p.Swatches(defaultSwatches...)
+	p.Value(0xff, 0xff, 0xff, 0xff)
+	return p
+}
+
+// 十六进制颜色，不透明时省略透明度
+func colorHex(c color.NRGBA) string {
+	return utils.FromColor(c).Hex()
+}
+
+// 绘制透明背景的棋盘格
+func paintChecker(gtx glayout.Context, rect image.Rectangle, cell int) {
+	defer clip.Rect(rect).Push(gtx.Ops).Pop()
+	paint.ColorOp{Color: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	dark := color.NRGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff}
+	for y := rect.Min.Y; y < rect.Max.Y; y += cell {
+		for x := rect.Min.X + ((y-rect.Min.Y)/cell%2)*cell; x < rect.Max.X; x += cell * 2 {
+			paint.FillShape(gtx.Ops, dark, clip.Rect(image.Rect(x, y, x+cell, y+cell)).Op())
+		}
+	}
+}
+
+// HSV转换为RGB，h为0-360，s和v为0-1
+func hsvToRGB(h, s, v float32) color.NRGBA {
+	return utils.HSV(float64(h), float64(s), float64(v)).NRGBA()
+}
+
+// RGB转换为HSV，h为0-360，s和v为0-1
+func rgbToHSV(c color.NRGBA) (h, s, v float32) {
+	fh, fs, fv := utils.FromColor(c).HSV()
+	return float32(fh), float32(fs), float32(fv)
+}
+
+// 限制在0-1之间
+func clamp01(v float32) float32 {
+	return clampRange(v, 0, 1)
+}
+
+// 限制在min和max之间
+func clampRange(v, min, max float32) float32 {
+	return float32(math.Max(float64(min), math.Min(float64(max), float64(v))))
+}
@@ -15,6 +15,8 @@ type columnLayoutConfig struct {
 }
 
 type ColumnLayout struct {
+	// 组件标识
+	identity
 	config         *columnLayoutConfig
 	margin         *glayout.Inset
 	childWidgets   []WidgetInterface
@@ -32,6 +34,18 @@ func (p *ColumnLayout) Then(fn func(self *ColumnLayout)) *ColumnLayout {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *ColumnLayout) ID(id string) *ColumnLayout {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *ColumnLayout) Class(classes ...string) *ColumnLayout {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *ColumnLayout) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -56,8 +70,10 @@ func (p *ColumnLayout) Destroy() {
 func (p *ColumnLayout) ResetParent(child WidgetInterface) {
 	child.Destroy()
 	child.Update(true)
+	setParent(child, p)
 	child.OnDestroy(func() {
 		child.Update(false)
+		setParent(child, nil)
 		p.RemoveChild(child)
 	})
 }
@@ -147,6 +163,11 @@ func (p *ColumnLayout) Margin(Top, Left, Bottom, Right float32) *ColumnLayout {
 	return p
 }
 
+// 获取外边距
+func (p *ColumnLayout) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 渲染UI
 func (p *ColumnLayout) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
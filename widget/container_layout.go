@@ -1,11 +1,12 @@
 package widget
 
 import (
+	"image"
 	"image/color"
 
+	"github.com/Seikaijyu/nenki.ui/widget/gradient"
+
 	glayout "github.com/Seikaijyu/gio/layout"
-	"github.com/Seikaijyu/gio/op/clip"
-	"github.com/Seikaijyu/gio/op/paint"
 	gunit "github.com/Seikaijyu/gio/unit"
 )
 
@@ -20,10 +21,16 @@ type containerConfig struct {
 	_destroy func()
 	// 背景颜色
 	background *color.NRGBA
+	// 圆角，背景、阴影和子节点的裁剪都使用这个圆角
+	cornerRadius float32
+	// 渐变、阴影和内边框
+	decoration decoration
 }
 
 // 容器布局，只用于包裹组件
 type ContainerLayout struct {
+	// 组件标识
+	identity
 	// 配置
 	config *containerConfig
 	// 外边距
@@ -38,6 +45,18 @@ func (p *ContainerLayout) Then(fn func(self *ContainerLayout)) *ContainerLayout
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *ContainerLayout) ID(id string) *ContainerLayout {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *ContainerLayout) Class(classes ...string) *ContainerLayout {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *ContainerLayout) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -52,8 +71,10 @@ func (p *ContainerLayout) Update(update bool) {
 func (p *ContainerLayout) ResetParent(child WidgetInterface) {
 	child.Destroy()
 	child.Update(true)
+	setParent(child, p)
 	child.OnDestroy(func() {
 		child.Update(false)
+		setParent(child, nil)
 		p.RemoveChild()
 	})
 }
@@ -76,6 +97,27 @@ func (p *ContainerLayout) RemoveChild() *ContainerLayout {
 	return p
 }
 
+// 遍历以自身为根的组件树，fn返回false时不再遍历该组件的子节点
+func (p *ContainerLayout) Walk(fn func(w WidgetInterface) bool) *ContainerLayout {
+	Walk(p, fn)
+	return p
+}
+
+// 通过ID查找组件，不存在时返回nil
+func (p *ContainerLayout) FindByID(id string) WidgetInterface {
+	return FindByID(p, id)
+}
+
+// 查找所有包含指定类名的组件
+func (p *ContainerLayout) FindByClass(class string) []WidgetInterface {
+	return FindByClass(p, class)
+}
+
+// 查找所有满足条件的组件
+func (p *ContainerLayout) FindAll(fn func(w WidgetInterface) bool) []WidgetInterface {
+	return FindAll(p, fn)
+}
+
 // 删除自身
 func (p *ContainerLayout) Destroy() {
 	p.config.update = false
@@ -97,6 +139,11 @@ func (p *ContainerLayout) Margin(Top, Left, Bottom, Right float32) *ContainerLay
 	return p
 }
 
+// 获取外边距
+func (p *ContainerLayout) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 背景颜色
 func (p *ContainerLayout) Background(r, g, b, a uint8) *ContainerLayout {
 	p.config.background = &color.NRGBA{
@@ -108,21 +155,74 @@ func (p *ContainerLayout) Background(r, g, b, a uint8) *ContainerLayout {
 	return p
 }
 
+// 获取背景颜色
+func (p *ContainerLayout) GetBackground() (r, g, b, a uint8) {
+	if p.config.background == nil {
+		return 0, 0, 0, 0
+	}
+	return rgba(*p.config.background)
+}
+
+// 圆角
+func (p *ContainerLayout) CornerRadius(radius float32) *ContainerLayout {
+	p.config.cornerRadius = radius
+	return p
+}
+
+// 获取圆角
+func (p *ContainerLayout) GetCornerRadius() float32 {
+	return p.config.cornerRadius
+}
+
+// 线性渐变背景，angle为渐变方向的角度，0度从下到上，90度从左到右，180度从上到下
+//
+// 设置渐变后背景颜色不再生效，没有颜色节点时清除渐变
+func (p *ContainerLayout) LinearGradient(angle float32, stops ...gradient.Stop) *ContainerLayout {
+	p.config.decoration.linear(angle, stops)
+	return p
+}
+
+// 径向渐变背景，centerX和centerY为圆心相对于容器大小的位置，0.5表示居中，
+// radius为半径相对于圆心到最远角距离的比例，1表示刚好到达最远的角
+//
+// 设置渐变后背景颜色不再生效，没有颜色节点时清除渐变
+func (p *ContainerLayout) RadialGradient(centerX, centerY, radius float32, stops ...gradient.Stop) *ContainerLayout {
+	p.config.decoration.radial(centerX, centerY, radius, stops)
+	return p
+}
+
+// 阴影，offsetX和offsetY为偏移，blur为模糊半径，spread为扩展距离，颜色完全透明时清除阴影
+func (p *ContainerLayout) Shadow(offsetX, offsetY, blur, spread float32, r, g, b, a uint8) *ContainerLayout {
+	p.config.decoration.setShadow(offsetX, offsetY, blur, spread, color.NRGBA{R: r, G: g, B: b, A: a})
+	return p
+}
+
+// 内边框，绘制在容器边缘内侧并覆盖在子节点上方，宽度为0时清除内边框
+func (p *ContainerLayout) InnerBorder(width float32, r, g, b, a uint8) *ContainerLayout {
+	p.config.decoration.setInner(width, color.NRGBA{R: r, G: g, B: b, A: a})
+	return p
+}
+
 // 渲染
 func (p *ContainerLayout) Layout(gtx glayout.Context) (dimensions glayout.Dimensions) {
 	if !p.config.update || p.childWidget == nil {
 		return glayout.Dimensions{Size: gtx.Constraints.Max}
 	}
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-		var stack = clip.Rect{Max: gtx.Constraints.Max}.Push(gtx.Ops)
+		shape := roundedRect(image.Rectangle{Max: gtx.Constraints.Max}, gtx.Dp(gunit.Dp(p.config.cornerRadius)))
+		decoration := &p.config.decoration
+		decoration.paintShadow(gtx, shape)
+		var stack = shape.Push(gtx.Ops)
 		defer stack.Pop()
 		// 设置背景颜色
+		var background color.NRGBA
 		if p.config.background != nil {
-			paint.ColorOp{Color: *p.config.background}.Add(gtx.Ops)
-			paint.PaintOp{}.Add(gtx.Ops)
+			background = *p.config.background
 		}
-		return p.childWidget.Layout(gtx)
-
+		decoration.paintFill(gtx, shape, background)
+		dims := p.childWidget.Layout(gtx)
+		decoration.paintInner(gtx, shape)
+		return dims
 	})
 }
 
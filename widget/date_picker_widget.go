@@ -0,0 +1,318 @@
+package widget
+
+import (
+	"image/color"
+	"time"
+
+	glayout "github.com/Seikaijyu/gio/layout"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &DatePicker{}
+
+type datePickerConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 值改变事件
+	_changed func(*DatePicker, time.Time)
+	// 输入框显示的格式
+	format string
+}
+
+// 日期选择器，点击后弹出日历，支持切换年月、限制可选范围和禁用指定日期
+//
+// 月份和星期的名称根据系统语言显示，也可以使用Locale指定语言
+type DatePicker struct {
+	// 组件标识
+	identity
+	// 配置
+	config *datePickerConfig
+	// 外边距
+	margin *glayout.Inset
+	// 选中的日期，零值表示没有选中
+	value time.Time
+	// 输入框和弹出层
+	field pickerField
+	// 日历
+	calendar calendarView
+}
+
+// 绑定函数
+func (p *DatePicker) Then(fn func(self *DatePicker)) *DatePicker {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *DatePicker) ID(id string) *DatePicker {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *DatePicker) Class(classes ...string) *DatePicker {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *DatePicker) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *DatePicker) Destroy() {
+	p.config.update = false
+	p.field.open = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *DatePicker) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 注册值改变事件，在日历中选择日期后触发，日期的时间部分为0点
+func (p *DatePicker) OnChanged(fn func(p *DatePicker, value time.Time)) *DatePicker {
+	p.config._changed = fn
+	return p
+}
+
+// 是否更新组件
+func (p *DatePicker) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不响应任何输入并关闭日历
+func (p *DatePicker) Disabled(disabled bool) *DatePicker {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *DatePicker) GetState() State {
+	return stateOf(p.field.click.Hovered(), p.field.focused, p.config.disabled, false)
+}
+
+// 外边距
+func (p *DatePicker) Margin(Top, Left, Bottom, Right float32) *DatePicker {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *DatePicker) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 设置选中的日期，零值表示清除选择，不会触发事件
+func (p *DatePicker) Value(value time.Time) *DatePicker {
+	p.value = value
+	if !value.IsZero() {
+		p.calendar.show(value)
+	}
+	return p
+}
+
+// 获取选中的日期，没有选中时返回零值
+func (p *DatePicker) GetValue() time.Time {
+	return p.value
+}
+
+// 可以选择的最早日期，零值表示不限制
+func (p *DatePicker) MinDate(date time.Time) *DatePicker {
+	p.calendar.min = date
+	return p
+}
+
+// 可以选择的最晚日期，零值表示不限制
+func (p *DatePicker) MaxDate(date time.Time) *DatePicker {
+	p.calendar.max = date
+	return p
+}
+
+// 设置禁用日期的判断函数，返回true的日期不能选择
+func (p *DatePicker) DisabledDates(fn func(date time.Time) bool) *DatePicker {
+	p.calendar.disabled = fn
+	return p
+}
+
+// 日历中一周的第一天，默认根据语言决定
+func (p *DatePicker) FirstDayOfWeek(day time.Weekday) *DatePicker {
+	p.calendar.firstDay = day
+	return p
+}
+
+// 指定月份和星期名称使用的语言，例如zh、en-US，为空时使用系统语言
+func (p *DatePicker) Locale(language string) *DatePicker {
+	p.field.language = language
+	return p
+}
+
+// 输入框显示日期的格式，使用time包的格式，默认为2006-01-02
+func (p *DatePicker) Format(layout string) *DatePicker {
+	p.config.format = layout
+	return p
+}
+
+// 没有选中日期时显示的提示文字
+func (p *DatePicker) Hint(hint string) *DatePicker {
+	p.field.hint = hint
+	return p
+}
+
+// 字体大小
+func (p *DatePicker) FontSize(size float32) *DatePicker {
+	p.field.textSize = size
+	p.config.themed.overrides.set(overrideFontSize)
+	return p
+}
+
+// 获取字体大小
+func (p *DatePicker) GetFontSize() float32 {
+	return p.field.textSize
+}
+
+// 字体族
+func (p *DatePicker) FontFamily(name string) *DatePicker {
+	p.field.typeface.set(name)
+	return p
+}
+
+// 文字颜色
+func (p *DatePicker) FontColor(r, g, b, a uint8) *DatePicker {
+	p.field.fontColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
+	return p
+}
+
+// 获取文字颜色
+func (p *DatePicker) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.field.fontColor)
+}
+
+// 输入框和日历的背景颜色
+func (p *DatePicker) Background(r, g, b, a uint8) *DatePicker {
+	p.field.background = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBackground)
+	return p
+}
+
+// 获取背景颜色
+func (p *DatePicker) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.field.background)
+}
+
+// 输入框的边框颜色
+func (p *DatePicker) BorderColor(r, g, b, a uint8) *DatePicker {
+	p.field.borderColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBorderColor)
+	return p
+}
+
+// 选中日期和焦点边框的颜色
+func (p *DatePicker) Color(r, g, b, a uint8) *DatePicker {
+	p.field.markColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
+	return p
+}
+
+// 圆角
+func (p *DatePicker) CornerRadius(radius float32) *DatePicker {
+	p.field.cornerRadius = radius
+	p.config.themed.overrides.set(overrideCornerRadius)
+	return p
+}
+
+// 获取圆角
+func (p *DatePicker) GetCornerRadius() float32 {
+	return p.field.cornerRadius
+}
+
+// 打开日历
+func (p *DatePicker) Open() *DatePicker {
+	if !p.field.open {
+		p.field.open = true
+		p.calendar.reset(p.value, time.Time{})
+	}
+	return p
+}
+
+// 关闭日历
+func (p *DatePicker) Close() *DatePicker {
+	p.field.open = false
+	return p
+}
+
+// 日历是否打开
+func (p *DatePicker) IsOpen() bool {
+	return p.field.open
+}
+
+// 输入框显示的文字
+func (p *DatePicker) text() string {
+	if p.value.IsZero() {
+		return ""
+	}
+	return p.value.Format(p.config.format)
+}
+
+// 渲染组件
+func (p *DatePicker) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	if p.field.update(gtx) {
+		p.calendar.reset(p.value, gtx.Now)
+	}
+	names := p.field.names(gtx)
+	if date, ok := p.calendar.update(gtx, names); ok && p.field.open {
+		p.value = date
+		p.field.open = false
+		if p.config._changed != nil {
+			p.config._changed(p, date)
+		}
+	}
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		return p.field.layout(gtx, p.text(), func(gtx glayout.Context) glayout.Dimensions {
+			return p.calendar.layout(gtx, &p.field, names, p.value)
+		})
+	})
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *DatePicker) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	p.field.applyTheme(th, p.config.themed.overrides)
+}
+
+// 创建日期选择器，默认没有选中日期
+func NewDatePicker() *DatePicker {
+	return &DatePicker{
+		field:    newPickerField(),
+		calendar: newCalendarView(),
+		margin:   &glayout.Inset{},
+		config:   &datePickerConfig{update: true, themed: newThemed(), format: "2006-01-02"},
+	}
+}
@@ -0,0 +1,356 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &DateTimePicker{}
+
+type dateTimePickerConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 值改变事件
+	_changed func(*DateTimePicker, time.Time)
+	// 输入框显示的格式，为空时根据12/24小时制和是否显示秒决定
+	format string
+}
+
+// 日期时间选择器，弹出层左侧为日历，右侧为时、分、秒的列表
+//
+// 日历的设置与DatePicker相同，时间列表的设置与TimePicker相同
+type DateTimePicker struct {
+	// 组件标识
+	identity
+	// 配置
+	config *dateTimePickerConfig
+	// 外边距
+	margin *glayout.Inset
+	// 选中的时间，零值表示没有选中
+	value time.Time
+	// 输入框和弹出层
+	field pickerField
+	// 日历
+	calendar calendarView
+	// 时间列表
+	clock clockView
+}
+
+// 绑定函数
+func (p *DateTimePicker) Then(fn func(self *DateTimePicker)) *DateTimePicker {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *DateTimePicker) ID(id string) *DateTimePicker {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *DateTimePicker) Class(classes ...string) *DateTimePicker {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *DateTimePicker) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *DateTimePicker) Destroy() {
+	p.config.update = false
+	p.field.open = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *DateTimePicker) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 注册值改变事件，每次选择日期、时、分、秒或上下午后触发
+//
+// 选择日期时保留原来的时间，没有设置过值时先选择时间则日期部分为选择时的当天
+func (p *DateTimePicker) OnChanged(fn func(p *DateTimePicker, value time.Time)) *DateTimePicker {
+	p.config._changed = fn
+	return p
+}
+
+// 是否更新组件
+func (p *DateTimePicker) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不响应任何输入并关闭弹出层
+func (p *DateTimePicker) Disabled(disabled bool) *DateTimePicker {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *DateTimePicker) GetState() State {
+	return stateOf(p.field.click.Hovered(), p.field.focused, p.config.disabled, false)
+}
+
+// 外边距
+func (p *DateTimePicker) Margin(Top, Left, Bottom, Right float32) *DateTimePicker {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *DateTimePicker) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 设置选中的日期和时间，零值表示清除选择，不会触发事件
+func (p *DateTimePicker) Value(value time.Time) *DateTimePicker {
+	p.value = value
+	if !value.IsZero() {
+		p.calendar.show(value)
+	}
+	p.clock.scroll = true
+	return p
+}
+
+// 获取选中的日期和时间，没有选中时返回零值
+func (p *DateTimePicker) GetValue() time.Time {
+	return p.value
+}
+
+// 是否使用12小时制，默认使用24小时制
+func (p *DateTimePicker) Hour12(hour12 bool) *DateTimePicker {
+	p.clock.hour12 = hour12
+	p.clock.scroll = true
+	return p
+}
+
+// 是否可以选择秒，默认只选择时和分
+func (p *DateTimePicker) ShowSeconds(show bool) *DateTimePicker {
+	p.clock.seconds = show
+	p.clock.scroll = true
+	return p
+}
+
+// 可以选择的最早日期，零值表示不限制
+func (p *DateTimePicker) MinDate(date time.Time) *DateTimePicker {
+	p.calendar.min = date
+	return p
+}
+
+// 可以选择的最晚日期，零值表示不限制
+func (p *DateTimePicker) MaxDate(date time.Time) *DateTimePicker {
+	p.calendar.max = date
+	return p
+}
+
+// 设置禁用日期的判断函数，返回true的日期不能选择
+func (p *DateTimePicker) DisabledDates(fn func(date time.Time) bool) *DateTimePicker {
+	p.calendar.disabled = fn
+	return p
+}
+
+// 日历中一周的第一天，默认根据语言决定
+func (p *DateTimePicker) FirstDayOfWeek(day time.Weekday) *DateTimePicker {
+	p.calendar.firstDay = day
+	return p
+}
+
+// 指定月份、星期和上下午名称使用的语言，例如zh、en-US，为空时使用系统语言
+func (p *DateTimePicker) Locale(language string) *DateTimePicker {
+	p.field.language = language
+	return p
+}
+
+// 输入框显示的格式，使用time包的格式，为空时显示为2006-01-02加上时间，时间根据12/24小时制和是否显示秒决定
+func (p *DateTimePicker) Format(layout string) *DateTimePicker {
+	p.config.format = layout
+	return p
+}
+
+// 没有选中日期和时间时显示的提示文字
+func (p *DateTimePicker) Hint(hint string) *DateTimePicker {
+	p.field.hint = hint
+	return p
+}
+
+// 字体大小
+func (p *DateTimePicker) FontSize(size float32) *DateTimePicker {
+	p.field.textSize = size
+	p.config.themed.overrides.set(overrideFontSize)
+	return p
+}
+
+// 获取字体大小
+func (p *DateTimePicker) GetFontSize() float32 {
+	return p.field.textSize
+}
+
+// 字体族
+func (p *DateTimePicker) FontFamily(name string) *DateTimePicker {
+	p.field.typeface.set(name)
+	return p
+}
+
+// 文字颜色
+func (p *DateTimePicker) FontColor(r, g, b, a uint8) *DateTimePicker {
+	p.field.fontColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
+	return p
+}
+
+// 获取文字颜色
+func (p *DateTimePicker) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.field.fontColor)
+}
+
+// 输入框和弹出层的背景颜色
+func (p *DateTimePicker) Background(r, g, b, a uint8) *DateTimePicker {
+	p.field.background = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBackground)
+	return p
+}
+
+// 获取背景颜色
+func (p *DateTimePicker) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.field.background)
+}
+
+// 输入框的边框颜色
+func (p *DateTimePicker) BorderColor(r, g, b, a uint8) *DateTimePicker {
+	p.field.borderColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBorderColor)
+	return p
+}
+
+// 选中项和焦点边框的颜色
+func (p *DateTimePicker) Color(r, g, b, a uint8) *DateTimePicker {
+	p.field.markColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
+	return p
+}
+
+// 圆角
+func (p *DateTimePicker) CornerRadius(radius float32) *DateTimePicker {
+	p.field.cornerRadius = radius
+	p.config.themed.overrides.set(overrideCornerRadius)
+	return p
+}
+
+// 获取圆角
+func (p *DateTimePicker) GetCornerRadius() float32 {
+	return p.field.cornerRadius
+}
+
+// 打开弹出层
+func (p *DateTimePicker) Open() *DateTimePicker {
+	if !p.field.open {
+		p.field.open = true
+		p.calendar.reset(p.value, time.Time{})
+		p.clock.scroll = true
+	}
+	return p
+}
+
+// 关闭弹出层
+func (p *DateTimePicker) Close() *DateTimePicker {
+	p.field.open = false
+	return p
+}
+
+// 弹出层是否打开
+func (p *DateTimePicker) IsOpen() bool {
+	return p.field.open
+}
+
+// 输入框显示的文字
+func (p *DateTimePicker) text(names *dateNames) string {
+	switch {
+	case p.value.IsZero():
+		return ""
+	case p.config.format != "":
+		return p.value.Format(p.config.format)
+	}
+	return p.value.Format("2006-01-02") + " " + formatClock(p.value, p.clock.hour12, p.clock.seconds, names)
+}
+
+// 渲染组件
+func (p *DateTimePicker) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	if p.field.update(gtx) {
+		p.calendar.reset(p.value, gtx.Now)
+		p.clock.scroll = true
+	}
+	names := p.field.names(gtx)
+	if p.field.open {
+		value, changed := p.clock.update(gtx, pickerBase(p.value, gtx.Now))
+		if date, ok := p.calendar.update(gtx, names); ok {
+			// 选择日期时保留时间
+			h, m, s := value.Clock()
+			value, changed = time.Date(date.Year(), date.Month(), date.Day(), h, m, s, 0, date.Location()), true
+		}
+		if changed {
+			p.value = value
+			if p.config._changed != nil {
+				p.config._changed(p, value)
+			}
+		}
+	}
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		return p.field.layout(gtx, p.text(names), func(gtx glayout.Context) glayout.Dimensions {
+			dims := p.calendar.layout(gtx, &p.field, names, p.value)
+			stack := op.Offset(image.Pt(dims.Size.X+gtx.Dp(8), 0)).Push(gtx.Ops)
+			clock := p.clock.layout(gtx, &p.field, names, p.value)
+			stack.Pop()
+			return glayout.Dimensions{Size: image.Pt(dims.Size.X+gtx.Dp(8)+clock.Size.X, maxInt(dims.Size.Y, clock.Size.Y))}
+		})
+	})
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *DateTimePicker) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	p.field.applyTheme(th, p.config.themed.overrides)
+}
+
+// 创建日期时间选择器，默认没有选中值，使用24小时制
+func NewDateTimePicker() *DateTimePicker {
+	return &DateTimePicker{
+		field:    newPickerField(),
+		calendar: newCalendarView(),
+		clock:    newClockView(),
+		margin:   &glayout.Inset{},
+		config:   &dateTimePickerConfig{update: true, themed: newThemed()},
+	}
+}
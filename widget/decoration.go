@@ -0,0 +1,294 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/Seikaijyu/nenki.ui/widget/gradient"
+
+	"github.com/Seikaijyu/gio/f32"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 生成渐变图像时的最大边长，更大的区域会缩放图像
+const decorationImageSize = 512
+
+// 背景渐变
+type backgroundGradient struct {
+	// 是否为径向渐变
+	radial bool
+	// 线性渐变的角度，0度从下到上，90度从左到右
+	angle float32
+	// 径向渐变的圆心，相对于区域大小
+	center f32.Point
+	// 径向渐变的半径，相对于圆心到最远角的距离
+	radius float32
+	// 已经排序的颜色节点
+	stops []gradient.Stop
+}
+
+// 阴影
+type boxShadow struct {
+	// 偏移
+	offset f32.Point
+	// 模糊半径
+	blur float32
+	// 扩展距离
+	spread float32
+	// 颜色
+	color color.NRGBA
+}
+
+// 组件的背景装饰，包括渐变填充、阴影和内边框，由ContainerLayout、Border和Button共用
+//
+// 所有形状都使用组件的圆角
+type decoration struct {
+	// 背景渐变，为nil时使用纯色背景
+	gradient *backgroundGradient
+	// 阴影，为nil时不绘制
+	shadow *boxShadow
+	// 内边框宽度，单位为dp
+	innerWidth float32
+	// 内边框颜色
+	innerColor color.NRGBA
+	// 渐变图像的缓存
+	image paint.ImageOp
+	// 缓存图像对应的区域大小
+	imageSize image.Point
+}
+
+// 是否设置过任何装饰
+func (p *decoration) active() bool {
+	return p.gradient != nil || p.shadow != nil || p.innerWidth > 0
+}
+
+// 设置线性渐变，没有颜色节点时清除渐变
+func (p *decoration) linear(angle float32, stops []gradient.Stop) {
+	p.setGradient(&backgroundGradient{angle: angle, stops: gradient.Sorted(stops)})
+}
+
+// 设置径向渐变，没有颜色节点时清除渐变
+func (p *decoration) radial(centerX, centerY, radius float32, stops []gradient.Stop) {
+	p.setGradient(&backgroundGradient{
+		radial: true,
+		center: f32.Pt(centerX, centerY),
+		radius: radius,
+		stops:  gradient.Sorted(stops),
+	})
+}
+
+// 替换渐变并清除图像缓存
+func (p *decoration) setGradient(g *backgroundGradient) {
+	if len(g.stops) == 0 {
+		g = nil
+	}
+	p.gradient = g
+	p.imageSize = image.Point{}
+}
+
+// 设置阴影，颜色完全透明时清除阴影
+func (p *decoration) setShadow(offsetX, offsetY, blur, spread float32, c color.NRGBA) {
+	p.shadow = nil
+	if c.A > 0 {
+		p.shadow = &boxShadow{offset: f32.Pt(offsetX, offsetY), blur: blur, spread: spread, color: c}
+	}
+}
+
+// 设置内边框，宽度为0时清除内边框
+func (p *decoration) setInner(width float32, c color.NRGBA) {
+	p.innerWidth = width
+	p.innerColor = c
+}
+
+// 绘制阴影，阴影位于形状下方，可以超出组件的范围
+//
+// 模糊使用多层逐渐扩大的圆角矩形叠加近似
+func (p *decoration) paintShadow(gtx glayout.Context, shape clip.RRect) {
+	s := p.shadow
+	if s == nil {
+		return
+	}
+	blur := gtx.Metric.PxPerDp * s.blur
+	spread := gtx.Metric.PxPerDp * s.spread
+	defer op.Offset(image.Pt(gtx.Dp(gunit.Dp(s.offset.X)), gtx.Dp(gunit.Dp(s.offset.Y)))).Push(gtx.Ops).Pop()
+	layers := 1
+	if blur > 0 {
+		layers = minInt(maxInt(int(blur/2), 1), 16)
+	}
+	// 所有层叠加后的透明度等于阴影颜色的透明度
+	alpha := 1 - math.Pow(1-float64(s.color.A)/0xff, 1/float64(layers))
+	c := s.color
+	c.A = uint8(math.Max(alpha*0xff+0.5, 1))
+	for i := 0; i < layers; i++ {
+		grow := spread
+		if layers > 1 {
+			grow += blur * (float32(2*i+1)/float32(layers) - 1)
+		}
+		layer := expandRRect(shape, int(math.Round(float64(grow))))
+		if layer.Rect.Empty() {
+			continue
+		}
+		paint.FillShape(gtx.Ops, c, layer.Op(gtx.Ops))
+	}
+}
+
+// 绘制背景，设置了渐变时使用渐变，否则使用纯色
+func (p *decoration) paintFill(gtx glayout.Context, shape clip.RRect, background color.NRGBA) {
+	g := p.gradient
+	if g == nil {
+		if background.A > 0 {
+			paint.FillShape(gtx.Ops, background, shape.Op(gtx.Ops))
+		}
+		return
+	}
+	defer shape.Push(gtx.Ops).Pop()
+	size := shape.Rect.Size()
+	origin := glayout.FPt(shape.Rect.Min)
+	switch {
+	case len(g.stops) == 1:
+		paint.ColorOp{Color: g.stops[0].Color}.Add(gtx.Ops)
+	// 两个节点的线性渐变直接交给GPU绘制
+	case !g.radial && len(g.stops) == 2:
+		start, end := g.line(size)
+		axis := end.Sub(start)
+		paint.LinearGradientOp{
+			Stop1:  origin.Add(start.Add(axis.Mul(g.stops[0].Offset))),
+			Color1: g.stops[0].Color,
+			// 两个节点位置相同时稍微错开，得到清晰的分界
+			Stop2:  origin.Add(start.Add(axis.Mul(g.stops[1].Offset + 1e-4))),
+			Color2: g.stops[1].Color,
+		}.Add(gtx.Ops)
+	default:
+		if p.imageSize != size {
+			p.image = paint.NewImageOp(g.render(size))
+			p.imageSize = size
+		}
+		scale := f32.Pt(float32(size.X)/float32(p.image.Size().X), float32(size.Y)/float32(p.image.Size().Y))
+		defer op.Affine(f32.Affine2D{}.Scale(f32.Point{}, scale).Offset(origin)).Push(gtx.Ops).Pop()
+		p.image.Add(gtx.Ops)
+	}
+	paint.PaintOp{}.Add(gtx.Ops)
+}
+
+// 沿形状的内侧绘制内边框
+func (p *decoration) paintInner(gtx glayout.Context, shape clip.RRect) {
+	if p.innerWidth <= 0 || p.innerColor.A == 0 {
+		return
+	}
+	width := maxInt(gtx.Dp(gunit.Dp(p.innerWidth)), 1)
+	// 描边以路径为中心，向内收缩半个宽度使边框完全位于形状内
+	line := expandRRect(shape, -width/2)
+	if line.Rect.Empty() {
+		return
+	}
+	paint.FillShape(gtx.Ops, p.innerColor, clip.Stroke{Path: line.Path(gtx.Ops), Width: float32(width)}.Op())
+}
+
+// 线性渐变的起点和终点，渐变线穿过区域中心并刚好覆盖区域的四个角
+func (p *backgroundGradient) line(size image.Point) (f32.Point, f32.Point) {
+	sin, cos := math.Sincos(float64(p.angle) * math.Pi / 180)
+	w, h := float64(size.X), float64(size.Y)
+	half := (math.Abs(w*sin) + math.Abs(h*cos)) / 2
+	dir := f32.Pt(float32(sin*half), float32(-cos*half))
+	center := f32.Pt(float32(w/2), float32(h/2))
+	return center.Sub(dir), center.Add(dir)
+}
+
+// 返回计算区域中某一点在渐变线上位置的函数
+func (p *backgroundGradient) position(size image.Point) func(pt f32.Point) float32 {
+	if p.radial {
+		center := f32.Pt(p.center.X*float32(size.X), p.center.Y*float32(size.Y))
+		// 半径相对于圆心到最远角的距离
+		far := float32(0)
+		for _, corner := range []f32.Point{{}, {X: float32(size.X)}, {Y: float32(size.Y)}, glayout.FPt(size)} {
+			far = float32(math.Max(float64(far), float64(distance(center, corner))))
+		}
+		radius := p.radius * far
+		return func(pt f32.Point) float32 {
+			if radius <= 0 {
+				return 1
+			}
+			return distance(center, pt) / radius
+		}
+	}
+	start, end := p.line(size)
+	axis := end.Sub(start)
+	length := axis.X*axis.X + axis.Y*axis.Y
+	return func(pt f32.Point) float32 {
+		if length == 0 {
+			return 0
+		}
+		d := pt.Sub(start)
+		return (d.X*axis.X + d.Y*axis.Y) / length
+	}
+}
+
+// 生成渐变图像，区域过大时生成缩小的图像，绘制时再放大
+func (p *backgroundGradient) render(size image.Point) *image.NRGBA {
+	scale := float32(1)
+	if longest := maxInt(size.X, size.Y); longest > decorationImageSize {
+		scale = float32(decorationImageSize) / float32(longest)
+	}
+	w := maxInt(int(math.Ceil(float64(float32(size.X)*scale))), 1)
+	h := maxInt(int(math.Ceil(float64(float32(size.Y)*scale))), 1)
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	position := p.position(size)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pt := f32.Pt((float32(x)+0.5)/scale, (float32(y)+0.5)/scale)
+			img.SetNRGBA(x, y, gradient.ColorAt(p.stops, position(pt)))
+		}
+	}
+	return img
+}
+
+// 两点之间的距离
+func distance(a, b f32.Point) float32 {
+	d := a.Sub(b)
+	return float32(math.Hypot(float64(d.X), float64(d.Y)))
+}
+
+// 向外扩展圆角矩形，距离为负数时向内收缩，圆角随之增减
+func expandRRect(rr clip.RRect, d int) clip.RRect {
+	rr.Rect = rr.Rect.Inset(-d)
+	corner := func(r int) int {
+		if r == 0 {
+			return 0
+		}
+		return maxInt(r+d, 0)
+	}
+	rr.NW, rr.NE, rr.SE, rr.SW = corner(rr.NW), corner(rr.NE), corner(rr.SE), corner(rr.SW)
+	return clampRRect(rr)
+}
+
+// 限制圆角不超过矩形较短边的一半
+func clampRRect(rr clip.RRect) clip.RRect {
+	limit := maxInt(minInt(rr.Rect.Dx(), rr.Rect.Dy())/2, 0)
+	rr.NW, rr.NE, rr.SE, rr.SW = minInt(rr.NW, limit), minInt(rr.NE, limit), minInt(rr.SE, limit), minInt(rr.SW, limit)
+	return rr
+}
+
+// 每条边分别向内收缩圆角矩形，圆角减去相邻两边中较宽的一条
+func insetRRect(rr clip.RRect, top, left, bottom, right int) clip.RRect {
+	rr.Rect.Min = rr.Rect.Min.Add(image.Pt(left, top))
+	rr.Rect.Max = rr.Rect.Max.Sub(image.Pt(right, bottom))
+	if rr.Rect.Empty() {
+		return clip.RRect{}
+	}
+	corner := func(r, a, b int) int {
+		return maxInt(r-maxInt(a, b), 0)
+	}
+	rr.NW, rr.NE = corner(rr.NW, top, left), corner(rr.NE, top, right)
+	rr.SE, rr.SW = corner(rr.SE, bottom, right), corner(rr.SW, bottom, left)
+	return clampRRect(rr)
+}
+
+// 所有角使用相同圆角的矩形，圆角不超过较短边的一半
+func roundedRect(rect image.Rectangle, radius int) clip.RRect {
+	return clampRRect(clip.RRect{Rect: rect, NW: radius, NE: radius, SE: radius, SW: radius})
+}
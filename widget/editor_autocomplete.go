@@ -0,0 +1,430 @@
+package widget
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/key"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+)
+
+// 默认的查询延迟
+const defaultSuggestionDelay = 150 * time.Millisecond
+
+// 自动补全的建议
+type Suggestion struct {
+	// 接受建议时替换前缀的文字
+	Text string
+	// 列表中显示的文字，为空时显示Text
+	Label string
+	// 显示在列表右侧的说明文字
+	Detail string
+}
+
+// 编辑框的自动补全
+type editorAutocomplete struct {
+	// 同步的建议提供者
+	provider func(prefix string) []Suggestion
+	// 异步的建议提供者，在新的协程中调用
+	asyncProvider func(ctx context.Context, prefix string) []Suggestion
+	// 文本改变后等待多久再查询
+	delay time.Duration
+	// 前缀至少包含多少个字符时才查询
+	minLength int
+	// 是否只使用光标前的单词作为前缀，否则使用光标前的整行
+	word bool
+	// 列表最多显示的行数
+	maxItems int
+	// 下一次查询的时间，为零时没有等待中的查询
+	deadline time.Time
+	// 取消正在进行的异步查询，为nil时没有进行中的查询
+	cancel context.CancelFunc
+	// 查询序号，用于丢弃过期的异步结果
+	seq int
+	// 保护异步查询的结果
+	mutex sync.Mutex
+	// 异步查询的结果和对应的序号
+	result    []Suggestion
+	resultSeq int
+	// 接受建议后的文本，这次文本改变不会触发查询
+	skip string
+	// 是否显示列表
+	open bool
+	// 当前显示的建议
+	items []Suggestion
+	// 当前选中的建议
+	selected int
+	// 列表滚动位置
+	list glayout.List
+	// 每一行的点击
+	clicks []gesture.Click
+	// 被拦截的导航按键
+	keys []key.Event
+	// 接受建议事件
+	_accepted func(*Editor, Suggestion)
+}
+
+// 创建自动补全
+func newEditorAutocomplete() *editorAutocomplete {
+	return &editorAutocomplete{
+		delay:     defaultSuggestionDelay,
+		minLength: 1,
+		maxItems:  8,
+		list:      glayout.List{Axis: glayout.Vertical},
+	}
+}
+
+// 取消等待中和进行中的查询
+func (p *editorAutocomplete) cancelPending() {
+	p.deadline = time.Time{}
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.seq++
+}
+
+// 显示查询结果，没有结果时关闭列表
+func (p *editorAutocomplete) show(items []Suggestion) {
+	p.items = items
+	p.selected = 0
+	p.open = len(items) > 0
+	p.list.Position = glayout.Position{}
+}
+
+// 关闭列表并取消查询
+func (p *editorAutocomplete) close() {
+	p.cancelPending()
+	p.open = false
+	p.items = nil
+}
+
+// 移动选中的建议，到达两端时循环
+func (p *editorAutocomplete) move(delta int) {
+	n := len(p.items)
+	p.selected = ((p.selected+delta)%n + n) % n
+	// 保持选中的建议可见
+	if p.selected < p.list.Position.First {
+		p.list.Position = glayout.Position{First: p.selected}
+	} else if rows := minInt(n, p.maxItems); p.selected >= p.list.Position.First+rows {
+		p.list.Position = glayout.Position{First: p.selected - rows + 1}
+	}
+}
+
+// 列表打开时拦截的导航按键
+func (p *editorAutocomplete) navigation(e key.Event) bool {
+	if !p.open || e.Modifiers != 0 {
+		return false
+	}
+	switch e.Name {
+	case key.NameUpArrow, key.NameDownArrow, key.NameReturn, key.NameEnter, key.NameTab, key.NameEscape:
+		return true
+	}
+	return false
+}
+
+// 设置自动补全的建议提供者，prefix为光标前的文字，为nil时关闭自动补全
+//
+// 文本改变后经过一段延迟才会查询，查询结果显示在编辑框下方的列表中，
+// 使用上下方向键选择，按下Tab或回车接受，按下Esc关闭
+func (p *Editor) Autocomplete(fn func(prefix string) []Suggestion) *Editor {
+	p.autocomplete().provider = fn
+	p.config.autocomplete.asyncProvider = nil
+	if fn == nil {
+		p.config.autocomplete.close()
+	}
+	return p
+}
+
+// 设置异步的建议提供者，在新的协程中调用，为nil时关闭自动补全
+//
+// 文本再次改变或列表关闭时ctx会被取消，过期的结果会被丢弃
+func (p *Editor) AutocompleteAsync(fn func(ctx context.Context, prefix string) []Suggestion) *Editor {
+	p.autocomplete().asyncProvider = fn
+	p.config.autocomplete.provider = nil
+	if fn == nil {
+		p.config.autocomplete.close()
+	}
+	return p
+}
+
+// 设置文本改变后等待多少毫秒再查询，默认为150毫秒
+func (p *Editor) AutocompleteDelay(ms int) *Editor {
+	p.autocomplete().delay = time.Duration(ms) * time.Millisecond
+	return p
+}
+
+// 设置前缀至少包含多少个字符时才查询，默认为1
+func (p *Editor) AutocompleteMinLength(length int) *Editor {
+	p.autocomplete().minLength = length
+	return p
+}
+
+// 是否只使用光标前的单词作为前缀，默认使用光标前的整行
+func (p *Editor) AutocompleteWord(word bool) *Editor {
+	p.autocomplete().word = word
+	return p
+}
+
+// 设置建议列表最多显示的行数，默认为8，超出时可以滚动
+func (p *Editor) AutocompleteMaxItems(items int) *Editor {
+	p.autocomplete().maxItems = maxInt(items, 1)
+	return p
+}
+
+// 接受建议事件
+func (p *Editor) OnSuggestionAccepted(fn func(p *Editor, suggestion Suggestion)) *Editor {
+	p.autocomplete()._accepted = fn
+	return p
+}
+
+// 立即使用当前的前缀查询并显示建议，忽略最少字符数
+func (p *Editor) ShowSuggestions() *Editor {
+	if p.config.autocomplete != nil {
+		p.lookupSuggestions(true)
+	}
+	return p
+}
+
+// 关闭建议列表
+func (p *Editor) CloseSuggestions() *Editor {
+	if p.config.autocomplete != nil {
+		p.config.autocomplete.close()
+	}
+	return p
+}
+
+// 获取当前显示的建议，列表关闭时返回nil
+func (p *Editor) GetSuggestions() []Suggestion {
+	if p.config.autocomplete == nil || !p.config.autocomplete.open {
+		return nil
+	}
+	return p.config.autocomplete.items
+}
+
+// 获取自动补全，没有时创建
+func (p *Editor) autocomplete() *editorAutocomplete {
+	if p.config.autocomplete == nil {
+		p.config.autocomplete = newEditorAutocomplete()
+	}
+	return p.config.autocomplete
+}
+
+// 文本改变后等待一段时间再查询
+func (p *Editor) suggestionsChanged(gtx glayout.Context) {
+	ac := p.config.autocomplete
+	if ac == nil || ac.provider == nil && ac.asyncProvider == nil {
+		return
+	}
+	if text := p.GetText(); ac.skip != "" && text == ac.skip {
+		ac.skip = ""
+		return
+	}
+	ac.skip = ""
+	if !p.editorMaterial.Editor.Focused() {
+		return
+	}
+	ac.cancelPending()
+	ac.deadline = gtx.Now.Add(ac.delay)
+	op.InvalidateOp{At: ac.deadline}.Add(gtx.Ops)
+}
+
+// 获取光标前用于查询的前缀和它的开始位置，有选中的文字时返回false
+func (p *Editor) suggestionPrefix() (string, int, bool) {
+	start, end := p.GetSelection()
+	if start != end {
+		return "", 0, false
+	}
+	runes := []rune(p.GetText())
+	end = minInt(end, len(runes))
+	start = end
+	for start > 0 && runes[start-1] != '\n' && (!p.config.autocomplete.word || isWordRune(runes[start-1])) {
+		start--
+	}
+	return string(runes[start:end]), start, true
+}
+
+// 使用当前的前缀查询建议，force为true时忽略最少字符数
+func (p *Editor) lookupSuggestions(force bool) {
+	ac := p.config.autocomplete
+	ac.cancelPending()
+	prefix, _, ok := p.suggestionPrefix()
+	if !ok || !force && utf8.RuneCountInString(prefix) < ac.minLength {
+		ac.open = false
+		return
+	}
+	switch {
+	case ac.asyncProvider != nil:
+		ctx, cancel := context.WithCancel(context.Background())
+		ac.cancel = cancel
+		seq, provider := ac.seq, ac.asyncProvider
+		go func() {
+			items := provider(ctx, prefix)
+			if ctx.Err() != nil {
+				return
+			}
+			ac.mutex.Lock()
+			ac.result, ac.resultSeq = items, seq
+			ac.mutex.Unlock()
+			// 唤醒窗口显示查询结果
+			RequestRedraw()
+		}()
+	case ac.provider != nil:
+		ac.show(ac.provider(prefix))
+	}
+}
+
+// 接受第i个建议，替换光标前的前缀
+func (p *Editor) acceptSuggestion(i int) {
+	ac := p.config.autocomplete
+	suggestion := ac.items[i]
+	ac.close()
+	_, start, ok := p.suggestionPrefix()
+	if !ok || p.editorMaterial.Editor.ReadOnly {
+		return
+	}
+	caret, _ := p.GetSelection()
+	p.syncHistory()
+	p.SetCaret(start, caret).Insert(suggestion.Text)
+	p.syncHistory()
+	p.validate()
+	ac.skip = p.GetText()
+	if ac._accepted != nil {
+		ac._accepted(p, suggestion)
+	}
+}
+
+// 处理被拦截的导航按键
+func (p *Editor) suggestionKey(e key.Event) {
+	ac := p.config.autocomplete
+	if !ac.open || e.State != key.Press {
+		return
+	}
+	switch e.Name {
+	case key.NameUpArrow:
+		ac.move(-1)
+	case key.NameDownArrow:
+		ac.move(1)
+	case key.NameReturn, key.NameEnter, key.NameTab:
+		p.acceptSuggestion(ac.selected)
+	case key.NameEscape:
+		ac.close()
+	}
+}
+
+// 处理到期的查询、异步查询的结果和列表的点击，在编辑框渲染前调用
+func (p *Editor) updateSuggestions(gtx glayout.Context) {
+	ac := p.config.autocomplete
+	if !p.editorMaterial.Editor.Focused() {
+		ac.close()
+		return
+	}
+	if !ac.deadline.IsZero() {
+		if gtx.Now.Before(ac.deadline) {
+			op.InvalidateOp{At: ac.deadline}.Add(gtx.Ops)
+		} else {
+			p.lookupSuggestions(false)
+		}
+	}
+	if ac.cancel != nil {
+		ac.mutex.Lock()
+		items, seq := ac.result, ac.resultSeq
+		ac.mutex.Unlock()
+		// 异步查询完成时会唤醒窗口，完成前不需要重新渲染
+		if seq == ac.seq {
+			ac.cancel()
+			ac.cancel = nil
+			ac.show(items)
+		}
+	}
+	for i := range ac.clicks {
+		for _, e := range ac.clicks[i].Update(gtx) {
+			if e.Kind == gesture.KindClick && ac.open && i < len(ac.items) {
+				p.acceptSuggestion(i)
+			}
+		}
+	}
+}
+
+// 在编辑框下方绘制建议列表，size为编辑框的大小
+func (p *Editor) layoutSuggestions(gtx glayout.Context, size image.Point) {
+	if !p.config.autocomplete.open {
+		return
+	}
+	// 列表绘制在其他组件之上
+	macro := op.Record(gtx.Ops)
+	op.Offset(image.Pt(0, size.Y)).Add(gtx.Ops)
+	p.paintSuggestions(gtx, size.X)
+	op.Defer(gtx.Ops, macro.Stop())
+}
+
+// 绘制建议列表
+func (p *Editor) paintSuggestions(gtx glayout.Context, width int) {
+	ac := p.config.autocomplete
+	th := theme.Current()
+	textSize := p.editorMaterial.TextSize
+	padding, border := gtx.Dp(8), gtx.Dp(1)
+	rowHeight := int(float32(gtx.Sp(textSize))*1.4) + gtx.Dp(8)
+	rows := minInt(len(ac.items), ac.maxItems)
+	rect := image.Rect(0, 0, width, rows*rowHeight+border*2)
+	paint.FillShape(gtx.Ops, withAlpha(th.Text, 0x30), clip.Rect(rect).Op())
+	paint.FillShape(gtx.Ops, th.Background, clip.Rect(rect.Inset(border)).Op())
+	for len(ac.clicks) < len(ac.items) {
+		ac.clicks = append(ac.clicks, gesture.Click{})
+	}
+	defer op.Offset(image.Pt(border, border)).Push(gtx.Ops).Pop()
+	gtx.Constraints = glayout.Exact(image.Pt(width-border*2, rows*rowHeight))
+	ac.list.Layout(gtx, len(ac.items), func(gtx glayout.Context, i int) glayout.Dimensions {
+		item := ac.items[i]
+		size := image.Pt(gtx.Constraints.Max.X, rowHeight)
+		switch {
+		case i == ac.selected:
+			paint.FillShape(gtx.Ops, withAlpha(th.Primary, 0x40), clip.Rect{Max: size}.Op())
+		case ac.clicks[i].Hovered():
+			paint.FillShape(gtx.Ops, withAlpha(th.Text, 0x14), clip.Rect{Max: size}.Op())
+		}
+		label := item.Label
+		if label == "" {
+			label = item.Text
+		}
+		tgtx := gtx
+		tgtx.Constraints = glayout.Constraints{Max: image.Pt(maxInt(size.X-padding*2, 0), rowHeight)}
+		// 说明文字靠右，标签使用剩下的宽度
+		detailWidth := 0
+		if item.Detail != "" {
+			macro := op.Record(gtx.Ops)
+			dims := textpaint.Label{Alignment: text.End, MaxLines: 1, Truncator: "…"}.
+				Layout(tgtx, theme.Shaper, p.editorMaterial.Font, textSize*0.85, item.Detail, th.TextSecondary)
+			call := macro.Stop()
+			detailWidth = dims.Size.X + padding
+			stack := op.Offset(image.Pt(size.X-padding-dims.Size.X, (rowHeight-dims.Size.Y)/2)).Push(gtx.Ops)
+			call.Add(gtx.Ops)
+			stack.Pop()
+		}
+		tgtx.Constraints.Max.X = maxInt(tgtx.Constraints.Max.X-detailWidth, 0)
+		macro := op.Record(gtx.Ops)
+		dims := textpaint.Label{MaxLines: 1, Truncator: "…"}.
+			Layout(tgtx, theme.Shaper, p.editorMaterial.Font, textSize, label, th.Text)
+		call := macro.Stop()
+		stack := op.Offset(image.Pt(padding, (rowHeight-dims.Size.Y)/2)).Push(gtx.Ops)
+		call.Add(gtx.Ops)
+		stack.Pop()
+		area := clip.Rect{Max: size}.Push(gtx.Ops)
+		pointer.CursorPointer.Add(gtx.Ops)
+		ac.clicks[i].Add(gtx.Ops)
+		area.Pop()
+		return glayout.Dimensions{Size: size}
+	})
+}
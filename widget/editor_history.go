@@ -0,0 +1,162 @@
+package widget
+
+import (
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Seikaijyu/gio/io/key"
+)
+
+// 连续输入合并为一步撤销的最大间隔
+const editGroupInterval = time.Second
+
+// 编辑框默认的历史记录深度
+const defaultHistoryDepth = 100
+
+// 编辑历史中的一步修改
+type editRecord struct {
+	// 修改开始的位置，以字符计数
+	start int
+	// 被替换掉的文字
+	removed string
+	// 替换后的文字
+	inserted string
+	// 是否为连续输入
+	typing bool
+	// 最后一次修改的时间
+	time time.Time
+}
+
+// 编辑框的撤销和重做历史
+type editHistory struct {
+	// 修改记录
+	records []editRecord
+	// 下一步修改在records中的位置，撤销后小于len(records)
+	next int
+	// 最多保存的修改步数，小于等于0时不记录历史
+	depth int
+	// 最后一次记录时的文本
+	text string
+	// 被拦截的撤销和重做按键
+	keys []key.Event
+}
+
+// 创建编辑历史
+func newEditHistory() *editHistory {
+	return &editHistory{depth: defaultHistoryDepth}
+}
+
+// 比较当前文本和上次记录的文本，将差异记录为一步修改，返回历史是否改变
+func (p *editHistory) record(text string) bool {
+	if text == p.text {
+		return false
+	}
+	old, cur := p.text, text
+	p.text = text
+	if p.depth <= 0 {
+		return false
+	}
+	// 去掉相同的前缀和后缀，剩下的部分就是修改的内容
+	prefix := 0
+	for prefix < len(old) && prefix < len(cur) && old[prefix] == cur[prefix] {
+		prefix++
+	}
+	// 相同的部分不能截断字符
+	for prefix > 0 && prefix < len(old) && !utf8.RuneStart(old[prefix]) {
+		prefix--
+	}
+	for prefix > 0 && prefix < len(cur) && !utf8.RuneStart(cur[prefix]) {
+		prefix--
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(cur)-prefix && old[len(old)-1-suffix] == cur[len(cur)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(old[len(old)-suffix]) {
+		suffix--
+	}
+	rec := editRecord{
+		start:    utf8.RuneCountInString(old[:prefix]),
+		removed:  old[prefix : len(old)-suffix],
+		inserted: cur[prefix : len(cur)-suffix],
+		time:     time.Now(),
+	}
+	r, size := utf8.DecodeRuneInString(rec.inserted)
+	rec.typing = rec.removed == "" && size == len(rec.inserted) && r != '\n'
+	// 丢弃已经撤销的修改
+	p.records = p.records[:p.next]
+	if p.merge(rec, r) {
+		return true
+	}
+	p.records = append(p.records, rec)
+	if len(p.records) > p.depth {
+		p.records = append(p.records[:0], p.records[len(p.records)-p.depth:]...)
+	}
+	p.next = len(p.records)
+	return true
+}
+
+// 将连续输入的字符合并到上一步修改中，空白之后输入的字符开始新的一步
+func (p *editHistory) merge(rec editRecord, r rune) bool {
+	if !rec.typing || len(p.records) == 0 {
+		return false
+	}
+	last := &p.records[len(p.records)-1]
+	if !last.typing || rec.time.Sub(last.time) > editGroupInterval {
+		return false
+	}
+	if last.start+utf8.RuneCountInString(last.inserted) != rec.start {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(last.inserted)
+	if unicode.IsSpace(prev) && !unicode.IsSpace(r) {
+		return false
+	}
+	last.inserted += rec.inserted
+	last.time = rec.time
+	return true
+}
+
+// 取出需要撤销的修改
+func (p *editHistory) undo() (editRecord, bool) {
+	if p.next == 0 {
+		return editRecord{}, false
+	}
+	p.next--
+	// 撤销后重新输入不再合并到之前的修改中
+	p.records[p.next].typing = false
+	return p.records[p.next], true
+}
+
+// 取出需要重做的修改
+func (p *editHistory) redo() (editRecord, bool) {
+	if p.next == len(p.records) {
+		return editRecord{}, false
+	}
+	p.next++
+	return p.records[p.next-1], true
+}
+
+// 清空历史，text为当前的文本
+func (p *editHistory) clear(text string) {
+	p.records = nil
+	p.next = 0
+	p.text = text
+}
+
+// 设置最多保存的修改步数
+func (p *editHistory) setDepth(depth int) {
+	p.depth = depth
+	if depth <= 0 {
+		p.clear(p.text)
+		return
+	}
+	if over := len(p.records) - depth; over > 0 {
+		p.records = append(p.records[:0], p.records[over:]...)
+		p.next -= over
+		if p.next < 0 {
+			p.next = 0
+		}
+	}
+}
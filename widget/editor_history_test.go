@@ -0,0 +1,107 @@
+package widget
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 修改记录中用于比较的部分
+type editStep struct {
+	start             int
+	removed, inserted string
+}
+
+func historySteps(h *editHistory) []editStep {
+	var steps []editStep
+	for _, rec := range h.records[:h.next] {
+		steps = append(steps, editStep{rec.start, rec.removed, rec.inserted})
+	}
+	return steps
+}
+
+func TestEditHistoryGrouping(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  []editStep
+	}{
+		{"连续输入合并", []string{"h", "he", "hel", "hell", "hello"}, []editStep{{0, "", "hello"}}},
+		{"空白之后开始新的一步", []string{"a", "ab", "ab ", "ab c", "ab cd"}, []editStep{{0, "", "ab "}, {3, "", "cd"}}},
+		{"连续的空白合并", []string{"a", "a ", "a  "}, []editStep{{0, "", "a  "}}},
+		{"换行单独作为一步", []string{"a", "a\n", "a\nb"}, []editStep{{0, "", "a"}, {1, "", "\n"}, {2, "", "b"}}},
+		{"粘贴多个字符单独作为一步", []string{"a", "abcd", "abcde"}, []editStep{{0, "", "a"}, {1, "", "bcd"}, {4, "", "e"}}},
+		{"删除单独作为一步", []string{"ab", "a", ""}, []editStep{{0, "", "ab"}, {1, "b", ""}, {0, "a", ""}}},
+		{"在其他位置输入开始新的一步", []string{"a", "ab", "xab", "xyab"}, []editStep{{0, "", "ab"}, {0, "", "xy"}}},
+		{"粘贴的文字之后输入不合并", []string{"ab", "abc"}, []editStep{{0, "", "ab"}, {2, "", "c"}}},
+		{"替换", []string{"hello world", "hello there"}, []editStep{{0, "", "hello world"}, {6, "world", "there"}}},
+		{"多字节字符按字符计算位置", []string{"你好", "你们好"}, []editStep{{0, "", "你好"}, {1, "", "们"}}},
+		{"相同的文本不记录", []string{"a", "a", "a"}, []editStep{{0, "", "a"}}},
+	}
+	for _, tt := range tests {
+		h := newEditHistory()
+		for _, text := range tt.texts {
+			h.record(text)
+		}
+		if got := historySteps(h); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// 间隔超过editGroupInterval的输入不合并
+func TestEditHistoryGroupInterval(t *testing.T) {
+	h := newEditHistory()
+	h.record("a")
+	h.records[0].time = time.Now().Add(-2 * editGroupInterval)
+	h.record("ab")
+	want := []editStep{{0, "", "a"}, {1, "", "b"}}
+	if got := historySteps(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("%v, want %v", got, want)
+	}
+}
+
+func TestEditHistoryUndoRedo(t *testing.T) {
+	h := newEditHistory()
+	h.record("ab ")
+	h.record("ab cd")
+	rec, ok := h.undo()
+	if !ok || rec.inserted != "cd" {
+		t.Fatalf("undo() = %+v, %v", rec, ok)
+	}
+	h.text = "ab "
+	// 撤销后重新输入不合并到之前的修改，并丢弃可以重做的修改
+	h.record("ab x")
+	want := []editStep{{0, "", "ab "}, {3, "", "x"}}
+	if got := historySteps(h); !reflect.DeepEqual(got, want) || len(h.records) != 2 {
+		t.Errorf("%v, want %v", got, want)
+	}
+	if _, ok := h.redo(); ok {
+		t.Error("redo() 应该没有可以重做的修改")
+	}
+	h.undo()
+	h.undo()
+	if _, ok := h.undo(); ok {
+		t.Error("undo() 应该没有可以撤销的修改")
+	}
+	if rec, ok := h.redo(); !ok || rec.inserted != "ab " {
+		t.Errorf("redo() = %+v, %v", rec, ok)
+	}
+}
+
+func TestEditHistoryDepth(t *testing.T) {
+	h := newEditHistory()
+	h.setDepth(2)
+	for _, text := range []string{"a\n", "a\nb\n", "a\nb\nc\n"} {
+		h.record(text)
+	}
+	want := []editStep{{2, "", "b\n"}, {4, "", "c\n"}}
+	if got := historySteps(h); len(h.records) != 2 || !reflect.DeepEqual(got, want) {
+		t.Errorf("%v, want %v", got, want)
+	}
+	h.setDepth(0)
+	h.record("x")
+	if len(h.records) != 0 || h.text != "x" {
+		t.Errorf("深度为0时不应该记录历史: %v", historySteps(h))
+	}
+}
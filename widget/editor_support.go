@@ -0,0 +1,77 @@
+package widget
+
+import (
+	"image"
+	"reflect"
+	"sync"
+
+	"github.com/Seikaijyu/gio/io/event"
+	"github.com/Seikaijyu/gio/io/key"
+	glayout "github.com/Seikaijyu/gio/layout"
+	gwidget "github.com/Seikaijyu/gio/widget"
+)
+
+// 在组件读取事件前拦截部分按键，用于替换gio编辑框自带的快捷键
+type keyInterceptor struct {
+	event.Queue
+	// 返回true时拦截按键，组件不会收到该按键
+	intercept func(e key.Event) bool
+}
+
+func (q keyInterceptor) Events(t event.Tag) []event.Event {
+	events := q.Queue.Events(t)
+	var filtered []event.Event
+	for i, e := range events {
+		if e, ok := e.(key.Event); ok && q.intercept(e) {
+			if filtered == nil {
+				filtered = append(make([]event.Event, 0, len(events)), events[:i]...)
+			}
+			continue
+		}
+		if filtered != nil {
+			filtered = append(filtered, e)
+		}
+	}
+	if filtered == nil {
+		return events
+	}
+	return filtered
+}
+
+// 返回拦截按键后的上下文，禁用时上下文没有事件队列，直接返回
+func interceptKeys(gtx glayout.Context, fn func(e key.Event) bool) glayout.Context {
+	if gtx.Queue != nil {
+		gtx.Queue = keyInterceptor{Queue: gtx.Queue, intercept: fn}
+	}
+	return gtx
+}
+
+// gio编辑框中滚动位置字段的路径
+//
+// gio没有公开滚动位置，只能通过反射读取未导出的text.scrollOff，第一次使用时才查找，
+// 当前版本的gio没有该字段时index为nil
+var editorScrollField struct {
+	once  sync.Once
+	index []int
+}
+
+// 获取gio编辑框的滚动位置，无法获取时返回false
+//
+// Regions返回的是文档坐标，需要减去滚动位置才是编辑框中的坐标，无法获取时调用者不应该绘制依赖坐标的内容
+func editorScroll(e *gwidget.Editor) (image.Point, bool) {
+	field := &editorScrollField
+	field.once.Do(func() {
+		text, ok := reflect.TypeOf(gwidget.Editor{}).FieldByName("text")
+		if !ok || text.Type.Kind() != reflect.Struct {
+			return
+		}
+		if off, ok := text.Type.FieldByName("scrollOff"); ok && off.Type == reflect.TypeOf(image.Point{}) {
+			field.index = append(append([]int(nil), text.Index...), off.Index...)
+		}
+	})
+	if field.index == nil {
+		return image.Point{}, false
+	}
+	off := reflect.ValueOf(e).Elem().FieldByIndex(field.index)
+	return image.Pt(int(off.Field(0).Int()), int(off.Field(1).Int())), true
+}
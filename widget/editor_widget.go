@@ -2,11 +2,18 @@ package widget
 
 import (
 	"image/color"
+	"unicode/utf8"
 
 	"github.com/Seikaijyu/nenki.ui/widget/text"
 	"github.com/Seikaijyu/nenki.ui/widget/theme"
+	"github.com/Seikaijyu/nenki.ui/widget/validator"
 
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/io/key"
 	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
 	gunit "github.com/Seikaijyu/gio/unit"
 	gwidget "github.com/Seikaijyu/gio/widget"
 	gmaterial "github.com/Seikaijyu/gio/widget/material"
@@ -17,6 +24,12 @@ var _ WidgetInterface = &Editor{}
 
 // 编辑框配置
 type editorConfig struct {
+	// 主题状态
+	themed themed
+	// 字体族
+	typeface typeface
+	// 是否禁用
+	disabled bool
 	// 是否更新组件
 	update bool
 	// 删除事件
@@ -29,18 +42,50 @@ type editorConfig struct {
 	_select func(*Editor, string)
 	// 文本改变事件
 	_change func(*Editor, string)
+	// 验证状态改变事件
+	_validChanged func(*Editor, bool, error)
+	// 编辑历史改变事件
+	_historyChanged func(*Editor, bool, bool)
 	// 焦点值
 	_focusValue bool
+	// 验证器
+	validators []validator.Validator
+	// 当前验证错误，为nil时表示验证通过
+	validateErr error
+	// 是否显示验证错误信息
+	showError bool
+	// 用户是否修改过内容、离开过编辑框或者主动要求验证，之后才显示验证错误信息
+	touched bool
+	// 上一帧是否获得焦点，用于判断是否离开了编辑框
+	hadFocus bool
+	// 撤销和重做历史
+	history *editHistory
+	// 查找
+	finder textFinder
+	// 查找结果的高亮颜色
+	matchColor color.NRGBA
+	// 当前查找结果的高亮颜色
+	currentMatchColor color.NRGBA
+	// 附加的查找栏，设置后可以通过Ctrl+F打开
+	findBar *FindBar
+	// 复用的区域
+	regions []gwidget.Region
+	// 自动补全，没有设置建议提供者时为nil
+	autocomplete *editorAutocomplete
 }
 
 // 编辑框
 type Editor struct {
+	// 组件标识
+	identity
 	// 配置
 	config *editorConfig
 	// 外边距
 	margin *glayout.Inset
 	// editor组件
 	editorMaterial *gmaterial.EditorStyle
+	// 验证错误信息组件
+	errorLabel *gmaterial.LabelStyle
 }
 
 // 绑定函数
@@ -49,6 +94,18 @@ func (p *Editor) Then(fn func(self *Editor)) *Editor {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *Editor) ID(id string) *Editor {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Editor) Class(classes ...string) *Editor {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *Editor) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -59,9 +116,26 @@ func (p *Editor) Update(update bool) {
 	p.config.update = update
 }
 
+// 是否禁用组件，禁用时不响应任何输入
+func (p *Editor) Disabled(disabled bool) *Editor {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *Editor) GetState() State {
+	return stateOf(false, p.editorMaterial.Editor.Focused(), p.config.disabled, false)
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Editor) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *Editor) Destroy() {
 	p.config.update = false
+	p.CloseSuggestions()
 	if p.config._destroy != nil {
 		p.config._destroy()
 	}
@@ -77,19 +151,35 @@ func (p *Editor) Margin(Top, Left, Bottom, Right float32) *Editor {
 	return p
 }
 
+// 获取外边距
+func (p *Editor) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 渲染
 func (p *Editor) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		return glayout.Dimensions{}
 	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
 	for _, item := range p.editorMaterial.Editor.Events() {
 		switch item.(type) {
 		case gwidget.ChangeEvent:
+			p.config.touched = true
+			p.config.typeface.invalidate()
+			p.syncHistory()
+			p.validate()
+			p.suggestionsChanged(gtx)
 			if p.config._change != nil {
 				p.config._change(p, p.GetText())
 			}
 		case gwidget.SubmitEvent:
-			if p.config._submit != nil {
+			// 验证不通过时阻止提交
+			if p.config._submit != nil && p.config.validateErr == nil {
 				p.config._submit(p, p.GetText())
 			}
 		case gwidget.SelectEvent:
@@ -98,25 +188,455 @@ func (p *Editor) Layout(gtx glayout.Context) glayout.Dimensions {
 			}
 		}
 	}
+	// 离开编辑框后开始显示验证错误信息
+	focused := p.editorMaterial.Editor.Focused()
+	if p.config.hadFocus && !focused {
+		p.config.touched = true
+	}
+	p.config.hadFocus = focused
 	if p.config._focused != nil && p.config._focusValue != p.editorMaterial.Editor.Focused() {
 		p.config._focused(p, p.editorMaterial.Editor.Focused(), p.GetText())
 		p.config._focusValue = p.editorMaterial.Editor.Focused()
 	}
+	// 获取编辑框文本的代价较高，只在文本或字体注册表改变时重新解析字体
+	if p.config.typeface.stale() {
+		p.editorMaterial.Font.Typeface = p.config.typeface.resolve(p.editorMaterial.Hint + p.editorMaterial.Editor.Text())
+	}
 
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-		return p.editorMaterial.Layout(gtx)
+		// 没有验证错误、不显示错误信息或者用户还没有修改过内容时直接渲染编辑框
+		if p.config.validateErr == nil || !p.config.showError || !p.config.touched {
+			return p.layoutEditor(gtx)
+		}
+		p.errorLabel.Text = p.config.validateErr.Error()
+		return glayout.Flex{Axis: glayout.Vertical}.Layout(gtx,
+			glayout.Rigid(p.layoutEditor),
+			glayout.Rigid(p.errorLabel.Layout),
+		)
 	})
 }
 
+// 渲染编辑框，处理撤销、重做、查找和自动补全的快捷键
+func (p *Editor) layoutEditor(gtx glayout.Context) glayout.Dimensions {
+	history := p.config.history
+	ac := p.config.autocomplete
+	if ac != nil {
+		p.updateSuggestions(gtx)
+	}
+	// Ctrl+Z和建议列表的导航按键由编辑框自身处理，需要在编辑框读取事件前拦截
+	gtx = interceptKeys(gtx, func(e key.Event) bool {
+		if ac != nil && ac.navigation(e) {
+			ac.keys = append(ac.keys, e)
+			return true
+		}
+		if e.Name != "Z" || !e.Modifiers.Contain(key.ModShortcut) {
+			return false
+		}
+		history.keys = append(history.keys, e)
+		return true
+	})
+	macro := op.Record(gtx.Ops)
+	dims := p.editorMaterial.Layout(gtx)
+	call := macro.Stop()
+	// 编辑框不处理的Ctrl+Y和Ctrl+F会交给包裹编辑框的区域
+	defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+	var keys key.Set
+	if p.editorMaterial.Editor.Focused() {
+		keys = "Short-Y"
+		if p.config.findBar != nil {
+			keys = "Short-[Y,F]"
+		}
+		if ac != nil && ac.open {
+			keys += "|[↑,↓,⏎,⌤,Tab,⎋]"
+		}
+	}
+	key.InputOp{Tag: history, Keys: keys}.Add(gtx.Ops)
+	if p.config.finder.query != "" {
+		p.paintMatches(gtx)
+	}
+	call.Add(gtx.Ops)
+
+	for _, e := range gtx.Events(history) {
+		if e, ok := e.(key.Event); ok {
+			if ac != nil && ac.navigation(e) {
+				ac.keys = append(ac.keys, e)
+				continue
+			}
+			history.keys = append(history.keys, e)
+		}
+	}
+	if ac != nil {
+		events := ac.keys
+		ac.keys = nil
+		for _, e := range events {
+			p.suggestionKey(e)
+		}
+		p.layoutSuggestions(gtx, dims.Size)
+	}
+	events := history.keys
+	history.keys = nil
+	for _, e := range events {
+		if e.State != key.Press {
+			continue
+		}
+		if e.Name == "F" {
+			if p.config.findBar != nil {
+				p.config.findBar.Open()
+			}
+			continue
+		}
+		if p.editorMaterial.Editor.ReadOnly {
+			continue
+		}
+		if e.Name == "Y" || e.Modifiers.Contain(key.ModShift) {
+			p.Redo()
+		} else {
+			p.Undo()
+		}
+		op.InvalidateOp{}.Add(gtx.Ops)
+	}
+	return dims
+}
+
+// 高亮所有查找结果
+func (p *Editor) paintMatches(gtx glayout.Context) {
+	editor := p.editorMaterial.Editor
+	matches := p.config.finder.search(editor.Text())
+	if len(matches) == 0 {
+		return
+	}
+	// 无法获取滚动位置时不高亮，避免画在错误的位置
+	scroll, ok := editorScroll(editor)
+	if !ok {
+		return
+	}
+	current := p.GetCurrentMatch()
+	for i, m := range matches {
+		col := p.config.matchColor
+		if i == current {
+			col = p.config.currentMatchColor
+		}
+		p.config.regions = editor.Regions(m[0], m[1], p.config.regions[:0])
+		for _, r := range p.config.regions {
+			paint.FillShape(gtx.Ops, col, clip.Rect(r.Bounds.Sub(scroll)).Op())
+		}
+	}
+}
+
+// 查找文字并高亮所有匹配，选中光标之后的第一个匹配，为空时清除查找
+func (p *Editor) Find(query string) *Editor {
+	p.config.finder.setQuery(query)
+	p.search()
+	start, end := p.GetSelection()
+	return p.selectMatch(p.config.finder.next(minInt(start, end), true))
+}
+
+// 查找时是否区分大小写，默认不区分
+func (p *Editor) FindCaseSensitive(caseSensitive bool) *Editor {
+	p.config.finder.setCaseSensitive(caseSensitive)
+	return p
+}
+
+// 查找时是否只匹配完整的单词
+func (p *Editor) FindWholeWord(wholeWord bool) *Editor {
+	p.config.finder.setWholeWord(wholeWord)
+	return p
+}
+
+// 是否把查找的文字作为正则表达式，替换文字中可以使用$1引用分组
+func (p *Editor) FindRegex(regex bool) *Editor {
+	p.config.finder.setRegex(regex)
+	return p
+}
+
+// 选中下一个匹配，到达末尾时从头开始
+func (p *Editor) FindNext() *Editor {
+	p.search()
+	start, end := p.GetSelection()
+	return p.selectMatch(p.config.finder.next(maxInt(start, end), true))
+}
+
+// 选中上一个匹配，到达开头时从末尾开始
+func (p *Editor) FindPrevious() *Editor {
+	p.search()
+	start, end := p.GetSelection()
+	return p.selectMatch(p.config.finder.next(minInt(start, end), false))
+}
+
+// 获取匹配的数量
+func (p *Editor) GetMatchCount() int {
+	return len(p.search())
+}
+
+// 获取当前选中的匹配序号，从0开始，没有选中匹配时返回-1
+func (p *Editor) GetCurrentMatch() int {
+	p.search()
+	start, end := p.GetSelection()
+	return p.config.finder.at(start, end)
+}
+
+// 获取正则表达式的错误，没有错误时返回nil
+func (p *Editor) GetFindError() error {
+	p.search()
+	return p.config.finder.err
+}
+
+// 替换当前选中的匹配并选中下一个匹配，没有选中匹配时只选中下一个匹配
+func (p *Editor) ReplaceCurrent(replacement string) *Editor {
+	i := p.GetCurrentMatch()
+	if i < 0 || p.editorMaterial.Editor.ReadOnly {
+		return p.FindNext()
+	}
+	m := p.config.finder.matches[i]
+	replacement = p.config.finder.replacement(i, replacement)
+	p.SetCaret(m[0], m[1]).Insert(replacement)
+	p.search()
+	return p.selectMatch(p.config.finder.next(m[0]+utf8.RuneCountInString(replacement), true))
+}
+
+// 替换所有匹配，作为一步撤销，返回替换的数量
+func (p *Editor) ReplaceAll(replacement string) int {
+	if p.editorMaterial.Editor.ReadOnly {
+		return 0
+	}
+	finder := &p.config.finder
+	matches := append([][2]int(nil), p.search()...)
+	if len(matches) == 0 {
+		return 0
+	}
+	replacements := make([]string, len(matches))
+	for i := range matches {
+		replacements[i] = finder.replacement(i, replacement)
+	}
+	p.syncHistory()
+	// 从后向前替换，前面的位置不受影响
+	for i := len(matches) - 1; i >= 0; i-- {
+		p.SetCaret(matches[i][0], matches[i][1]).Insert(replacements[i])
+	}
+	p.syncHistory()
+	p.validate()
+	return len(matches)
+}
+
+// 设置查找结果的高亮颜色，当前结果使用更高的透明度
+func (p *Editor) MatchColor(r, g, b, a uint8) *Editor {
+	p.config.matchColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.currentMatchColor = color.NRGBA{R: r, G: g, B: b, A: uint8(minInt(int(a)*2, 0xff))}
+	p.config.themed.overrides.set(overrideMatchColor)
+	return p
+}
+
+// 使用当前文字查找
+func (p *Editor) search() [][2]int {
+	return p.config.finder.search(p.GetText())
+}
+
+// 选中指定的匹配
+func (p *Editor) selectMatch(i int) *Editor {
+	if i < 0 {
+		return p
+	}
+	m := p.config.finder.matches[i]
+	p.SetCaret(m[1], m[0])
+	return p
+}
+
+// 撤销上一步修改，选中撤销后恢复的文字
+func (p *Editor) Undo() *Editor {
+	p.syncHistory()
+	rec, ok := p.config.history.undo()
+	if !ok {
+		return p
+	}
+	p.applyEdit(rec.start, rec.inserted, rec.removed)
+	p.historyChanged()
+	return p
+}
+
+// 重做上一步撤销的修改，选中重做后的文字
+func (p *Editor) Redo() *Editor {
+	p.syncHistory()
+	rec, ok := p.config.history.redo()
+	if !ok {
+		return p
+	}
+	p.applyEdit(rec.start, rec.removed, rec.inserted)
+	p.historyChanged()
+	return p
+}
+
+// 记录还没有通过ChangeEvent记录的修改
+func (p *Editor) syncHistory() {
+	if p.config.history.record(p.GetText()) {
+		p.historyChanged()
+	}
+}
+
+// 将start位置的from替换为to，不记录到历史中
+func (p *Editor) applyEdit(start int, from, to string) {
+	editor := p.editorMaterial.Editor
+	editor.SetCaret(start, start+utf8.RuneCountInString(from))
+	editor.Insert(to)
+	editor.SetCaret(start+utf8.RuneCountInString(to), start)
+	p.config.history.text = editor.Text()
+	p.config.typeface.invalidate()
+}
+
+// 是否可以撤销
+func (p *Editor) CanUndo() bool {
+	return p.config.history.next > 0
+}
+
+// 是否可以重做
+func (p *Editor) CanRedo() bool {
+	return p.config.history.next < len(p.config.history.records)
+}
+
+// 清空撤销和重做历史
+func (p *Editor) ClearHistory() *Editor {
+	changed := len(p.config.history.records) > 0
+	p.config.history.clear(p.GetText())
+	if changed {
+		p.historyChanged()
+	}
+	return p
+}
+
+// 设置最多可以撤销的步数，默认为100，小于等于0时不记录历史
+func (p *Editor) HistoryDepth(depth int) *Editor {
+	canUndo, canRedo := p.CanUndo(), p.CanRedo()
+	p.config.history.setDepth(depth)
+	if canUndo != p.CanUndo() || canRedo != p.CanRedo() {
+		p.historyChanged()
+	}
+	return p
+}
+
+// 编辑历史改变事件，可以用于更新撤销和重做按钮的状态
+func (p *Editor) OnHistoryChanged(fn func(p *Editor, canUndo, canRedo bool)) *Editor {
+	p.config._historyChanged = fn
+	return p
+}
+
+// 触发编辑历史改变事件
+func (p *Editor) historyChanged() {
+	if p.config._historyChanged != nil {
+		p.config._historyChanged(p, p.CanUndo(), p.CanRedo())
+	}
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *Editor) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideFontColor) {
+		p.editorMaterial.Color = th.Text
+	}
+	if !p.config.themed.overrides.has(overrideFontSize) {
+		p.editorMaterial.TextSize = gunit.Sp(th.Body)
+	}
+	if !p.config.themed.overrides.has(overrideHintColor) {
+		p.editorMaterial.HintColor = th.TextSecondary
+	}
+	if !p.config.themed.overrides.has(overrideSelectionColor) {
+		p.editorMaterial.SelectionColor = withAlpha(th.Primary, 0x60)
+	}
+	if !p.config.themed.overrides.has(overrideErrorColor) {
+		p.errorLabel.Color = th.Error
+	}
+	if !p.config.themed.overrides.has(overrideMatchColor) {
+		p.config.matchColor = color.NRGBA{R: 0xff, G: 0xb3, B: 0x00, A: 0x50}
+		p.config.currentMatchColor = color.NRGBA{R: 0xff, G: 0xb3, B: 0x00, A: 0xa0}
+	}
+}
+
+// 执行所有验证器，并在验证状态改变时触发事件
+func (p *Editor) validate() {
+	var err error
+	text := p.GetText()
+	for _, fn := range p.config.validators {
+		if err = fn(text); err != nil {
+			break
+		}
+	}
+	changed := (err == nil) != (p.config.validateErr == nil)
+	p.config.validateErr = err
+	if changed && p.config._validChanged != nil {
+		p.config._validChanged(p, err == nil, err)
+	}
+}
+
 // 设置只读
 func (p *Editor) ReadOnly(readOnly bool) *Editor {
 	p.editorMaterial.Editor.ReadOnly = readOnly
 	return p
 }
 
-// 设置文字
+// 设置文字，会清空撤销和重做历史
 func (p *Editor) Text(text string) *Editor {
 	p.editorMaterial.Editor.SetText(text)
+	p.ClearHistory()
+	p.config.typeface.invalidate()
+	p.validate()
+	return p
+}
+
+// 添加验证器，按添加顺序执行，遇到第一个错误时停止
+//
+// 内置的验证器在validator包中；验证结果会立即更新，但错误信息在用户修改过内容或者离开编辑框之后才显示，
+// 避免空的必填项一打开就显示错误。不传入验证器时表示主动要求验证，例如提交表单时，会立即显示错误信息
+func (p *Editor) Validate(fn ...validator.Validator) *Editor {
+	if len(fn) == 0 {
+		p.config.touched = true
+	}
+	p.config.validators = append(p.config.validators, fn...)
+	p.validate()
+	return p
+}
+
+// 删除所有验证器
+func (p *Editor) ClearValidators() *Editor {
+	p.config.validators = nil
+	p.validate()
+	return p
+}
+
+// 设置验证错误信息的颜色
+func (p *Editor) ErrorColor(r, g, b, a uint8) *Editor {
+	p.errorLabel.Color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideErrorColor)
+	return p
+}
+
+// 设置验证错误信息的字体大小
+func (p *Editor) ErrorFontSize(size float32) *Editor {
+	p.errorLabel.TextSize = gunit.Sp(size)
+	return p
+}
+
+// 是否在编辑框下方显示验证错误信息，默认显示
+//
+// 错误信息在用户修改过内容、离开编辑框或者调用不带参数的Validate之后才显示
+func (p *Editor) ShowError(show bool) *Editor {
+	p.config.showError = show
+	return p
+}
+
+// 当前内容是否通过验证
+func (p *Editor) IsValid() bool {
+	return p.config.validateErr == nil
+}
+
+// 获取当前的验证错误，验证通过时返回nil
+func (p *Editor) GetError() error {
+	return p.config.validateErr
+}
+
+// 验证状态改变事件
+func (p *Editor) OnValidChanged(fn func(p *Editor, valid bool, err error)) *Editor {
+	p.config._validChanged = fn
 	return p
 }
 
@@ -141,6 +661,7 @@ func (p *Editor) AllowOnly(filter string) *Editor {
 // 设置提示文字
 func (p *Editor) Hint(hint string) *Editor {
 	p.editorMaterial.Hint = hint
+	p.config.typeface.invalidate()
 	return p
 }
 
@@ -152,6 +673,7 @@ func (p *Editor) HintColor(r, g, b, a uint8) *Editor {
 		B: b,
 		A: a,
 	}
+	p.config.themed.overrides.set(overrideHintColor)
 	return p
 }
 
@@ -243,7 +765,7 @@ func (p *Editor) OnFocused(fn func(p *Editor, focus bool, text string)) *Editor
 	return p
 }
 
-// 提交事件
+// 提交事件，验证不通过时不会触发
 func (p *Editor) OnSubmit(fn func(p *Editor, text string)) *Editor {
 	p.config._submit = fn
 	return p
@@ -264,6 +786,7 @@ func (p *Editor) OnChange(fn func(p *Editor, text string)) *Editor {
 // 方法在选中处插入一段字符串
 func (p *Editor) Insert(text string) *Editor {
 	p.editorMaterial.Editor.Insert(text)
+	p.config.typeface.invalidate()
 	return p
 }
 
@@ -292,18 +815,55 @@ func (p *Editor) SelectionColor(r, g, b, a uint8) *Editor {
 		B: b,
 		A: a,
 	}
+	p.config.themed.overrides.set(overrideSelectionColor)
 	return p
 }
 
 // 设置文本框字体大小
 func (p *Editor) FontSize(textSize float32) *Editor {
 	p.editorMaterial.TextSize = gunit.Sp(textSize)
+	p.config.themed.overrides.set(overrideFontSize)
+	return p
+}
+
+// 获取字体大小
+func (p *Editor) GetFontSize() float32 {
+	return float32(p.editorMaterial.TextSize)
+}
+
+// 设置文本框字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *Editor) FontFamily(name string) *Editor {
+	p.config.typeface.set(name)
 	return p
 }
 
-// 设置文本框字体粗细
+// 设置文本框字体粗细，与FontWeight相同
 func (p *Editor) TextWeight(textWeight text.Weight) *Editor {
-	p.editorMaterial.Font.Weight = textWeight
+	return p.FontWeight(textWeight)
+}
+
+// 设置字体粗细
+func (p *Editor) FontWeight(weight text.Weight) *Editor {
+	p.editorMaterial.Font.Weight = weight
+	return p
+}
+
+// 设置是否使用斜体
+func (p *Editor) Italic(italic bool) *Editor {
+	p.editorMaterial.Font.Style = gfont.Regular
+	if italic {
+		p.editorMaterial.Font.Style = gfont.Italic
+	}
+	return p
+}
+
+// 设置文本样式，字间距和装饰线会被忽略
+//
+// 文本框的光标、选区和点击位置都由gio根据字形位置计算，自行绘制带字间距或装饰线的文本会与它们错位，所以不支持
+func (p *Editor) TextStyle(style text.Style) *Editor {
+	p.FontFamily(style.Family)
+	p.FontWeight(style.Weight)
+	p.Italic(style.Italic)
 	return p
 }
 
@@ -315,6 +875,7 @@ func (p *Editor) TextColor(r, g, b, a uint8) *Editor {
 		B: b,
 		A: a,
 	}
+	p.config.themed.overrides.set(overrideFontColor)
 	return p
 }
 
@@ -326,10 +887,14 @@ func (p *Editor) KeyboardType(keyboardType text.InputHint) *Editor {
 
 // 创建编辑框
 func NewEditor(hint string) *Editor {
-	editorMaterial := gmaterial.Editor(theme.NewTheme(), &gwidget.Editor{}, hint)
+	th := theme.Material()
+	editorMaterial := gmaterial.Editor(th, &gwidget.Editor{}, hint)
+	errorLabel := gmaterial.Label(th, gunit.Sp(theme.Current().Caption), "")
+	errorLabel.Color = theme.Current().Error
 	return &Editor{
-		config:         &editorConfig{update: true},
+		config:         &editorConfig{update: true, showError: true, themed: newThemed(), typeface: newTypeface(), history: newEditHistory()},
 		margin:         &glayout.Inset{},
 		editorMaterial: &editorMaterial,
+		errorLabel:     &errorLabel,
 	}
 }
@@ -0,0 +1,292 @@
+package widget
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/Seikaijyu/gio/io/key"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &FindBar{}
+
+type findBarConfig struct {
+	// 主题状态
+	themed themed
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 是否打开
+	open bool
+	// 是否显示替换行
+	showReplace bool
+	// 背景颜色
+	background color.NRGBA
+}
+
+// 查找栏，绑定到一个多行编辑框，提供查找、替换和查找选项
+//
+// 编辑框获得焦点时按下Ctrl+F打开，按下Esc关闭
+type FindBar struct {
+	// 组件标识
+	identity
+	// 配置
+	config *findBarConfig
+	// 外边距
+	margin *glayout.Inset
+	// 绑定的编辑框
+	target *Editor
+	// 查找和替换的输入框
+	query, replacement *Editor
+	// 匹配数量
+	count *Label
+	// 查找行和替换行
+	findRow, replaceRow *RowLayout
+	// 整体布局
+	column *ColumnLayout
+}
+
+// 绑定函数
+func (p *FindBar) Then(fn func(self *FindBar)) *FindBar {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *FindBar) ID(id string) *FindBar {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *FindBar) Class(classes ...string) *FindBar {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *FindBar) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *FindBar) Destroy() {
+	p.config.update = false
+	if p.target.config.findBar == p {
+		p.target.config.findBar = nil
+	}
+	p.column.Destroy()
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *FindBar) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 是否更新组件
+func (p *FindBar) Update(update bool) {
+	p.config.update = update
+}
+
+// 外边距
+func (p *FindBar) Margin(Top, Left, Bottom, Right float32) *FindBar {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *FindBar) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 背景颜色
+func (p *FindBar) Background(r, g, b, a uint8) *FindBar {
+	p.config.background = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBackground)
+	return p
+}
+
+// 获取背景颜色
+func (p *FindBar) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.config.background)
+}
+
+// 是否显示替换行，默认显示
+func (p *FindBar) ShowReplace(show bool) *FindBar {
+	p.config.showReplace = show
+	p.replaceRow.Update(show)
+	return p
+}
+
+// 获取绑定的编辑框
+func (p *FindBar) GetTarget() *Editor {
+	return p.target
+}
+
+// 获取查找输入框
+func (p *FindBar) GetQueryEditor() *Editor {
+	return p.query
+}
+
+// 获取替换输入框
+func (p *FindBar) GetReplaceEditor() *Editor {
+	return p.replacement
+}
+
+// 是否已打开
+func (p *FindBar) IsOpen() bool {
+	return p.config.open
+}
+
+// 打开查找栏，编辑框中选中了单行文字时作为查找的文字
+func (p *FindBar) Open() *FindBar {
+	p.config.open = true
+	if selected := p.target.GetSelectedText(); selected != "" && !strings.Contains(selected, "\n") {
+		p.query.Text(selected)
+	}
+	p.query.Focus().SetCaret(0, p.query.GetTextLen())
+	p.target.Find(p.query.GetText())
+	return p
+}
+
+// 关闭查找栏，清除高亮并把焦点还给编辑框
+func (p *FindBar) Close() *FindBar {
+	p.config.open = false
+	p.target.Find("")
+	p.target.Focus()
+	return p
+}
+
+// 渲染UI
+func (p *FindBar) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update || !p.config.open {
+		return glayout.Dimensions{}
+	}
+	p.applyTheme()
+	for _, e := range gtx.Events(p) {
+		if e, ok := e.(key.Event); ok && e.State == key.Press && e.Name == key.NameEscape {
+			p.Close()
+			return glayout.Dimensions{}
+		}
+	}
+	p.updateCount()
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		macro := op.Record(gtx.Ops)
+		dims := p.column.Layout(gtx)
+		call := macro.Stop()
+		defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+		paint.ColorOp{Color: p.config.background}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		// 输入框不处理的Esc会交给这里
+		key.InputOp{Tag: p, Keys: key.NameEscape}.Add(gtx.Ops)
+		call.Add(gtx.Ops)
+		return dims
+	})
+}
+
+// 更新匹配数量的显示
+func (p *FindBar) updateCount() {
+	var text string
+	switch n := p.target.GetMatchCount(); {
+	case p.target.GetFindError() != nil:
+		text = "表达式错误"
+	case p.query.GetText() == "":
+		text = ""
+	case n == 0:
+		text = "无结果"
+	case p.target.GetCurrentMatch() >= 0:
+		text = strconv.Itoa(p.target.GetCurrentMatch()+1) + "/" + strconv.Itoa(n)
+	default:
+		text = strconv.Itoa(n) + "个结果"
+	}
+	p.count.Text(text)
+}
+
+// 重新查找，查找选项改变后调用
+func (p *FindBar) refresh() {
+	p.target.Find(p.query.GetText())
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *FindBar) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideBackground) {
+		p.config.background = th.Surface
+	}
+}
+
+// 创建查找栏并绑定到编辑框，编辑框中按下Ctrl+F时打开
+func NewFindBar(target *Editor) *FindBar {
+	p := &FindBar{
+		config: &findBarConfig{update: true, showReplace: true, themed: newThemed()},
+		margin: &glayout.Inset{},
+		target: target,
+		count:  NewLabel("").Margin(0, 8, 0, 8),
+	}
+	p.query = NewEditor("查找").SingleLine(true).Submit(true).Margin(4, 4, 4, 4).
+		OnChange(func(_ *Editor, text string) {
+			p.target.Find(text)
+		}).
+		OnSubmit(func(_ *Editor, _ string) {
+			p.target.FindNext()
+		})
+	p.replacement = NewEditor("替换为").SingleLine(true).Submit(true).Margin(4, 4, 4, 4).
+		OnSubmit(func(_ *Editor, text string) {
+			p.target.ReplaceCurrent(text)
+		})
+	p.findRow = NewRowLayout().
+		AppendFlexChild(1, p.query).
+		AppendRigidChild(p.count).
+		AppendRigidChild(NewCheckBox("区分大小写").OnChecked(func(_ *CheckBox, check bool) {
+			p.target.FindCaseSensitive(check)
+			p.refresh()
+		})).
+		AppendRigidChild(NewCheckBox("全字匹配").OnChecked(func(_ *CheckBox, check bool) {
+			p.target.FindWholeWord(check)
+			p.refresh()
+		})).
+		AppendRigidChild(NewCheckBox("正则表达式").OnChecked(func(_ *CheckBox, check bool) {
+			p.target.FindRegex(check)
+			p.refresh()
+		})).
+		AppendRigidChild(NewButton("上一个").Margin(4, 4, 4, 4).OnClicked(func(_ *Button) {
+			p.target.FindPrevious()
+		})).
+		AppendRigidChild(NewButton("下一个").Margin(4, 4, 4, 4).OnClicked(func(_ *Button) {
+			p.target.FindNext()
+		})).
+		AppendRigidChild(NewButton("关闭").Margin(4, 4, 4, 4).OnClicked(func(_ *Button) {
+			p.Close()
+		}))
+	p.replaceRow = NewRowLayout().
+		AppendFlexChild(1, p.replacement).
+		AppendRigidChild(NewButton("替换").Margin(4, 4, 4, 4).OnClicked(func(_ *Button) {
+			p.target.ReplaceCurrent(p.replacement.GetText())
+		})).
+		AppendRigidChild(NewButton("全部替换").Margin(4, 4, 4, 4).OnClicked(func(_ *Button) {
+			p.target.ReplaceAll(p.replacement.GetText())
+		}))
+	p.findRow.HorizontalWidget.Alignment = glayout.Middle
+	p.replaceRow.HorizontalWidget.Alignment = glayout.Middle
+	p.column = NewColumnLayout().AppendRigidChild(p.findRow).AppendRigidChild(p.replaceRow)
+	p.column.SetParent(p)
+	target.config.findBar = p
+	return p
+}
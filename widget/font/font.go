@@ -0,0 +1,211 @@
+// 字体注册表
+// 从文件或者内嵌的字节中加载TTF/OTF/TTC字体，以字体族名注册，并可以按文字脚本（例如Han、Latin）设置回退字体
+package font
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/font/opentype"
+	gtext "github.com/Seikaijyu/gio/text"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+)
+
+// 字体样式
+type Style = gfont.Style
+
+const (
+	// 常规
+	Regular Style = gfont.Regular
+	// 斜体
+	Italic Style = gfont.Italic
+)
+
+var (
+	mutex sync.RWMutex
+	// 已经注册的字体
+	collection []gfont.FontFace
+	// 文字脚本对应的回退字体族
+	fallbacks = map[string][]string{}
+	// 回退字体的注册顺序
+	scripts []string
+	// 默认字体族
+	defaultFamily string
+	// 注册表版本，每次修改时增加，组件通过版本判断是否需要重新解析字体
+	version int
+	// 是否需要重新创建文字排版器
+	dirty bool
+)
+
+// 解析字体文件，TTC字体集会返回多个字体
+func parse(data []byte) ([]gfont.FontFace, error) {
+	if bytes.HasPrefix(data, []byte("ttcf")) {
+		return opentype.ParseCollection(data)
+	}
+	face, err := opentype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return []gfont.FontFace{{Font: face.Font(), Face: face}}, nil
+}
+
+// 注册字体
+func register(family string, faces []gfont.FontFace) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, face := range faces {
+		face.Font.Typeface = gfont.Typeface(family)
+		collection = append(collection, face)
+	}
+	version++
+	dirty = true
+}
+
+// 从字节中加载字体并以family为字体族名注册，字重和样式从字体文件中读取
+//
+// 可以配合go:embed使用内嵌的字体文件
+func Load(family string, data []byte) error {
+	faces, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("无法解析字体%s: %w", family, err)
+	}
+	register(family, faces)
+	return nil
+}
+
+// 从字节中加载字体，并使用指定的字重和样式注册，用于字体文件中的信息不正确的情况
+func LoadStyled(family string, weight text.Weight, style Style, data []byte) error {
+	faces, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("无法解析字体%s: %w", family, err)
+	}
+	for i := range faces {
+		faces[i].Font.Weight = weight
+		faces[i].Font.Style = style
+	}
+	register(family, faces)
+	return nil
+}
+
+// 从文件中加载字体并以family为字体族名注册
+func LoadFile(family, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return Load(family, data)
+}
+
+// 设置文字脚本的回退字体族，当文本中包含该脚本的文字时，会优先使用这些字体族
+//
+// script为unicode脚本名，例如Han、Hiragana、Latin、Cyrillic、Arabic
+func Fallback(script string, families ...string) error {
+	if _, ok := unicode.Scripts[script]; !ok {
+		return fmt.Errorf("未知的文字脚本%s", script)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if _, ok := fallbacks[script]; !ok {
+		scripts = append(scripts, script)
+	}
+	fallbacks[script] = families
+	version++
+	return nil
+}
+
+// 设置应用默认字体族，没有设置字体族的组件都会使用此字体族
+func DefaultFamily(family string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	defaultFamily = family
+	version++
+}
+
+// 获取所有已经注册的字体族名
+func Families() []string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	seen := map[string]bool{}
+	var families []string
+	for _, face := range collection {
+		family := string(face.Font.Typeface)
+		if !seen[family] {
+			seen[family] = true
+			families = append(families, family)
+		}
+	}
+	sort.Strings(families)
+	return families
+}
+
+// 获取注册表版本
+func Version() int {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return version
+}
+
+// 根据字体族和文本内容生成字体回退链
+//
+// 顺序为：指定的字体族（为空时使用默认字体族）、文本中出现的脚本的回退字体族、默认字体族
+func Resolve(family, content string) gfont.Typeface {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	var chain []string
+	seen := map[string]bool{}
+	add := func(names ...string) {
+		for _, name := range names {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				chain = append(chain, name)
+			}
+		}
+	}
+	if family == "" {
+		family = defaultFamily
+	}
+	add(family)
+	if len(scripts) > 0 {
+		found := map[string]bool{}
+		for _, r := range content {
+			if r < unicode.MaxASCII && !found["Latin"] && unicode.IsLetter(r) {
+				found["Latin"] = true
+				add(fallbacks["Latin"]...)
+				continue
+			}
+			for _, script := range scripts {
+				if !found[script] && unicode.Is(unicode.Scripts[script], r) {
+					found[script] = true
+					add(fallbacks[script]...)
+				}
+			}
+		}
+	}
+	add(defaultFamily)
+	quoted := make([]string, len(chain))
+	for i, name := range chain {
+		quoted[i] = `"` + strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), `"`, `\"`) + `"`
+	}
+	return gfont.Typeface(strings.Join(quoted, ", "))
+}
+
+// 注册了新字体时重新创建共享的文字排版器，需要在UI循环中调用
+func Refresh() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !dirty {
+		return
+	}
+	dirty = false
+	faces := make([]gfont.FontFace, len(collection))
+	copy(faces, collection)
+	// 所有组件共享同一个排版器指针，所以直接替换指针指向的值
+	*theme.Shaper = *gtext.NewShaper(gtext.WithCollection(faces))
+}
@@ -0,0 +1,83 @@
+package gradient
+
+import (
+	"image/color"
+	"sort"
+)
+
+// 渐变的颜色节点
+type Stop struct {
+	// 节点在渐变线上的位置，0为起点，1为终点
+	Offset float32
+	// 节点的颜色
+	Color color.NRGBA
+}
+
+// 在指定位置创建颜色节点
+func At(offset float32, r, g, b, a uint8) Stop {
+	return Stop{Offset: offset, Color: color.NRGBA{R: r, G: g, B: b, A: a}}
+}
+
+// 将颜色均匀分布在渐变线上，第一个颜色位于起点，最后一个颜色位于终点
+func Even(colors ...color.NRGBA) []Stop {
+	stops := make([]Stop, len(colors))
+	for i, c := range colors {
+		stops[i] = Stop{Color: c}
+		if len(colors) > 1 {
+			stops[i].Offset = float32(i) / float32(len(colors)-1)
+		}
+	}
+	return stops
+}
+
+// 按位置排序颜色节点，位置相同的节点保持原有顺序，返回新的切片
+func Sorted(stops []Stop) []Stop {
+	sorted := append([]Stop(nil), stops...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+	return sorted
+}
+
+// 渐变线上指定位置的颜色，节点需要已经排序
+//
+// 相邻节点之间使用预乘透明度插值，避免渐变到透明色时出现暗边
+func ColorAt(stops []Stop, t float32) color.NRGBA {
+	switch {
+	case len(stops) == 0:
+		return color.NRGBA{}
+	case t <= stops[0].Offset:
+		return stops[0].Color
+	case t >= stops[len(stops)-1].Offset:
+		return stops[len(stops)-1].Color
+	}
+	i := 1
+	for i < len(stops)-1 && t > stops[i].Offset {
+		i++
+	}
+	from, to := stops[i-1], stops[i]
+	span := to.Offset - from.Offset
+	if span <= 0 {
+		return to.Color
+	}
+	return mix(from.Color, to.Color, (t-from.Offset)/span)
+}
+
+// 使用预乘透明度混合两个颜色
+func mix(a, b color.NRGBA, t float32) color.NRGBA {
+	aa, ba := float32(a.A)/0xff, float32(b.A)/0xff
+	alpha := aa + (ba-aa)*t
+	if alpha <= 0 {
+		return color.NRGBA{}
+	}
+	channel := func(x, y uint8) uint8 {
+		v := (float32(x)*aa + (float32(y)*ba-float32(x)*aa)*t) / alpha
+		return uint8(v + 0.5)
+	}
+	return color.NRGBA{
+		R: channel(a.R, b.R),
+		G: channel(a.G, b.G),
+		B: channel(a.B, b.B),
+		A: uint8(alpha*0xff + 0.5),
+	}
+}
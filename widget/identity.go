@@ -0,0 +1,83 @@
+package widget
+
+// 组件标识，记录组件的ID、类名和父节点
+//
+// 嵌入到每个组件中，ID和类名通过组件自身的ID和Class函数设置
+type identity struct {
+	// 组件ID
+	id string
+	// 组件类名
+	classes []string
+	// 父节点
+	parent WidgetInterface
+}
+
+// 获取组件ID
+func (p *identity) GetID() string {
+	return p.id
+}
+
+// 获取组件类名
+func (p *identity) GetClasses() []string {
+	return p.classes
+}
+
+// 是否包含指定类名
+func (p *identity) HasClass(class string) bool {
+	for _, c := range p.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// 获取父节点，不存在时返回nil
+func (p *identity) GetParent() WidgetInterface {
+	return p.parent
+}
+
+// 设置父节点，由父节点的ResetParent调用
+func (p *identity) SetParent(parent WidgetInterface) {
+	p.parent = parent
+}
+
+// 获取所有祖先节点，从父节点开始直到根节点
+func (p *identity) GetAncestors() []WidgetInterface {
+	var ancestors []WidgetInterface
+	for parent := p.parent; parent != nil; parent = ParentOf(parent) {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// 获取组件ID，组件没有实现NodeInterface时返回空字符串
+func IDOf(w WidgetInterface) string {
+	if node, ok := w.(NodeInterface); ok {
+		return node.GetID()
+	}
+	return ""
+}
+
+// 获取组件类名，组件没有实现NodeInterface时返回nil
+func ClassesOf(w WidgetInterface) []string {
+	if node, ok := w.(NodeInterface); ok {
+		return node.GetClasses()
+	}
+	return nil
+}
+
+// 获取组件的父节点，组件没有实现NodeInterface时返回nil
+func ParentOf(w WidgetInterface) WidgetInterface {
+	if node, ok := w.(NodeInterface); ok {
+		return node.GetParent()
+	}
+	return nil
+}
+
+// 设置组件的父节点，组件没有实现NodeInterface时忽略
+func setParent(w WidgetInterface, parent WidgetInterface) {
+	if node, ok := w.(NodeInterface); ok {
+		node.SetParent(parent)
+	}
+}
@@ -0,0 +1,254 @@
+// 通过反射调用组件的链式设置函数，将字符串形式的属性值转换为设置函数的参数
+// 由描述文件加载器和样式表共用
+package setter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Seikaijyu/nenki.ui/utils"
+	"github.com/Seikaijyu/nenki.ui/widget/anchor"
+	"github.com/Seikaijyu/nenki.ui/widget/axis"
+	"github.com/Seikaijyu/nenki.ui/widget/border"
+	"github.com/Seikaijyu/nenki.ui/widget/edge"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+)
+
+// 枚举类型的名字对应的值，属性中可以直接使用名字
+var enums = map[reflect.Type]map[string]int64{
+	reflect.TypeOf(text.Start): {
+		"start": int64(text.Start), "end": int64(text.End), "middle": int64(text.Middle),
+	},
+	reflect.TypeOf(text.WrapHeuristically): {
+		"heuristically": int64(text.WrapHeuristically), "words": int64(text.WrapWords), "graphemes": int64(text.WrapGraphemes),
+	},
+	reflect.TypeOf(text.Normal): {
+		"thin": int64(text.Thin), "extralight": int64(text.ExtraLight), "light": int64(text.Light),
+		"normal": int64(text.Normal), "medium": int64(text.Medium), "semibold": int64(text.SemiBold),
+		"bold": int64(text.Bold), "extrabold": int64(text.ExtraBold), "black": int64(text.Black),
+	},
+	reflect.TypeOf(text.HintAny): {
+		"any": int64(text.HintAny), "text": int64(text.HintText), "numeric": int64(text.HintNumeric),
+		"email": int64(text.HintEmail), "url": int64(text.HintURL), "telephone": int64(text.HintTelephone),
+		"password": int64(text.HintPassword),
+	},
+	reflect.TypeOf(text.Underline): {
+		"none": int64(text.DecorationNone), "underline": int64(text.Underline), "strikethrough": int64(text.Strikethrough),
+	},
+	reflect.TypeOf(axis.Horizontal): {
+		"horizontal": int64(axis.Horizontal), "vertical": int64(axis.Vertical),
+	},
+	reflect.TypeOf(border.Solid): {
+		"solid": int64(border.Solid), "dashed": int64(border.Dashed), "dotted": int64(border.Dotted),
+	},
+	reflect.TypeOf(edge.Top): {
+		"top": int64(edge.Top), "left": int64(edge.Left), "bottom": int64(edge.Bottom), "right": int64(edge.Right),
+	},
+	reflect.TypeOf(anchor.Center): {
+		"topleft": int64(anchor.TopLeft), "top": int64(anchor.Top), "topright": int64(anchor.TopRight),
+		"right": int64(anchor.Right), "bottomright": int64(anchor.BottomRight), "bottom": int64(anchor.Bottom),
+		"bottomleft": int64(anchor.BottomLeft), "left": int64(anchor.Left), "center": int64(anchor.Center),
+	},
+}
+
+// 注册枚举类型的名字，用于不能被本包引用的组件包，例如依赖widget包的图表
+//
+// 需要在包的init中调用，typ为枚举的类型
+func RegisterEnum(typ reflect.Type, names map[string]int64) {
+	enums[typ] = names
+}
+
+// 组件类型可以作为属性使用的设置函数和事件
+var properties = map[reflect.Type]map[string]bool{}
+
+// 注册组件可以在描述文件和样式表中使用的属性，names为设置函数或事件函数的名字
+//
+// 需要在组件包的init中调用，target为组件的指针，例如&Button{}；
+// 没有注册的方法，例如Undo、Focus、Insert等操作，不能作为属性调用
+func RegisterProperties(target any, names ...string) {
+	typ := reflect.TypeOf(target)
+	if properties[typ] == nil {
+		properties[typ] = map[string]bool{}
+	}
+	for _, name := range names {
+		properties[typ][name] = true
+	}
+}
+
+// 获取组件注册的所有属性名，按名字排序
+func Properties(target any) []string {
+	var names []string
+	for name := range properties[reflect.TypeOf(target)] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 将属性名转换为方法名，允许首字母小写
+func methodName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return strings.ToUpper(string(r)) + name[size:]
+}
+
+// 获取组件上注册为属性的设置函数
+func Method(target any, name string) (reflect.Value, error) {
+	name = methodName(name)
+	method := reflect.ValueOf(target).MethodByName(name)
+	if !method.IsValid() || !properties[reflect.TypeOf(target)][name] {
+		return reflect.Value{}, fmt.Errorf("不支持属性%s", name)
+	}
+	return method, nil
+}
+
+// 获取属性对应的获取函数，名字为Get加设置函数名，返回值与设置函数的参数一一对应
+//
+// 样式表通过获取函数记录属性设置之前的值，没有对应的获取函数时返回false
+func Getter(target any, name string) (reflect.Value, bool) {
+	name = methodName(name)
+	set := reflect.ValueOf(target).MethodByName(name)
+	get := reflect.ValueOf(target).MethodByName("Get" + name)
+	if !set.IsValid() || !get.IsValid() {
+		return reflect.Value{}, false
+	}
+	st, gt := set.Type(), get.Type()
+	if st.IsVariadic() || st.NumIn() == 0 || gt.NumIn() != 0 || gt.NumOut() != st.NumIn() {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < st.NumIn(); i++ {
+		if gt.Out(i) != st.In(i) || !st.In(i).Comparable() {
+			return reflect.Value{}, false
+		}
+	}
+	return get, true
+}
+
+// 将属性值转换为设置函数的参数
+//
+// 多个参数使用逗号分隔；只有一个值时所有参数都使用这个值，四个uint8参数时可以使用颜色字符串，例如#ff0000、rgb(255, 0, 0)或red
+func Args(method reflect.Type, raw string) ([]reflect.Value, error) {
+	count := method.NumIn()
+	// 单参数时不拆分，避免文本中的逗号被误拆
+	values := []string{raw}
+	switch {
+	// 颜色可以直接使用16进制字符串、CSS函数或颜色名称
+	case count == 4 && method.In(0).Kind() == reflect.Uint8 && isColor(raw):
+		c, err := utils.ParseColor(raw)
+		if err != nil {
+			return nil, err
+		}
+		values = []string{fmt.Sprint(c.R), fmt.Sprint(c.G), fmt.Sprint(c.B), fmt.Sprint(c.A)}
+	case count > 1:
+		values = strings.Split(raw, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		// 所有参数使用同一个值，例如Margin="5"
+		if len(values) == 1 {
+			for len(values) < count {
+				values = append(values, values[0])
+			}
+		}
+	}
+	if len(values) != count {
+		return nil, fmt.Errorf("需要%d个参数，实际为%d个", count, len(values))
+	}
+	args := make([]reflect.Value, count)
+	for i, value := range values {
+		arg, err := Convert(method.In(i), value)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// 调用组件上的设置函数
+func Call(target any, name, raw string) error {
+	method, err := Method(target, name)
+	if err != nil {
+		return err
+	}
+	if method.Type().NumIn() == 1 && method.Type().In(0).Kind() == reflect.Func {
+		return fmt.Errorf("属性%s需要绑定处理函数", name)
+	}
+	args, err := Args(method.Type(), raw)
+	if err != nil {
+		return err
+	}
+	method.Call(args)
+	return nil
+}
+
+// 将字符串转换为指定类型的值
+func Convert(typ reflect.Type, raw string) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return value, fmt.Errorf("无效的布尔值%q", raw)
+		}
+		value.SetBool(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return value, fmt.Errorf("无效的数字%q", raw)
+		}
+		value.SetFloat(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if names, ok := enums[typ]; ok {
+			if v, ok := names[strings.ToLower(raw)]; ok {
+				value.SetInt(v)
+				return value, nil
+			}
+		}
+		v, err := strconv.ParseInt(raw, 10, typ.Bits())
+		if err != nil {
+			// rune类型允许直接使用单个字符，例如Mask="*"
+			if typ.Kind() == reflect.Int32 && utf8.RuneCountInString(raw) == 1 {
+				r, _ := utf8.DecodeRuneInString(raw)
+				value.SetInt(int64(r))
+				return value, nil
+			}
+			return value, fmt.Errorf("无效的值%q", raw)
+		}
+		value.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if names, ok := enums[typ]; ok {
+			if v, ok := names[strings.ToLower(raw)]; ok {
+				value.SetUint(uint64(v))
+				return value, nil
+			}
+		}
+		v, err := strconv.ParseUint(raw, 10, typ.Bits())
+		if err != nil {
+			return value, fmt.Errorf("无效的值%q", raw)
+		}
+		value.SetUint(v)
+	default:
+		return value, fmt.Errorf("不支持的参数类型%s", typ)
+	}
+	return value, nil
+}
+
+// 是否为颜色字符串，数字仍然按照所有参数使用同一个值处理
+func isColor(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "#") || strings.Contains(raw, "(") {
+		return true
+	}
+	for _, r := range raw {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return raw != ""
+}
@@ -0,0 +1,197 @@
+// 文本绘制
+// 在gio的widget.Label基础上增加字间距和装饰线，供需要自行绘制文本的组件使用
+package textpaint
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/Seikaijyu/gio/f32"
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/io/semantic"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gtext "github.com/Seikaijyu/gio/text"
+	gunit "github.com/Seikaijyu/gio/unit"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"golang.org/x/image/math/fixed"
+)
+
+// 文本绘制器
+type Label struct {
+	// 文本对齐方式
+	Alignment text.Alignment
+	// 最大行数，0表示不限制
+	MaxLines int
+	// 超出最大行数时显示的截断符号
+	Truncator string
+	// 换行策略
+	WrapPolicy text.WrapPolicy
+	// 行高
+	LineHeight gunit.Sp
+	// 行高缩放
+	LineHeightScale float32
+	// 字间距，不参与换行计算
+	LetterSpacing gunit.Sp
+	// 装饰线
+	Decoration text.Decoration
+}
+
+// 一行文本
+type line struct {
+	// 在glyphs中的范围
+	start, end int
+	// 字间距带来的偏移
+	shift fixed.Int26_6
+}
+
+// 绘制文本
+func (l Label) Layout(gtx glayout.Context, lt *gtext.Shaper, font gfont.Font, size gunit.Sp, txt string, col color.NRGBA) glayout.Dimensions {
+	cs := gtx.Constraints
+	textSize := fixed.I(gtx.Sp(size))
+	lt.LayoutString(gtext.Parameters{
+		Font:            font,
+		PxPerEm:         textSize,
+		MaxLines:        l.MaxLines,
+		Truncator:       l.Truncator,
+		Alignment:       l.Alignment,
+		WrapPolicy:      l.WrapPolicy,
+		MaxWidth:        cs.Max.X,
+		MinWidth:        cs.Min.X,
+		Locale:          gtx.Locale,
+		LineHeight:      fixed.I(gtx.Sp(l.LineHeight)),
+		LineHeightScale: l.LineHeightScale,
+	}, txt)
+	// 先收集所有字形，按行应用字间距
+	var glyphs []gtext.Glyph
+	var lines []line
+	seen := 0
+	for g, ok := lt.NextGlyph(); ok; g, ok = lt.NextGlyph() {
+		glyphs = append(glyphs, g)
+		if g.Flags&gtext.FlagLineBreak != 0 {
+			lines = append(lines, line{start: seen, end: len(glyphs)})
+			seen = len(glyphs)
+			if l.MaxLines > 0 && len(lines) == l.MaxLines {
+				break
+			}
+		}
+	}
+	if seen < len(glyphs) {
+		lines = append(lines, line{start: seen, end: len(glyphs)})
+	}
+	spacing := fixed.I(gtx.Sp(l.LetterSpacing))
+	for i := range lines {
+		ln := &lines[i]
+		if spacing == 0 || ln.end-ln.start < 2 {
+			continue
+		}
+		extra := spacing * fixed.Int26_6(ln.end-ln.start-1)
+		switch l.Alignment {
+		case text.Middle:
+			ln.shift = -extra / 2
+		case text.End:
+			ln.shift = -extra
+		}
+		for j := ln.start; j < ln.end; j++ {
+			glyphs[j].X += ln.shift + spacing*fixed.Int26_6(j-ln.start)
+		}
+	}
+
+	m := op.Record(gtx.Ops)
+	semantic.LabelOp(txt).Add(gtx.Ops)
+	var bounds, padding image.Rectangle
+	baseline := 0
+	for i, ln := range lines {
+		for j, g := range glyphs[ln.start:ln.end] {
+			logical := image.Rectangle{
+				Min: image.Pt(g.X.Floor(), int(g.Y)-g.Ascent.Ceil()),
+				Max: image.Pt((g.X + g.Advance).Ceil(), int(g.Y)+g.Descent.Ceil()),
+			}
+			if i == 0 && j == 0 {
+				bounds = logical
+				baseline = int(g.Y)
+			} else {
+				bounds = bounds.Union(logical)
+			}
+			// 字形超出逻辑边界的部分
+			padding.Min.X = minInt(padding.Min.X, g.Bounds.Min.X.Floor())
+			padding.Max.X = maxInt(padding.Max.X, (g.Bounds.Max.X - g.Advance).Ceil())
+			padding.Min.Y = minInt(padding.Min.Y, (g.Bounds.Min.Y + g.Ascent).Floor())
+			padding.Max.Y = maxInt(padding.Max.Y, (g.Bounds.Max.Y - g.Descent).Ceil())
+		}
+		Glyphs(gtx, lt, glyphs[ln.start:ln.end], col)
+		if l.Decoration != text.DecorationNone && ln.end > ln.start {
+			// 换行符等不可见字形的宽度为0，不影响装饰线的长度
+			first, last := glyphs[ln.start], glyphs[ln.end-1]
+			Decorate(gtx, l.Decoration, first.X.Floor(), (last.X + last.Advance).Ceil(), int(first.Y), textSize, col)
+		}
+	}
+	call := m.Stop()
+	viewport := image.Rectangle{Max: cs.Max}
+	viewport.Min = viewport.Min.Add(padding.Min)
+	viewport.Max = viewport.Max.Add(padding.Max)
+	clipStack := clip.Rect(viewport).Push(gtx.Ops)
+	call.Add(gtx.Ops)
+	clipStack.Pop()
+	dims := glayout.Dimensions{Size: cs.Constrain(bounds.Size())}
+	dims.Baseline = dims.Size.Y - baseline
+	return dims
+}
+
+// 绘制字形，字形必须属于同一行
+func Glyphs(gtx glayout.Context, lt *gtext.Shaper, glyphs []gtext.Glyph, col color.NRGBA) {
+	if len(glyphs) == 0 {
+		return
+	}
+	off := f32.Point{X: float32(glyphs[0].X) / 64, Y: float32(glyphs[0].Y)}
+	t := op.Affine(f32.Affine2D{}.Offset(off)).Push(gtx.Ops)
+	// 字形绘制有数量限制，分批绘制
+	for len(glyphs) > 0 {
+		n := minInt(len(glyphs), 32)
+		batch := glyphs[:n]
+		glyphs = glyphs[n:]
+		inner := op.Affine(f32.Affine2D{}.Offset(f32.Pt(float32(batch[0].X)/64-off.X, 0))).Push(gtx.Ops)
+		outline := clip.Outline{Path: lt.Shape(batch)}.Op().Push(gtx.Ops)
+		paint.ColorOp{Color: col}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		outline.Pop()
+		if call := lt.Bitmaps(batch); call != (op.CallOp{}) {
+			call.Add(gtx.Ops)
+		}
+		inner.Pop()
+	}
+	t.Pop()
+}
+
+// 在基线y处绘制从x0到x1的装饰线，textSize为字体的像素大小
+func Decorate(gtx glayout.Context, decoration text.Decoration, x0, x1, y int, textSize fixed.Int26_6, col color.NRGBA) {
+	if decoration == text.DecorationNone || x1 <= x0 {
+		return
+	}
+	em := float32(textSize) / 64
+	thickness := maxInt(1, int(em/14+0.5))
+	if decoration.Has(text.Underline) {
+		top := y + maxInt(1, int(em*0.1))
+		paint.FillShape(gtx.Ops, col, clip.Rect{Min: image.Pt(x0, top), Max: image.Pt(x1, top+thickness)}.Op())
+	}
+	if decoration.Has(text.Strikethrough) {
+		top := y - int(em*0.3) - thickness/2
+		paint.FillShape(gtx.Ops, col, clip.Rect{Min: image.Pt(x0, top), Max: image.Pt(x1, top+thickness)}.Op())
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
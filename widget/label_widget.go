@@ -3,9 +3,12 @@ package widget
 import (
 	"image/color"
 
+	gfont "github.com/Seikaijyu/gio/font"
 	glayout "github.com/Seikaijyu/gio/layout"
 	gunit "github.com/Seikaijyu/gio/unit"
+	gwidget "github.com/Seikaijyu/gio/widget"
 	gmaterial "github.com/Seikaijyu/gio/widget/material"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
 	"github.com/Seikaijyu/nenki.ui/widget/text"
 	"github.com/Seikaijyu/nenki.ui/widget/theme"
 )
@@ -14,6 +17,16 @@ import (
 var _ WidgetInterface = &Label{}
 
 type labelConfig struct {
+	// 主题状态
+	themed themed
+	// 字体族
+	typeface typeface
+	// 字间距
+	letterSpacing float32
+	// 装饰线
+	decoration text.Decoration
+	// 选择文本事件
+	_select func(*Label, string)
 	// 是否更新组件
 	update bool
 	// 删除事件
@@ -21,6 +34,8 @@ type labelConfig struct {
 }
 
 type Label struct {
+	// 组件标识
+	identity
 	// 配置
 	config *labelConfig
 	// 外边距
@@ -35,6 +50,23 @@ func (p *Label) Then(fn func(self *Label)) *Label {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *Label) ID(id string) *Label {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Label) Class(classes ...string) *Label {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Label) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *Label) Destroy() {
 	p.config.update = false
@@ -63,12 +95,39 @@ func (p *Label) Margin(Top, Left, Bottom, Right float32) *Label {
 	return p
 }
 
+// 获取外边距
+func (p *Label) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 布局
 func (p *Label) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		p.config.update = false
 	}
+	p.applyTheme()
+	p.labelWidget.Font.Typeface = p.config.typeface.resolve(p.labelWidget.Text)
+	if p.labelWidget.State != nil && p.config._select != nil {
+		for _, item := range p.labelWidget.State.Events() {
+			if _, ok := item.(gwidget.SelectEvent); ok {
+				p.config._select(p, p.labelWidget.State.SelectedText())
+			}
+		}
+	}
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		// 有字间距或装饰线时自行绘制文本，可以选择时使用默认的绘制方式
+		if p.labelWidget.State == nil && (p.config.letterSpacing != 0 || p.config.decoration != text.DecorationNone) {
+			return textpaint.Label{
+				Alignment:       p.labelWidget.Alignment,
+				MaxLines:        p.labelWidget.MaxLines,
+				Truncator:       p.labelWidget.Truncator,
+				WrapPolicy:      p.labelWidget.WrapPolicy,
+				LineHeight:      p.labelWidget.LineHeight,
+				LineHeightScale: p.labelWidget.LineHeightScale,
+				LetterSpacing:   gunit.Sp(p.config.letterSpacing),
+				Decoration:      p.config.decoration,
+			}.Layout(gtx, p.labelWidget.Shaper, p.labelWidget.Font, p.labelWidget.TextSize, p.labelWidget.Text, p.labelWidget.Color)
+		}
 		return p.labelWidget.Layout(gtx)
 	})
 }
@@ -82,12 +141,100 @@ func (p *Label) Text(text string) *Label {
 // 设置文本大小
 func (p *Label) FontSize(size float32) *Label {
 	p.labelWidget.TextSize = gunit.Sp(size)
+	p.config.themed.overrides.set(overrideFontSize)
+	return p
+}
+
+// 获取字体大小
+func (p *Label) GetFontSize() float32 {
+	return float32(p.labelWidget.TextSize)
+}
+
+// 设置字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *Label) FontFamily(name string) *Label {
+	p.config.typeface.set(name)
+	return p
+}
+
+// 设置字体粗细
+func (p *Label) FontWeight(weight text.Weight) *Label {
+	p.labelWidget.Font.Weight = weight
+	return p
+}
+
+// 设置是否使用斜体
+func (p *Label) Italic(italic bool) *Label {
+	p.labelWidget.Font.Style = gfont.Regular
+	if italic {
+		p.labelWidget.Font.Style = gfont.Italic
+	}
+	return p
+}
+
+// 设置文本样式
+func (p *Label) TextStyle(style text.Style) *Label {
+	p.FontFamily(style.Family)
+	p.FontWeight(style.Weight)
+	p.Italic(style.Italic)
+	p.LetterSpacing(style.LetterSpacing)
+	p.Decoration(style.Decoration)
+	return p
+}
+
+// 设置字间距，字间距不参与换行计算
+func (p *Label) LetterSpacing(spacing float32) *Label {
+	p.config.letterSpacing = spacing
+	return p
+}
+
+// 设置装饰线，例如text.Underline|text.Strikethrough
+func (p *Label) Decoration(decoration text.Decoration) *Label {
+	p.config.decoration = decoration
 	return p
 }
 
 // 设置文字颜色
 func (p *Label) FontColor(r, g, b, a uint8) *Label {
 	p.labelWidget.Color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
+	return p
+}
+
+// 获取文字颜色
+func (p *Label) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.labelWidget.Color)
+}
+
+// 设置文本是否可以选择，可以选择时支持拖动选择、双击选择单词、Ctrl+A全选和Ctrl+C复制，但文本不能编辑
+//
+// 可以选择时不支持字间距和装饰线
+func (p *Label) Selectable(selectable bool) *Label {
+	if !selectable {
+		p.labelWidget.State = nil
+	} else if p.labelWidget.State == nil {
+		p.labelWidget.State = &gwidget.Selectable{}
+	}
+	return p
+}
+
+// 设置选中区域颜色
+func (p *Label) SelectionColor(r, g, b, a uint8) *Label {
+	p.labelWidget.SelectionColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideSelectionColor)
+	return p
+}
+
+// 获取选中的文本
+func (p *Label) GetSelectedText() string {
+	if p.labelWidget.State == nil {
+		return ""
+	}
+	return p.labelWidget.State.SelectedText()
+}
+
+// 选择文本事件，选择范围改变时触发
+func (p *Label) OnSelect(fn func(p *Label, text string)) *Label {
+	p.config._select = fn
 	return p
 }
 
@@ -111,7 +258,7 @@ func (p *Label) LineHeightScale(lineHeightScale float32) *Label {
 
 // 设置文本最大行
 func (p *Label) MaxLines(maxLines int) *Label {
-	p.labelWidget.State.MaxLines = maxLines
+	p.labelWidget.MaxLines = maxLines
 	return p
 }
 
@@ -127,12 +274,29 @@ func (p *Label) WrapPolicy(wrapPolicy text.WrapPolicy) *Label {
 	return p
 }
 
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *Label) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideFontColor) {
+		p.labelWidget.Color = th.Text
+	}
+	if !p.config.themed.overrides.has(overrideFontSize) {
+		p.labelWidget.TextSize = gunit.Sp(th.Subtitle)
+	}
+	if !p.config.themed.overrides.has(overrideSelectionColor) {
+		p.labelWidget.SelectionColor = withAlpha(th.Primary, 0x60)
+	}
+}
+
 // 设置文本
 func NewLabel(text string) *Label {
-	label := gmaterial.Label(theme.NewTheme(), gunit.Sp(18), text)
+	label := gmaterial.Label(theme.Material(), gunit.Sp(theme.Current().Subtitle), text)
 	return &Label{
 		labelWidget: &label,
 		margin:      &glayout.Inset{},
-		config:      &labelConfig{},
+		config:      &labelConfig{themed: newThemed(), typeface: newTypeface()},
 	}
 }
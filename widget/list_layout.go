@@ -13,6 +13,8 @@ import (
 
 // 列表配置
 type listLayoutConfig struct {
+	// 主题状态
+	themed themed
 	// 是否更新组件
 	update bool
 	// 删除事件
@@ -21,6 +23,8 @@ type listLayoutConfig struct {
 
 // 列表布局
 type ListLayout struct {
+	// 组件标识
+	identity
 	// 配置
 	config       *listLayoutConfig
 	margin       *glayout.Inset
@@ -39,11 +43,28 @@ func (p *ListLayout) Then(fn func(self *ListLayout)) *ListLayout {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *ListLayout) ID(id string) *ListLayout {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *ListLayout) Class(classes ...string) *ListLayout {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *ListLayout) OnDestroy(fn func()) {
 	p.config._destroy = fn
 }
 
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *ListLayout) themeState() *themed {
+	return &p.config.themed
+}
+
 // 删除组件
 func (p *ListLayout) Destroy() {
 	p.config.update = false
@@ -64,8 +85,10 @@ func (p *ListLayout) Update(update bool) {
 func (p *ListLayout) ResetParent(child WidgetInterface) {
 	child.Destroy()
 	child.Update(true)
+	setParent(child, p)
 	child.OnDestroy(func() {
 		child.Update(false)
+		setParent(child, nil)
 		p.RemoveChild(child)
 	})
 }
@@ -147,6 +170,7 @@ func (p *ListLayout) ScrollBgColor(r, g, b, a uint8) *ListLayout {
 		B: b,
 		A: a,
 	}
+	p.config.themed.overrides.set(overrideTrackColor)
 	return p
 }
 
@@ -183,6 +207,7 @@ func (p *ListLayout) ScrollColor(r, g, b, a uint8) *ListLayout {
 		B: b,
 		A: a,
 	}
+	p.config.themed.overrides.set(overrideMarkColor)
 	return p
 }
 
@@ -194,6 +219,7 @@ func (p *ListLayout) ScrollHoverColor(r, g, b, a uint8) *ListLayout {
 		B: b,
 		A: a,
 	}
+	p.config.themed.overrides.set(overrideHoverColor)
 	return p
 }
 
@@ -218,11 +244,17 @@ func (p *ListLayout) Margin(Top, Left, Bottom, Right float32) *ListLayout {
 	return p
 }
 
+// 获取外边距
+func (p *ListLayout) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 渲染UI
 func (p *ListLayout) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		return glayout.Dimensions{}
 	}
+	p.applyTheme()
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
 		return p.listMaterial.Layout(gtx, len(p.childWidgets), func(gtx glayout.Context, index int) glayout.Dimensions {
 			return p.childWidgets[index].Layout(gtx)
@@ -230,6 +262,20 @@ func (p *ListLayout) Layout(gtx glayout.Context) glayout.Dimensions {
 	})
 }
 
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *ListLayout) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideMarkColor) {
+		p.listMaterial.Indicator.Color = withAlpha(th.Text, 150)
+	}
+	if !p.config.themed.overrides.has(overrideHoverColor) {
+		p.listMaterial.Indicator.HoverColor = withAlpha(th.Text, 200)
+	}
+}
+
 // 设置方向
 func (p *ListLayout) Axis(axis axis.Axis) *ListLayout {
 	p.listWidget.Axis = axis
@@ -246,6 +292,6 @@ func NewListLayout(axis axis.Axis) *ListLayout {
 		margin:       &glayout.Inset{},
 		listWidget:   &listWidget.List,
 		listMaterial: &listMaterial,
-		config:       &listLayoutConfig{update: true},
+		config:       &listLayoutConfig{update: true, themed: newThemed()},
 	}
 }
@@ -0,0 +1,44 @@
+package loader
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Seikaijyu/nenki.ui/widget/internal/setter"
+)
+
+// 将属性应用到组件的同名设置函数上
+func (b *builder) apply(target any, n *node, skip map[string]bool) error {
+	for _, a := range n.attrs {
+		if skip[strings.ToLower(a.name)] {
+			continue
+		}
+		method, err := setter.Method(target, a.name)
+		if err != nil {
+			return &Error{Line: n.line, Msg: fmt.Sprintf("元素%s不支持属性%s", n.name, a.name)}
+		}
+		args, err := b.args(method.Type(), a)
+		if err != nil {
+			return &Error{Line: n.line, Msg: fmt.Sprintf("属性%s: %s", a.name, err.Error())}
+		}
+		method.Call(args)
+	}
+	return nil
+}
+
+// 将属性值转换为设置函数的参数，事件属性的值为处理函数的名字
+func (b *builder) args(method reflect.Type, a attr) ([]reflect.Value, error) {
+	if method.NumIn() == 1 && method.In(0).Kind() == reflect.Func {
+		handler, ok := b.handlers[a.value]
+		if !ok {
+			return nil, fmt.Errorf("未注册的处理函数%s", a.value)
+		}
+		fn := reflect.ValueOf(handler)
+		if !fn.Type().AssignableTo(method.In(0)) {
+			return nil, fmt.Errorf("处理函数%s的类型%s与%s不匹配", a.value, fn.Type(), method.In(0))
+		}
+		return []reflect.Value{fn}, nil
+	}
+	return setter.Args(method, a.value)
+}
@@ -0,0 +1,240 @@
+package loader
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Seikaijyu/nenki.ui/utils"
+	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/anchor"
+	"github.com/Seikaijyu/nenki.ui/widget/axis"
+	"github.com/Seikaijyu/nenki.ui/widget/chart"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/setter"
+)
+
+// 元素定义
+type element struct {
+	// 创建组件，并添加子组件
+	build func(b *builder, n *node) (widget.WidgetInterface, error)
+	// 在创建时已经使用的属性，不会再作为设置函数调用
+	consumes []string
+}
+
+// 所有支持的元素
+var elements map[string]element
+
+func init() {
+	elements = map[string]element{
+		"ContainerLayout": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			container := widget.NewContainerLayout()
+			child, err := b.singleChild(n)
+			if err != nil || child == nil {
+				return container, err
+			}
+			return container.AppendChild(child), nil
+		}},
+		"AnchorLayout": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			layout := widget.NewAnchorLayout(anchor.Center)
+			child, err := b.singleChild(n)
+			if err != nil || child == nil {
+				return layout, err
+			}
+			return layout.AppendChild(child), nil
+		}},
+		"Border": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			child, err := b.singleChild(n)
+			if err != nil {
+				return nil, err
+			}
+			if child == nil {
+				return nil, &Error{Line: n.line, Msg: "Border必须包含一个子元素"}
+			}
+			return widget.NewBorder(child), nil
+		}},
+		"RowLayout": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			row := widget.NewRowLayout()
+			return row, flexChildren(b, n, row.AppendRigidChild, row.AppendFlexChild, row.AppendFlexAnchorChild)
+		}},
+		"ColumnLayout": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			column := widget.NewColumnLayout()
+			return column, flexChildren(b, n, column.AppendRigidChild, column.AppendFlexChild, column.AppendFlexAnchorChild)
+		}},
+		"ListLayout": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			list := widget.NewListLayout(axis.Vertical)
+			for _, c := range n.children {
+				child, err := b.build(c)
+				if err != nil {
+					return nil, err
+				}
+				list.AppendChild(child)
+			}
+			return list, nil
+		}},
+		"RadioButtons": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			radio := widget.NewRadioButtons(axis.Vertical)
+			for _, c := range n.children {
+				if c.name != "Radio" {
+					return nil, &Error{Line: c.line, Msg: fmt.Sprintf("RadioButtons中只能包含Radio元素，而不是%s", c.name)}
+				}
+				key, ok := c.attr("key")
+				if !ok {
+					return nil, &Error{Line: c.line, Msg: "Radio缺少key属性"}
+				}
+				text, _ := c.attr("text")
+				radio.AppendRadioButton(key, text)
+			}
+			return radio, nil
+		}},
+		"RichText": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			rich := widget.NewRichText()
+			for _, c := range n.children {
+				if c.name != "Span" {
+					return nil, &Error{Line: c.line, Msg: fmt.Sprintf("RichText中只能包含Span元素，而不是%s", c.name)}
+				}
+				span, err := buildSpan(c)
+				if err != nil {
+					return nil, err
+				}
+				rich.AppendSpan(span)
+			}
+			return rich, nil
+		}},
+		"Slider": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			sliderAxis, err := axisAttr(n)
+			if err != nil {
+				return nil, err
+			}
+			return widget.NewSlider(sliderAxis), nil
+		}, consumes: []string{"axis"}},
+		"RangeSlider": {build: func(b *builder, n *node) (widget.WidgetInterface, error) {
+			sliderAxis, err := axisAttr(n)
+			if err != nil {
+				return nil, err
+			}
+			return widget.NewRangeSlider(sliderAxis), nil
+		}, consumes: []string{"axis"}},
+		"Button":         {build: leaf(func() widget.WidgetInterface { return widget.NewButton("") })},
+		"Label":          {build: leaf(func() widget.WidgetInterface { return widget.NewLabel("") })},
+		"Editor":         {build: leaf(func() widget.WidgetInterface { return widget.NewEditor("") })},
+		"CheckBox":       {build: leaf(func() widget.WidgetInterface { return widget.NewCheckBox("") })},
+		"Switch":         {build: leaf(func() widget.WidgetInterface { return widget.NewSwitch() })},
+		"Markdown":       {build: leaf(func() widget.WidgetInterface { return widget.NewMarkdown("") })},
+		"CodeEditor":     {build: leaf(func() widget.WidgetInterface { return widget.NewCodeEditor() })},
+		"NumberInput":    {build: leaf(func() widget.WidgetInterface { return widget.NewNumberInput() })},
+		"DatePicker":     {build: leaf(func() widget.WidgetInterface { return widget.NewDatePicker() })},
+		"TimePicker":     {build: leaf(func() widget.WidgetInterface { return widget.NewTimePicker() })},
+		"DateTimePicker": {build: leaf(func() widget.WidgetInterface { return widget.NewDateTimePicker() })},
+		"ColorPicker":    {build: leaf(func() widget.WidgetInterface { return widget.NewColorPicker() })},
+		"Canvas":         {build: leaf(func() widget.WidgetInterface { return widget.NewCanvas() })},
+		"LineChart":      {build: leaf(func() widget.WidgetInterface { return chart.NewLineChart() })},
+		"AreaChart":      {build: leaf(func() widget.WidgetInterface { return chart.NewAreaChart() })},
+		"BarChart":       {build: leaf(func() widget.WidgetInterface { return chart.NewBarChart() })},
+		"PieChart":       {build: leaf(func() widget.WidgetInterface { return chart.NewPieChart() })},
+		"DonutChart":     {build: leaf(func() widget.WidgetInterface { return chart.NewDonutChart() })},
+		"ScatterChart":   {build: leaf(func() widget.WidgetInterface { return chart.NewScatterChart() })},
+	}
+}
+
+// 读取滑块的方向属性，默认为水平
+func axisAttr(n *node) (axis.Axis, error) {
+	value, ok := n.attr("axis")
+	if !ok {
+		return axis.Horizontal, nil
+	}
+	v, err := setter.Convert(reflect.TypeOf(axis.Horizontal), value)
+	if err != nil {
+		return axis.Horizontal, &Error{Line: n.line, Msg: fmt.Sprintf("属性axis: %s", err.Error())}
+	}
+	return v.Interface().(axis.Axis), nil
+}
+
+// 创建不能包含子元素的组件
+func leaf(fn func() widget.WidgetInterface) func(b *builder, n *node) (widget.WidgetInterface, error) {
+	return func(b *builder, n *node) (widget.WidgetInterface, error) {
+		if len(n.children) > 0 {
+			return nil, &Error{Line: n.children[0].line, Msg: fmt.Sprintf("%s不能包含子元素", n.name)}
+		}
+		return fn(), nil
+	}
+}
+
+// 构建唯一的子元素，没有子元素时返回nil
+func (b *builder) singleChild(n *node) (widget.WidgetInterface, error) {
+	switch len(n.children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return b.build(n.children[0])
+	}
+	return nil, &Error{Line: n.children[1].line, Msg: fmt.Sprintf("%s只能包含一个子元素", n.name)}
+}
+
+// 构建弹性布局的子元素
+//
+// 子元素通过weight属性设置权重，没有权重时作为固定大小的子节点添加，通过anchor属性设置锚定方向
+func flexChildren[T any](b *builder, n *node,
+	rigid func(widget.WidgetInterface) T,
+	flex func(float32, widget.WidgetInterface) T,
+	flexAnchor func(float32, anchor.Direction, widget.WidgetInterface) T,
+) error {
+	for _, c := range n.children {
+		weight, hasWeight := float32(1), false
+		if value, ok := c.attr("weight"); ok {
+			v, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return &Error{Line: c.line, Msg: fmt.Sprintf("无效的权重%q", value)}
+			}
+			weight, hasWeight = float32(v), true
+		}
+		direction, hasAnchor := anchor.Center, false
+		if value, ok := c.attr("anchor"); ok {
+			v, err := setter.Convert(reflect.TypeOf(anchor.Center), value)
+			if err != nil {
+				return &Error{Line: c.line, Msg: fmt.Sprintf("属性anchor: %s", err.Error())}
+			}
+			direction, hasAnchor = v.Interface().(anchor.Direction), true
+		}
+		child, err := b.build(c)
+		if err != nil {
+			return err
+		}
+		switch {
+		case hasAnchor:
+			flexAnchor(weight, direction, child)
+		case hasWeight:
+			flex(weight, child)
+		default:
+			rigid(child)
+		}
+	}
+	return nil
+}
+
+// 根据Span元素的属性创建富文本片段，颜色使用16进制字符串、CSS函数或颜色名称
+func buildSpan(n *node) (widget.Span, error) {
+	var span widget.Span
+	value := reflect.ValueOf(&span).Elem()
+	for _, a := range n.attrs {
+		field := value.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, a.name) })
+		if !field.IsValid() {
+			return span, &Error{Line: n.line, Msg: fmt.Sprintf("Span不支持属性%s", a.name)}
+		}
+		if field.Type() == reflect.TypeOf(color.NRGBA{}) {
+			c, err := utils.ParseColor(a.value)
+			if err != nil {
+				return span, &Error{Line: n.line, Msg: fmt.Sprintf("属性%s: %s", a.name, err.Error())}
+			}
+			field.Set(reflect.ValueOf(c.NRGBA()))
+			continue
+		}
+		v, err := setter.Convert(field.Type(), a.value)
+		if err != nil {
+			return span, &Error{Line: n.line, Msg: fmt.Sprintf("属性%s: %s", a.name, err.Error())}
+		}
+		field.Set(v)
+	}
+	return span, nil
+}
@@ -0,0 +1,160 @@
+// 声明式UI加载器
+// 从XML或者JSON描述文件中构建组件树，元素名对应组件名（例如RowLayout、Button），属性对应组件的链式设置函数（例如Margin、FontColor）
+// 只能使用组件注册的属性和事件，Insert、Undo、Focus等操作不能在描述文件中调用
+package loader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 描述文件格式
+type Format uint8
+
+const (
+	// XML格式
+	XML Format = iota
+	// JSON格式
+	JSON
+)
+
+// 事件处理函数注册表，键为描述文件中使用的名字，值为与组件事件函数签名一致的函数
+//
+// 例如：Handlers{"login": func(p *widget.Button) {}} 可以通过 OnClicked="login" 绑定
+type Handlers map[string]any
+
+// 带行号的加载错误
+type Error struct {
+	// 错误所在行号
+	Line int
+	// 错误信息
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("第%d行: %s", e.Line, e.Msg)
+}
+
+// 加载完成的文档
+type Document struct {
+	// 根组件
+	root widget.WidgetInterface
+	// 通过id属性命名的组件
+	ids map[string]widget.WidgetInterface
+}
+
+// 获取根组件
+func (p *Document) GetRoot() widget.WidgetInterface {
+	return p.root
+}
+
+// 通过id获取组件，不存在时返回nil
+func (p *Document) GetByID(id string) widget.WidgetInterface {
+	return p.ids[id]
+}
+
+// 通过id获取指定类型的组件，不存在或者类型不匹配时返回false
+func Find[T widget.WidgetInterface](doc *Document, id string) (T, bool) {
+	w, ok := doc.ids[id].(T)
+	return w, ok
+}
+
+// 组件构建器
+type builder struct {
+	handlers Handlers
+	doc      *Document
+}
+
+// 从读取器中加载描述文件
+func Load(r io.Reader, format Format, handlers Handlers) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var root *node
+	switch format {
+	case XML:
+		root, err = parseXML(data)
+	case JSON:
+		root, err = parseJSON(data)
+	default:
+		return nil, fmt.Errorf("不支持的描述文件格式%d", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{
+		handlers: handlers,
+		doc:      &Document{ids: map[string]widget.WidgetInterface{}},
+	}
+	if b.doc.root, err = b.build(root); err != nil {
+		return nil, err
+	}
+	return b.doc, nil
+}
+
+// 从字符串中加载描述文件
+func LoadString(data string, format Format, handlers Handlers) (*Document, error) {
+	return Load(strings.NewReader(data), format, handlers)
+}
+
+// 从文件中加载描述文件，根据扩展名判断格式
+func LoadFile(path string, handlers Handlers) (*Document, error) {
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		format = XML
+	case ".json":
+		format = JSON
+	default:
+		return nil, fmt.Errorf("无法识别的描述文件扩展名%s", filepath.Ext(path))
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	doc, err := Load(file, format, handlers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// 构建元素对应的组件以及其子组件
+func (b *builder) build(n *node) (widget.WidgetInterface, error) {
+	elem, ok := elements[n.name]
+	if !ok {
+		return nil, &Error{Line: n.line, Msg: fmt.Sprintf("未知的元素%s", n.name)}
+	}
+	w, err := elem.build(b, n)
+	if err != nil {
+		return nil, err
+	}
+	skip := map[string]bool{"id": true, "class": true, "weight": true, "anchor": true}
+	for _, name := range elem.consumes {
+		skip[strings.ToLower(name)] = true
+	}
+	if err := b.apply(w, n, skip); err != nil {
+		return nil, err
+	}
+	if id, ok := n.attr("id"); ok {
+		if _, exists := b.doc.ids[id]; exists {
+			return nil, &Error{Line: n.line, Msg: fmt.Sprintf("重复的id %s", id)}
+		}
+		b.doc.ids[id] = w
+		reflect.ValueOf(w).MethodByName("ID").Call([]reflect.Value{reflect.ValueOf(id)})
+	}
+	// 多个类名使用空格分隔
+	if class, ok := n.attr("class"); ok {
+		classes := reflect.ValueOf(strings.Fields(class))
+		reflect.ValueOf(w).MethodByName("Class").CallSlice([]reflect.Value{classes})
+	}
+	return w, nil
+}
@@ -0,0 +1,74 @@
+package loader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/setter"
+)
+
+// 每个元素都注册了属性，并且注册的属性都是组件上存在的方法
+func TestRegisteredProperties(t *testing.T) {
+	for name := range elements {
+		source := "<" + name + "/>"
+		if name == "Border" {
+			source = "<Border><Label/></Border>"
+		}
+		doc, err := LoadString(source, XML, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		w := doc.GetRoot()
+		props := setter.Properties(w)
+		if len(props) == 0 {
+			t.Errorf("%s: 没有注册属性", name)
+		}
+		for _, prop := range props {
+			if !reflect.ValueOf(w).MethodByName(prop).IsValid() {
+				t.Errorf("%s: 注册的属性%s不存在", name, prop)
+			}
+		}
+	}
+}
+
+func TestLoadAttributes(t *testing.T) {
+	handlers := Handlers{"login": func(p *widget.Button) {}}
+	tests := []struct {
+		source string
+		// 错误信息中包含的文字，为空时表示应该成功
+		err string
+	}{
+		{`<Button text="ok" fontColor="#ff0000" margin="4" onClicked="login"/>`, ""},
+		{`<Editor text="a" hint="b" readOnly="true" maxLength="10"/>`, ""},
+		{`<Label text="a" maxLines="2" alignment="middle"/>`, ""},
+		{`<ListLayout axis="horizontal" scrollWidth="6"/>`, ""},
+		{`<Button onClicked="missing"/>`, "未注册的处理函数"},
+		{`<Button fontColor="#zz"/>`, "属性fontColor"},
+		{`<Button unknown="1"/>`, "不支持属性unknown"},
+		// 操作和组件树函数不能作为属性调用
+		{`<Editor insert="x"/>`, "不支持属性insert"},
+		{`<Editor replaceAll="a,b"/>`, "不支持属性replaceAll"},
+		{`<Editor undo=""/>`, "不支持属性undo"},
+		{`<Editor focus=""/>`, "不支持属性focus"},
+		{`<Editor showSuggestions=""/>`, "不支持属性showSuggestions"},
+		{`<Editor setCaret="1,1"/>`, "不支持属性setCaret"},
+		{`<ListLayout scrollBy="5"/>`, "不支持属性scrollBy"},
+		{`<Button then="x"/>`, "不支持属性then"},
+		{`<Button update="false"/>`, "不支持属性update"},
+		{`<Button getMargin=""/>`, "不支持属性getMargin"},
+		{`<LineChart addSeries="a"/>`, "不支持属性addSeries"},
+	}
+	for _, tt := range tests {
+		_, err := LoadString(tt.source, XML, handlers)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.source, err)
+		case tt.err != "" && err == nil:
+			t.Errorf("%s: 应该返回错误", tt.source)
+		case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+			t.Errorf("%s: 错误%q不包含%q", tt.source, err.Error(), tt.err)
+		}
+	}
+}
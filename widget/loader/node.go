@@ -0,0 +1,241 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 属性
+type attr struct {
+	name  string
+	value string
+}
+
+// 描述文件中的一个元素
+type node struct {
+	// 元素名，对应组件名
+	name string
+	// 属性，保持文档中的顺序
+	attrs []attr
+	// 子元素
+	children []*node
+	// 所在行号
+	line int
+}
+
+// 获取属性值
+func (n *node) attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if strings.EqualFold(a.name, name) {
+			return a.value, true
+		}
+	}
+	return "", false
+}
+
+// 计算偏移量所在的行号
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte{'\n'}) + 1
+}
+
+// 解析XML描述文件
+func parseXML(data []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*node
+	var root *node
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return nil, &Error{Line: syntaxErr.Line, Msg: syntaxErr.Msg}
+			}
+			return nil, &Error{Line: lineAt(data, decoder.InputOffset()), Msg: err.Error()}
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			n := &node{name: token.Name.Local, line: lineAt(data, offset)}
+			for _, a := range token.Attr {
+				n.attrs = append(n.attrs, attr{name: a.Name.Local, value: a.Value})
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			} else {
+				return nil, &Error{Line: n.line, Msg: "只能存在一个根元素"}
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 && len(bytes.TrimSpace(token)) > 0 {
+				return nil, &Error{Line: lineAt(data, offset), Msg: fmt.Sprintf("元素%s中不允许出现文本内容，请使用属性", stack[len(stack)-1].name)}
+			}
+		}
+	}
+	if root == nil {
+		return nil, &Error{Line: 1, Msg: "描述文件中没有任何元素"}
+	}
+	return root, nil
+}
+
+// JSON解析器
+//
+// 元素为一个对象，其中type为元素名，children为子元素数组，其余的键都作为属性
+type jsonParser struct {
+	data    []byte
+	decoder *json.Decoder
+}
+
+// 解析JSON描述文件
+func parseJSON(data []byte) (*node, error) {
+	parser := &jsonParser{data: data, decoder: json.NewDecoder(bytes.NewReader(data))}
+	parser.decoder.UseNumber()
+	root, err := parser.element()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parser.decoder.Token(); err != io.EOF {
+		return nil, parser.errorf("只能存在一个根元素")
+	}
+	return root, nil
+}
+
+// 当前位置的错误
+func (p *jsonParser) errorf(format string, args ...any) error {
+	return &Error{Line: lineAt(p.data, p.decoder.InputOffset()), Msg: fmt.Sprintf(format, args...)}
+}
+
+// 读取下一个token，并将语法错误转换为带行号的错误
+func (p *jsonParser) token() (json.Token, error) {
+	token, err := p.decoder.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &Error{Line: lineAt(p.data, syntaxErr.Offset), Msg: syntaxErr.Error()}
+		}
+		if err == io.EOF {
+			return nil, p.errorf("文件意外结束")
+		}
+		return nil, p.errorf("%s", err.Error())
+	}
+	return token, nil
+}
+
+// 解析一个元素对象
+func (p *jsonParser) element() (*node, error) {
+	token, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, p.errorf("元素必须是对象")
+	}
+	n := &node{line: lineAt(p.data, p.decoder.InputOffset())}
+	for p.decoder.More() {
+		token, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		switch key {
+		case "type":
+			value, err := p.token()
+			if err != nil {
+				return nil, err
+			}
+			name, ok := value.(string)
+			if !ok {
+				return nil, p.errorf("type必须是字符串")
+			}
+			n.name = name
+		case "children":
+			if token, err = p.token(); err != nil {
+				return nil, err
+			}
+			if token != json.Delim('[') {
+				return nil, p.errorf("children必须是数组")
+			}
+			for p.decoder.More() {
+				child, err := p.element()
+				if err != nil {
+					return nil, err
+				}
+				n.children = append(n.children, child)
+			}
+			if _, err = p.token(); err != nil {
+				return nil, err
+			}
+		default:
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.attrs = append(n.attrs, attr{name: key, value: value})
+		}
+	}
+	if _, err = p.token(); err != nil {
+		return nil, err
+	}
+	if n.name == "" {
+		return nil, &Error{Line: n.line, Msg: "元素缺少type"}
+	}
+	return n, nil
+}
+
+// 解析属性值，数组会被转换为逗号分隔的字符串
+func (p *jsonParser) value() (string, error) {
+	token, err := p.token()
+	if err != nil {
+		return "", err
+	}
+	if token == json.Delim('[') {
+		var values []string
+		for p.decoder.More() {
+			if token, err = p.token(); err != nil {
+				return "", err
+			}
+			value, err := scalar(token)
+			if err != nil {
+				return "", p.errorf("%s", err.Error())
+			}
+			values = append(values, value)
+		}
+		if _, err = p.token(); err != nil {
+			return "", err
+		}
+		return strings.Join(values, ","), nil
+	}
+	value, err := scalar(token)
+	if err != nil {
+		return "", p.errorf("%s", err.Error())
+	}
+	return value, nil
+}
+
+// 将JSON标量转换为字符串
+func scalar(token json.Token) (string, error) {
+	switch value := token.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return fmt.Sprint(value), nil
+	}
+	return "", errors.New("属性值只能是字符串、数字、布尔值或者它们的数组")
+}
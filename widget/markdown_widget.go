@@ -0,0 +1,668 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seikaijyu/nenki.ui/widget/axis"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	"github.com/Seikaijyu/gio/f32"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+	"github.com/pkg/browser"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	gmtext "github.com/yuin/goldmark/text"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &Markdown{}
+
+// Markdown解析器，支持CommonMark以及GFM的表格和删除线
+var markdownParser = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough))
+
+// 标题相对于正文的大小
+var markdownHeadingScale = [...]float32{2, 1.5, 1.25, 1.1, 1, 0.9}
+
+type markdownConfig struct {
+	// 主题状态
+	themed themed
+	// 正文字体大小
+	size float32
+	// 代码使用的字体族
+	codeFamily string
+	// 文本是否可以选择
+	selectable bool
+	// 图片加载函数
+	loader func(src string) (image.Image, error)
+	// 是否需要重新生成组件
+	dirty bool
+	// 是否更新组件
+	update bool
+	// 链接点击事件，为nil时使用系统浏览器打开链接
+	_linkClicked func(*Markdown, string)
+	// 删除事件
+	_destroy func()
+}
+
+// Markdown查看器，将Markdown文档渲染为组件并放在可以滚动的列表中
+type Markdown struct {
+	// 组件标识
+	identity
+	// 配置
+	config *markdownConfig
+	// 文档内容
+	source string
+	// 存放所有块的列表
+	list *ListLayout
+}
+
+// 绑定函数
+func (p *Markdown) Then(fn func(self *Markdown)) *Markdown {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *Markdown) ID(id string) *Markdown {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Markdown) Class(classes ...string) *Markdown {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Markdown) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *Markdown) Destroy() {
+	p.config.update = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *Markdown) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 是否更新组件
+func (p *Markdown) Update(update bool) {
+	p.config.update = update
+}
+
+// 外边距
+func (p *Markdown) Margin(Top, Left, Bottom, Right float32) *Markdown {
+	p.list.Margin(Top, Left, Bottom, Right)
+	return p
+}
+
+// 获取外边距
+func (p *Markdown) GetMargin() (top, left, bottom, right float32) {
+	return p.list.GetMargin()
+}
+
+// 设置文档内容
+func (p *Markdown) Source(source string) *Markdown {
+	p.source = source
+	p.config.dirty = true
+	return p
+}
+
+// 获取文档内容
+func (p *Markdown) GetSource() string {
+	return p.source
+}
+
+// 设置正文字体大小，标题按比例放大
+func (p *Markdown) FontSize(size float32) *Markdown {
+	p.config.size = size
+	p.config.themed.overrides.set(overrideFontSize)
+	p.config.dirty = true
+	return p
+}
+
+// 获取字体大小
+func (p *Markdown) GetFontSize() float32 {
+	return p.config.size
+}
+
+// 设置代码使用的字体族，默认为系统的等宽字体
+func (p *Markdown) CodeFontFamily(name string) *Markdown {
+	p.config.codeFamily = name
+	p.config.dirty = true
+	return p
+}
+
+// 设置文本是否可以选择
+func (p *Markdown) Selectable(selectable bool) *Markdown {
+	p.config.selectable = selectable
+	p.config.dirty = true
+	return p
+}
+
+// 设置图片加载函数，src为图片的地址，函数在单独的协程中调用
+//
+// 没有设置时图片显示为替代文本
+func (p *Markdown) ImageLoader(fn func(src string) (image.Image, error)) *Markdown {
+	p.config.loader = fn
+	p.config.dirty = true
+	return p
+}
+
+// 链接点击事件，设置后不再使用系统浏览器打开链接，所有链接都会交给事件处理
+func (p *Markdown) OnLinkClicked(fn func(p *Markdown, link string)) *Markdown {
+	p.config._linkClicked = fn
+	return p
+}
+
+// 滚动到顶部
+func (p *Markdown) ScrollToTop() *Markdown {
+	p.list.ScrollToItem(0)
+	return p
+}
+
+// 渲染
+func (p *Markdown) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 主题改变时重新生成组件，使代码块和引用的颜色跟随主题
+	if th, ok := p.config.themed.changed(); ok {
+		if !p.config.themed.overrides.has(overrideFontSize) {
+			p.config.size = th.Body
+		}
+		p.config.dirty = true
+	}
+	if p.config.dirty {
+		p.config.dirty = false
+		p.build()
+	}
+	return p.list.Layout(gtx)
+}
+
+// 解析文档并重新生成所有块
+func (p *Markdown) build() {
+	source := []byte(p.source)
+	doc := markdownParser.Parser().Parse(gmtext.NewReader(source))
+	b := &markdownBuilder{markdown: p, source: source, theme: theme.Current()}
+	p.list.RemoveChildAll()
+	for _, block := range b.blocks(doc) {
+		p.list.AppendChild(block)
+	}
+}
+
+// 链接点击时调用
+//
+// 没有设置链接点击事件时只用系统浏览器打开http、https和mailto链接，
+// 其他链接（例如file:或者相对路径）可能执行本地程序，直接忽略
+func (p *Markdown) openLink(link string) {
+	if p.config._linkClicked != nil {
+		p.config._linkClicked(p, link)
+		return
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		go browser.OpenURL(u.String())
+	}
+}
+
+// 创建Markdown查看器
+func NewMarkdown(source string) *Markdown {
+	markdown := &Markdown{
+		source: source,
+		list:   NewListLayout(axis.Vertical),
+		config: &markdownConfig{
+			update: true,
+			dirty:  true,
+			size:   theme.Current().Body,
+			themed: newThemed(),
+		},
+	}
+	markdown.list.SetParent(markdown)
+	return markdown
+}
+
+// 将Markdown语法树转换为组件
+type markdownBuilder struct {
+	markdown *Markdown
+	source   []byte
+	theme    *theme.Theme
+}
+
+// 行内样式
+type markdownInline struct {
+	bold, italic, code, strike bool
+	link                       string
+}
+
+// 转换所有子块
+func (b *markdownBuilder) blocks(parent ast.Node) []WidgetInterface {
+	var blocks []WidgetInterface
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		blocks = append(blocks, b.block(n)...)
+	}
+	return blocks
+}
+
+// 转换一个块，段落中包含图片时会拆分为多个块
+func (b *markdownBuilder) block(n ast.Node) []WidgetInterface {
+	size := b.markdown.config.size
+	space := size * 0.5
+	switch n := n.(type) {
+	case *ast.Heading:
+		scale := markdownHeadingScale[len(markdownHeadingScale)-1]
+		if n.Level <= len(markdownHeadingScale) {
+			scale = markdownHeadingScale[n.Level-1]
+		}
+		spans := b.inlines(n, markdownInline{bold: true}, nil)
+		for i := range spans {
+			spans[i].Size = size * scale
+		}
+		return []WidgetInterface{b.richText(spans).Margin(space, 0, space, 0)}
+	case *ast.Paragraph, *ast.TextBlock:
+		var blocks []WidgetInterface
+		var images []*ast.Image
+		spans := b.inlines(n, markdownInline{}, &images)
+		// 图片单独成块，放在段落文本之后
+		if len(spans) > 0 {
+			blocks = append(blocks, b.richText(spans).Margin(0, 0, space, 0))
+		}
+		for _, img := range images {
+			blocks = append(blocks, b.image(img))
+		}
+		return blocks
+	case *ast.ThematicBreak:
+		return []WidgetInterface{newMarkdownBox(nil).line(withAlpha(b.theme.Text, 0x40)).margin(space, 0, space, 0)}
+	case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock:
+		code := strings.TrimSuffix(b.lines(n), "\n")
+		rich := b.richText([]Span{{Text: code, Family: b.codeFamily(), Size: size * 0.9}}).WrapPolicy(text.WrapGraphemes)
+		return []WidgetInterface{newMarkdownBox(rich).background(withAlpha(b.theme.Text, 0x14)).
+			inset(size*0.5, size*0.75, size*0.5, size*0.75).margin(0, 0, space, 0)}
+	case *ast.Blockquote:
+		column := NewColumnLayout()
+		for _, child := range b.blocks(n) {
+			column.AppendRigidChild(child)
+		}
+		return []WidgetInterface{newMarkdownBox(column).bar(withAlpha(b.theme.Text, 0x50)).
+			inset(0, size, 0, 0).margin(0, 0, space, 0)}
+	case *ast.List:
+		column := NewColumnLayout()
+		index := n.Start
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "•"
+			if n.IsOrdered() {
+				marker = fmt.Sprintf("%d.", index)
+				index++
+			}
+			content := NewColumnLayout()
+			for _, child := range b.blocks(item) {
+				content.AppendRigidChild(child)
+			}
+			row := NewRowLayout().
+				AppendRigidChild(b.richText([]Span{{Text: marker}}).Margin(0, size*0.5, 0, size*0.5)).
+				AppendFlexChild(1, content)
+			column.AppendRigidChild(row)
+		}
+		return []WidgetInterface{column}
+	case *extast.Table:
+		return []WidgetInterface{b.table(n)}
+	}
+	// 不支持的块作为普通文本显示
+	if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+		return []WidgetInterface{b.richText([]Span{{Text: b.lines(n)}}).Margin(0, 0, space, 0)}
+	}
+	return b.blocks(n)
+}
+
+// 转换表格，每一列宽度相同
+func (b *markdownBuilder) table(n *extast.Table) WidgetInterface {
+	size := b.markdown.config.size
+	column := NewColumnLayout()
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		_, header := row.(*extast.TableHeader)
+		line := NewRowLayout()
+		i := 0
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			rich := b.richText(b.inlines(cell, markdownInline{bold: header}, nil))
+			if i < len(n.Alignments) {
+				switch n.Alignments[i] {
+				case extast.AlignCenter:
+					rich.Alignment(text.Middle)
+				case extast.AlignRight:
+					rich.Alignment(text.End)
+				}
+			}
+			box := newMarkdownBox(rich).border(withAlpha(b.theme.Text, 0x40)).inset(size*0.25, size*0.5, size*0.25, size*0.5)
+			if header {
+				box.background(withAlpha(b.theme.Text, 0x0c))
+			}
+			line.AppendFlexChild(1, box)
+			i++
+		}
+		column.AppendRigidChild(line)
+	}
+	return newMarkdownBox(column).margin(0, 0, size*0.5, 0)
+}
+
+// 转换行内元素为富文本片段，images不为nil时收集段落中的图片
+func (b *markdownBuilder) inlines(parent ast.Node, style markdownInline, images *[]*ast.Image) []Span {
+	var spans []Span
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		switch n := n.(type) {
+		case *ast.Text:
+			value := string(n.Segment.Value(b.source))
+			if n.HardLineBreak() {
+				value += "\n"
+			} else if n.SoftLineBreak() {
+				value += " "
+			}
+			spans = append(spans, b.span(value, style))
+		case *ast.String:
+			spans = append(spans, b.span(string(n.Value), style))
+		case *ast.CodeSpan:
+			inner := style
+			inner.code = true
+			spans = append(spans, b.inlines(n, inner, images)...)
+		case *ast.Emphasis:
+			inner := style
+			if n.Level >= 2 {
+				inner.bold = true
+			} else {
+				inner.italic = true
+			}
+			spans = append(spans, b.inlines(n, inner, images)...)
+		case *extast.Strikethrough:
+			inner := style
+			inner.strike = true
+			spans = append(spans, b.inlines(n, inner, images)...)
+		case *ast.Link:
+			inner := style
+			inner.link = string(n.Destination)
+			spans = append(spans, b.inlines(n, inner, images)...)
+		case *ast.AutoLink:
+			inner := style
+			inner.link = string(n.URL(b.source))
+			if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(inner.link, "mailto:") {
+				inner.link = "mailto:" + inner.link
+			}
+			spans = append(spans, b.span(string(n.Label(b.source)), inner))
+		case *ast.Image:
+			if images != nil {
+				*images = append(*images, n)
+				continue
+			}
+			// 不能显示图片的位置使用替代文本
+			spans = append(spans, b.inlines(n, style, nil)...)
+		case *ast.RawHTML:
+			var builder strings.Builder
+			for i := 0; i < n.Segments.Len(); i++ {
+				segment := n.Segments.At(i)
+				builder.Write(segment.Value(b.source))
+			}
+			spans = append(spans, b.span(builder.String(), style))
+		default:
+			spans = append(spans, b.inlines(n, style, images)...)
+		}
+	}
+	return spans
+}
+
+// 根据行内样式创建富文本片段
+func (b *markdownBuilder) span(value string, style markdownInline) Span {
+	span := Span{Text: value, Italic: style.italic, Link: style.link}
+	if style.bold {
+		span.Weight = text.Bold
+	}
+	if style.strike {
+		span.Decoration = text.Strikethrough
+	}
+	if style.code {
+		span.Family = b.codeFamily()
+		span.Size = b.markdown.config.size * 0.9
+		span.Background = withAlpha(b.theme.Text, 0x14)
+	}
+	return span
+}
+
+// 创建段落使用的富文本
+func (b *markdownBuilder) richText(spans []Span) *RichText {
+	markdown := b.markdown
+	return NewRichText(spans...).
+		FontSize(markdown.config.size).
+		Selectable(markdown.config.selectable).
+		OnLinkClicked(func(p *RichText, link string) {
+			markdown.openLink(link)
+		})
+}
+
+// 创建图片块
+func (b *markdownBuilder) image(n *ast.Image) WidgetInterface {
+	var alt strings.Builder
+	for _, span := range b.inlines(n, markdownInline{}, nil) {
+		alt.WriteString(span.Text)
+	}
+	block := &markdownImage{
+		update: true,
+		alt:    b.richText([]Span{{Text: alt.String(), Italic: true, Color: b.theme.TextSecondary}}),
+	}
+	if loader := b.markdown.config.loader; loader != nil {
+		block.loading = true
+		src := string(n.Destination)
+		go func() {
+			img, err := loader(src)
+			block.mutex.Lock()
+			defer block.mutex.Unlock()
+			block.loading = false
+			if err == nil && img != nil {
+				block.img = paint.NewImageOp(img)
+				block.size = img.Bounds().Size()
+			}
+		}()
+	}
+	return newMarkdownBox(block).margin(0, 0, b.markdown.config.size*0.5, 0)
+}
+
+// 块的原始文本
+func (b *markdownBuilder) lines(n ast.Node) string {
+	var builder strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		builder.Write(segment.Value(b.source))
+	}
+	return builder.String()
+}
+
+// 代码使用的字体族
+func (b *markdownBuilder) codeFamily() string {
+	if b.markdown.config.codeFamily != "" {
+		return b.markdown.config.codeFamily
+	}
+	return "monospace"
+}
+
+// Markdown中的容器块，可以绘制背景、边框、左侧竖线或者分割线
+type markdownBox struct {
+	// 组件标识
+	identity
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 子组件，分割线没有子组件
+	child WidgetInterface
+	// 外边距和内边距
+	outer, inner glayout.Inset
+	// 背景、边框、左侧竖线和分割线的颜色，透明时不绘制
+	fill, stroke, side, rule color.NRGBA
+}
+
+func newMarkdownBox(child WidgetInterface) *markdownBox {
+	box := &markdownBox{update: true, child: child}
+	if child != nil {
+		setParent(child, box)
+	}
+	return box
+}
+
+func (p *markdownBox) margin(top, left, bottom, right float32) *markdownBox {
+	p.outer = glayout.Inset{Top: gunit.Dp(top), Left: gunit.Dp(left), Bottom: gunit.Dp(bottom), Right: gunit.Dp(right)}
+	return p
+}
+
+func (p *markdownBox) inset(top, left, bottom, right float32) *markdownBox {
+	p.inner = glayout.Inset{Top: gunit.Dp(top), Left: gunit.Dp(left), Bottom: gunit.Dp(bottom), Right: gunit.Dp(right)}
+	return p
+}
+
+func (p *markdownBox) background(c color.NRGBA) *markdownBox {
+	p.fill = c
+	return p
+}
+
+func (p *markdownBox) border(c color.NRGBA) *markdownBox {
+	p.stroke = c
+	return p
+}
+
+func (p *markdownBox) bar(c color.NRGBA) *markdownBox {
+	p.side = c
+	return p
+}
+
+func (p *markdownBox) line(c color.NRGBA) *markdownBox {
+	p.rule = c
+	return p
+}
+
+func (p *markdownBox) OnDestroy(fn func()) {
+	p._destroy = fn
+}
+
+func (p *markdownBox) Destroy() {
+	p.update = false
+	if p._destroy != nil {
+		p._destroy()
+	}
+	p._destroy = nil
+}
+
+func (p *markdownBox) Update(update bool) {
+	p.update = update
+}
+
+// 宽度总是填满父组件，使背景和边框对齐
+func (p *markdownBox) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.update {
+		return glayout.Dimensions{}
+	}
+	return p.outer.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		width := gtx.Constraints.Max.X
+		if p.child == nil {
+			height := gtx.Dp(1)
+			paint.FillShape(gtx.Ops, p.rule, clip.Rect{Max: image.Pt(width, height)}.Op())
+			return glayout.Dimensions{Size: image.Pt(width, height)}
+		}
+		macro := op.Record(gtx.Ops)
+		gtx.Constraints.Min.X = 0
+		dims := p.inner.Layout(gtx, p.child.Layout)
+		call := macro.Stop()
+		size := image.Pt(width, dims.Size.Y)
+		if p.fill.A > 0 {
+			paint.FillShape(gtx.Ops, p.fill, clip.Rect{Max: size}.Op())
+		}
+		call.Add(gtx.Ops)
+		if p.side.A > 0 {
+			paint.FillShape(gtx.Ops, p.side, clip.Rect{Max: image.Pt(gtx.Dp(3), size.Y)}.Op())
+		}
+		if p.stroke.A > 0 {
+			paint.FillShape(gtx.Ops, p.stroke, clip.Stroke{Path: clip.Rect{Max: size}.Path(), Width: 1}.Op())
+		}
+		return glayout.Dimensions{Size: size, Baseline: dims.Baseline}
+	})
+}
+
+// Markdown中的图片，加载完成前和加载失败时显示替代文本
+type markdownImage struct {
+	// 组件标识
+	identity
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 替代文本
+	alt *RichText
+	// 图片，在加载协程中写入
+	mutex   sync.Mutex
+	loading bool
+	img     paint.ImageOp
+	size    image.Point
+}
+
+func (p *markdownImage) OnDestroy(fn func()) {
+	p._destroy = fn
+}
+
+func (p *markdownImage) Destroy() {
+	p.update = false
+	if p._destroy != nil {
+		p._destroy()
+	}
+	p._destroy = nil
+}
+
+func (p *markdownImage) Update(update bool) {
+	p.update = update
+}
+
+// 图片宽度超出时按比例缩小
+func (p *markdownImage) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.update {
+		return glayout.Dimensions{}
+	}
+	p.mutex.Lock()
+	loading, img, size := p.loading, p.img, p.size
+	p.mutex.Unlock()
+	if loading {
+		// 加载完成前定时重新渲染
+		op.InvalidateOp{At: gtx.Now.Add(100 * time.Millisecond)}.Add(gtx.Ops)
+	}
+	if size.X == 0 || size.Y == 0 {
+		return p.alt.Layout(gtx)
+	}
+	scale := float32(1)
+	if size.X > gtx.Constraints.Max.X {
+		scale = float32(gtx.Constraints.Max.X) / float32(size.X)
+	}
+	dst := image.Pt(int(float32(size.X)*scale), int(float32(size.Y)*scale))
+	defer clip.Rect{Max: dst}.Push(gtx.Ops).Pop()
+	defer op.Affine(f32.Affine2D{}.Scale(f32.Point{}, f32.Pt(scale, scale))).Push(gtx.Ops).Pop()
+	img.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	return glayout.Dimensions{Size: dst}
+}
@@ -0,0 +1,454 @@
+package widget
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Seikaijyu/gio/io/key"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op/clip"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &NumberInput{}
+
+// 小数点使用逗号的语言
+var commaDecimalLanguages = map[string]bool{
+	"af": true, "az": true, "be": true, "bg": true, "bs": true, "ca": true, "cs": true, "da": true,
+	"de": true, "el": true, "es": true, "et": true, "eu": true, "fi": true, "fr": true, "gl": true,
+	"hr": true, "hu": true, "hy": true, "id": true, "is": true, "it": true, "ka": true, "kk": true,
+	"ky": true, "lt": true, "lv": true, "mk": true, "nb": true, "nl": true, "nn": true, "no": true,
+	"pl": true, "pt": true, "ro": true, "ru": true, "sk": true, "sl": true, "sq": true, "sr": true,
+	"sv": true, "tr": true, "uk": true, "uz": true, "vi": true,
+}
+
+type numberInputConfig struct {
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 值改变事件
+	_valueChanged func(*NumberInput, float64)
+	// 是否禁用
+	disabled bool
+	// 当前值
+	value float64
+	// 最小值和最大值
+	min, max float64
+	// 步长
+	step float64
+	// 小数位数
+	precision int
+	// 小数点，为0时根据系统语言决定
+	decimal rune
+	// 系统语言对应的小数点
+	localeDecimal rune
+	// 上一帧编辑框是否有焦点，用于在失去焦点时修正输入
+	focused bool
+}
+
+// 数字输入框，由编辑框和增减按钮组成，支持范围、步长和小数位数
+//
+// 获得焦点时可以使用上下方向键、PageUp、PageDown和鼠标滚轮调整数值，失去焦点时修正到范围内
+type NumberInput struct {
+	// 组件标识
+	identity
+	// 配置
+	config *numberInputConfig
+	// 外边距
+	margin *glayout.Inset
+	// 编辑框
+	editor *Editor
+	// 减少和增加按钮
+	decrement, increment *Button
+}
+
+// 绑定函数
+func (p *NumberInput) Then(fn func(self *NumberInput)) *NumberInput {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *NumberInput) ID(id string) *NumberInput {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *NumberInput) Class(classes ...string) *NumberInput {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 注销自身，清理所有引用
+func (p *NumberInput) Destroy() {
+	p.config.update = false
+	p.editor.Destroy()
+	p.decrement.Destroy()
+	p.increment.Destroy()
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *NumberInput) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 是否更新组件
+func (p *NumberInput) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不响应任何输入
+func (p *NumberInput) Disabled(disabled bool) *NumberInput {
+	p.config.disabled = disabled
+	p.editor.Disabled(disabled)
+	p.decrement.Disabled(disabled)
+	p.increment.Disabled(disabled)
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *NumberInput) GetState() State {
+	return p.editor.GetState()
+}
+
+// 外边距
+func (p *NumberInput) Margin(Top, Left, Bottom, Right float32) *NumberInput {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *NumberInput) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 获取内部的编辑框
+func (p *NumberInput) GetEditor() *Editor {
+	return p.editor
+}
+
+// 设置数值，超出范围时修正到范围内
+func (p *NumberInput) Value(value float64) *NumberInput {
+	p.setValue(value)
+	p.format()
+	return p
+}
+
+// 获取数值
+func (p *NumberInput) GetValue() float64 {
+	return p.config.value
+}
+
+// 设置最小值，默认没有限制
+func (p *NumberInput) Min(min float64) *NumberInput {
+	p.config.min = min
+	return p.Value(p.config.value)
+}
+
+// 设置最大值，默认没有限制
+func (p *NumberInput) Max(max float64) *NumberInput {
+	p.config.max = max
+	return p.Value(p.config.value)
+}
+
+// 设置范围
+func (p *NumberInput) Range(min, max float64) *NumberInput {
+	p.config.min, p.config.max = min, max
+	return p.Value(p.config.value)
+}
+
+// 设置步长，默认为1
+func (p *NumberInput) Step(step float64) *NumberInput {
+	p.config.step = math.Abs(step)
+	return p
+}
+
+// 设置小数位数，默认为0
+func (p *NumberInput) Precision(precision int) *NumberInput {
+	p.config.precision = maxInt(precision, 0)
+	return p.Value(p.config.value)
+}
+
+// 设置小数点，默认根据系统语言使用点号或逗号
+func (p *NumberInput) DecimalSeparator(separator rune) *NumberInput {
+	p.config.decimal = separator
+	p.format()
+	return p
+}
+
+// 增加一个步长
+func (p *NumberInput) Increment() *NumberInput {
+	return p.stepBy(1)
+}
+
+// 减少一个步长
+func (p *NumberInput) Decrement() *NumberInput {
+	return p.stepBy(-1)
+}
+
+// 值改变事件
+func (p *NumberInput) OnValueChanged(fn func(p *NumberInput, value float64)) *NumberInput {
+	p.config._valueChanged = fn
+	return p
+}
+
+// 渲染UI
+func (p *NumberInput) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	if decimal := localeDecimal(gtx.Locale.Language); decimal != p.config.localeDecimal {
+		p.config.localeDecimal = decimal
+		p.format()
+	}
+	// 失去焦点时修正输入
+	focused := p.editor.editorMaterial.Editor.Focused()
+	if p.config.focused && !focused {
+		p.commit()
+	}
+	p.config.focused = focused
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		return glayout.Flex{Alignment: glayout.Middle}.Layout(gtx,
+			glayout.Flexed(1, func(gtx glayout.Context) glayout.Dimensions {
+				return p.layoutEditor(gtx)
+			}),
+			glayout.Rigid(p.decrement.Layout),
+			glayout.Rigid(p.increment.Layout),
+		)
+	})
+}
+
+// 渲染编辑框，获得焦点时处理方向键和滚轮
+func (p *NumberInput) layoutEditor(gtx glayout.Context) glayout.Dimensions {
+	var keys []key.Event
+	gtx = interceptKeys(gtx, func(e key.Event) bool {
+		if !numberStepKey(e) {
+			return false
+		}
+		keys = append(keys, e)
+		return true
+	})
+	dims := p.editor.Layout(gtx)
+	// 编辑框不处理的方向键会交给包裹编辑框的区域
+	defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+	if p.config.focused {
+		key.InputOp{Tag: p, Keys: "[↑,↓,⇞,⇟]"}.Add(gtx.Ops)
+		pointer.InputOp{Tag: p, Kinds: pointer.Scroll, ScrollBounds: image.Rect(0, -1, 0, 1)}.Add(gtx.Ops)
+	}
+	for _, e := range gtx.Events(p) {
+		switch e := e.(type) {
+		case key.Event:
+			if numberStepKey(e) {
+				keys = append(keys, e)
+			}
+		case pointer.Event:
+			if e.Kind == pointer.Scroll && e.Scroll.Y != 0 {
+				// 向上滚动增加数值
+				p.stepBy(-sign(e.Scroll.Y))
+			}
+		}
+	}
+	for _, e := range keys {
+		if e.State != key.Press {
+			continue
+		}
+		switch e.Name {
+		case key.NameUpArrow:
+			p.stepBy(1)
+		case key.NameDownArrow:
+			p.stepBy(-1)
+		case key.NamePageUp:
+			p.stepBy(10)
+		case key.NamePageDown:
+			p.stepBy(-10)
+		}
+	}
+	return dims
+}
+
+// 按步长调整数值，以编辑框中正在输入的数值为准
+func (p *NumberInput) stepBy(steps float64) *NumberInput {
+	if value, ok := p.parse(p.editor.GetText()); ok {
+		p.config.value = value
+	}
+	p.setValue(p.config.value + steps*p.config.step)
+	p.format()
+	return p
+}
+
+// 修正编辑框中的输入，无法解析时恢复为当前值
+func (p *NumberInput) commit() {
+	if value, ok := p.parse(p.editor.GetText()); ok {
+		p.setValue(value)
+	}
+	p.format()
+}
+
+// 修正并设置数值，数值改变时触发事件
+func (p *NumberInput) setValue(value float64) {
+	value = p.clamp(value)
+	if value == p.config.value {
+		return
+	}
+	p.config.value = value
+	if p.config._valueChanged != nil {
+		p.config._valueChanged(p, value)
+	}
+}
+
+// 将数值修正到范围内并按小数位数舍入
+func (p *NumberInput) clamp(value float64) float64 {
+	scale := math.Pow10(p.config.precision)
+	value = math.Round(value*scale) / scale
+	return math.Max(p.config.min, math.Min(p.config.max, value))
+}
+
+// 将数值显示到编辑框中
+func (p *NumberInput) format() {
+	text := strconv.FormatFloat(p.config.value, 'f', p.config.precision, 64)
+	text = strings.Replace(text, ".", string(p.decimal()), 1)
+	if text != p.editor.GetText() {
+		p.editor.Text(text)
+		p.editor.SetCaret(p.editor.GetTextLen(), p.editor.GetTextLen())
+	}
+}
+
+// 解析输入的数值，忽略首尾的空白
+//
+// 整数部分可以使用千位分隔符，分隔符只能出现在每三位数字之间并且使用同一种，例如1,234,567；
+// 位置不正确时无法解析，例如小数点为.时1,5不会被当作15
+func (p *NumberInput) parse(text string) (float64, bool) {
+	decimal := p.decimal()
+	text = strings.TrimFunc(text, unicode.IsSpace)
+	sign := ""
+	if strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+		sign, text = text[:1], text[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(text, string(decimal))
+	digits, ok := ungroupDigits(integer, decimal)
+	if !ok || digits == "" && fraction == "" || strings.IndexFunc(fraction, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return 0, false
+	}
+	number := sign + digits
+	if hasFraction {
+		number += "." + fraction
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// 去掉整数部分的千位分隔符，分隔符位置不正确或者有其他字符时返回false
+func ungroupDigits(text string, decimal rune) (string, bool) {
+	var b strings.Builder
+	var separator rune
+	// 上一个分隔符之后的数字数量
+	group := 0
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			group++
+		case r != decimal && strings.ContainsRune(".,' \u00a0\u202f", r):
+			// 第一组为1到3位，之后每组都是3位，并且使用同一种分隔符
+			if group == 0 || separator == 0 && group > 3 || separator != 0 && (group != 3 || r != separator) {
+				return "", false
+			}
+			separator, group = r, 0
+		default:
+			return "", false
+		}
+	}
+	if separator != 0 && group != 3 {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// 获取小数点
+func (p *NumberInput) decimal() rune {
+	if p.config.decimal != 0 {
+		return p.config.decimal
+	}
+	if p.config.localeDecimal != 0 {
+		return p.config.localeDecimal
+	}
+	return '.'
+}
+
+// 根据BCP-47语言标签获取小数点
+func localeDecimal(language string) rune {
+	lang, _, _ := strings.Cut(strings.ToLower(language), "-")
+	if commaDecimalLanguages[lang] {
+		return ','
+	}
+	return '.'
+}
+
+// 是否为调整数值的按键
+func numberStepKey(e key.Event) bool {
+	if e.Modifiers != 0 {
+		return false
+	}
+	switch e.Name {
+	case key.NameUpArrow, key.NameDownArrow, key.NamePageUp, key.NamePageDown:
+		return true
+	}
+	return false
+}
+
+// 获取符号
+func sign(v float32) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+// 创建数字输入框
+func NewNumberInput() *NumberInput {
+	p := &NumberInput{
+		config: &numberInputConfig{update: true, min: math.Inf(-1), max: math.Inf(1), step: 1},
+		margin: &glayout.Inset{},
+	}
+	p.editor = NewEditor("").SingleLine(true).Submit(true).AllowOnly("0123456789+-.,' \u00a0\u202f").
+		OnChange(func(_ *Editor, text string) {
+			// 输入的数值在范围内时立即生效，超出范围的在失去焦点时修正
+			if value, ok := p.parse(text); ok && value == p.clamp(value) {
+				p.setValue(value)
+			}
+		}).
+		OnSubmit(func(_ *Editor, _ string) {
+			p.commit()
+		})
+	p.decrement = NewButton("-").Padding(4, 10, 4, 10).Margin(0, 4, 0, 0).OnClicked(func(_ *Button) {
+		p.Decrement()
+	})
+	p.increment = NewButton("+").Padding(4, 10, 4, 10).Margin(0, 4, 0, 0).OnClicked(func(_ *Button) {
+		p.Increment()
+	})
+	p.editor.SetParent(p)
+	p.decrement.SetParent(p)
+	p.increment.SetParent(p)
+	p.format()
+	return p
+}
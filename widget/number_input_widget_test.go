@@ -0,0 +1,45 @@
+package widget
+
+import "testing"
+
+func TestNumberInputParse(t *testing.T) {
+	tests := []struct {
+		decimal rune
+		text    string
+		value   float64
+		ok      bool
+	}{
+		{'.', "12", 12, true},
+		{'.', " -1.5 ", -1.5, true},
+		{'.', "+3", 3, true},
+		{'.', ".5", 0.5, true},
+		{'.', "5.", 5, true},
+		{'.', "1,234", 1234, true},
+		{'.', "1,234,567.25", 1234567.25, true},
+		{'.', "1'234", 1234, true},
+		{'.', "1 234", 1234, true},
+		{',', "1.234,5", 1234.5, true},
+		{',', "1 234,5", 1234.5, true},
+		{',', "1,5", 1.5, true},
+		// 分隔符的位置不正确时不能解析
+		{'.', "1,5", 0, false},
+		{'.', "12,34", 0, false},
+		{'.', "1234,567", 0, false},
+		{'.', ",123", 0, false},
+		{'.', "1,,234", 0, false},
+		{'.', "1,234 567", 0, false},
+		{'.', "1.2,345", 0, false},
+		{',', "1.5", 0, false},
+		{'.', "", 0, false},
+		{'.', "-", 0, false},
+		{'.', "+-1", 0, false},
+		{'.', "1.2.3", 0, false},
+	}
+	for _, tt := range tests {
+		p := NewNumberInput().DecimalSeparator(tt.decimal)
+		value, ok := p.parse(tt.text)
+		if ok != tt.ok || ok && value != tt.value {
+			t.Errorf("parse(%q, %q) = %v, %v, want %v, %v", tt.text, tt.decimal, value, ok, tt.value, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package widget
+
+import (
+	"image"
+	"strconv"
+	"time"
+
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+)
+
+// 日历的格子数量，6行7列
+const calendarCells = 42
+
+// 日历视图，显示一个月的日期，由DatePicker和DateTimePicker共用
+type calendarView struct {
+	// 显示的年份和月份
+	year  int
+	month time.Month
+	// 日期使用的时区
+	location *time.Location
+	// 可以选择的最早和最晚日期，为零值时不限制，只比较日期部分
+	min, max time.Time
+	// 判断日期是否禁用
+	disabled func(date time.Time) bool
+	// 一周的第一天，为负数时使用语言的默认值
+	firstDay time.Weekday
+	// 上一年、上一月、下一月和下一年的点击
+	nav [4]gesture.Click
+	// 日期格子的点击
+	days [calendarCells]gesture.Click
+}
+
+// 导航按钮调整的月数
+var calendarNavMonths = [4]int{-12, -1, 1, 12}
+
+// 导航按钮的文字
+var calendarNavText = [4]string{"«", "‹", "›", "»"}
+
+// 创建日历视图
+func newCalendarView() calendarView {
+	return calendarView{firstDay: -1, location: time.Local}
+}
+
+// 显示日期所在的月份
+func (p *calendarView) show(t time.Time) {
+	p.year, p.month, _ = t.Date()
+	p.location = t.Location()
+}
+
+// 打开时显示的月份，value为零值时显示今天所在的月份，超出范围时显示范围的边界
+func (p *calendarView) reset(value, now time.Time) {
+	if !value.IsZero() {
+		p.show(value)
+		return
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	p.show(now.In(p.location))
+	if !p.min.IsZero() && dayKey(p.min) > dayKey(now) {
+		p.show(p.min)
+	} else if !p.max.IsZero() && dayKey(p.max) < dayKey(now) {
+		p.show(p.max)
+	}
+}
+
+// 一周的第一天
+func (p *calendarView) weekStart(names *dateNames) time.Weekday {
+	if p.firstDay < 0 {
+		return names.firstDay
+	}
+	return p.firstDay
+}
+
+// 格子对应的日期，以及日期是否属于显示的月份
+func (p *calendarView) cellDate(i int, start time.Weekday) (time.Time, bool) {
+	first := time.Date(p.year, p.month, 1, 0, 0, 0, 0, p.location)
+	offset := (int(first.Weekday()) - int(start) + 7) % 7
+	date := first.AddDate(0, 0, i-offset)
+	return date, date.Month() == p.month
+}
+
+// 日期是否可以选择
+func (p *calendarView) allowed(date time.Time) bool {
+	key := dayKey(date)
+	if !p.min.IsZero() && key < dayKey(p.min) {
+		return false
+	}
+	if !p.max.IsZero() && key > dayKey(p.max) {
+		return false
+	}
+	return p.disabled == nil || !p.disabled(date)
+}
+
+// 从显示的月份移动months个月后的月份是否有可以选择的日期
+func (p *calendarView) canMove(months int) bool {
+	first := time.Date(p.year, p.month+time.Month(months), 1, 0, 0, 0, 0, p.location)
+	last := first.AddDate(0, 1, -1)
+	if !p.min.IsZero() && dayKey(last) < dayKey(p.min) {
+		return false
+	}
+	return p.max.IsZero() || dayKey(first) <= dayKey(p.max)
+}
+
+// 处理导航和日期的点击，返回点击的日期
+func (p *calendarView) update(gtx glayout.Context, names *dateNames) (time.Time, bool) {
+	for i := range p.nav {
+		for _, e := range p.nav[i].Update(gtx) {
+			if e.Kind == gesture.KindClick && p.canMove(calendarNavMonths[i]) {
+				first := time.Date(p.year, p.month+time.Month(calendarNavMonths[i]), 1, 0, 0, 0, 0, p.location)
+				p.year, p.month, _ = first.Date()
+			}
+		}
+	}
+	var picked time.Time
+	ok := false
+	start := p.weekStart(names)
+	for i := range p.days {
+		for _, e := range p.days[i].Update(gtx) {
+			if e.Kind != gesture.KindClick {
+				continue
+			}
+			if date, in := p.cellDate(i, start); in && p.allowed(date) {
+				picked, ok = date, true
+			}
+		}
+	}
+	return picked, ok
+}
+
+// 渲染日历，selected为选中的日期，零值表示没有选中
+func (p *calendarView) layout(gtx glayout.Context, field *pickerField, names *dateNames, selected time.Time) glayout.Dimensions {
+	cell := gtx.Dp(36)
+	navWidth := cell * 3 / 4
+	width := cell * 7
+	inset := gtx.Dp(2)
+
+	// 标题和导航按钮
+	navRects := [4]image.Rectangle{
+		image.Rect(0, 0, navWidth, cell),
+		image.Rect(navWidth, 0, navWidth*2, cell),
+		image.Rect(width-navWidth*2, 0, width-navWidth, cell),
+		image.Rect(width-navWidth, 0, width, cell),
+	}
+	for i, rect := range navRects {
+		col := field.fontColor
+		if p.canMove(calendarNavMonths[i]) {
+			if p.nav[i].Hovered() {
+				paint.FillShape(gtx.Ops, field.hoverColor, clip.UniformRRect(rect.Inset(inset), gtx.Dp(4)).Op(gtx.Ops))
+			}
+			area := clip.Rect(rect).Push(gtx.Ops)
+			pointer.CursorPointer.Add(gtx.Ops)
+			p.nav[i].Add(gtx.Ops)
+			area.Pop()
+		} else {
+			col = field.disabledColor
+		}
+		field.centered(gtx, field.textSize*1.1, calendarNavText[i], col, rect)
+	}
+	field.centered(gtx, field.textSize, names.monthTitle(p.year, p.month), field.fontColor, image.Rect(navWidth*2, 0, width-navWidth*2, cell))
+
+	// 星期
+	start := p.weekStart(names)
+	for i := 0; i < 7; i++ {
+		rect := image.Rect(i*cell, cell, (i+1)*cell, cell*2)
+		field.centered(gtx, field.textSize*0.8, names.weekdays[(int(start)+i)%7], field.secondaryColor, rect)
+	}
+
+	// 日期
+	today := dayKey(gtx.Now.In(p.location))
+	for i := 0; i < calendarCells; i++ {
+		date, in := p.cellDate(i, start)
+		if !in {
+			continue
+		}
+		rect := image.Rect(i%7*cell, (i/7+2)*cell, (i%7+1)*cell, (i/7+3)*cell)
+		circle := rect.Inset(inset)
+		key := dayKey(date)
+		col := field.fontColor
+		allowed := p.allowed(date)
+		switch {
+		case !selected.IsZero() && key == dayKey(selected):
+			paint.FillShape(gtx.Ops, field.markColor, clip.Ellipse(circle).Op(gtx.Ops))
+			col = field.markTextColor
+		case !allowed:
+			col = field.disabledColor
+		case p.days[i].Hovered():
+			paint.FillShape(gtx.Ops, field.hoverColor, clip.Ellipse(circle).Op(gtx.Ops))
+		}
+		if key == today {
+			paint.FillShape(gtx.Ops, field.markColor, clip.Stroke{Path: clip.Ellipse(circle).Path(gtx.Ops), Width: float32(gtx.Dp(1))}.Op())
+		}
+		field.centered(gtx, field.textSize*0.9, strconv.Itoa(date.Day()), col, rect)
+		if allowed {
+			area := clip.Rect(rect).Push(gtx.Ops)
+			pointer.CursorPointer.Add(gtx.Ops)
+			p.days[i].Add(gtx.Ops)
+			area.Pop()
+		}
+	}
+	return glayout.Dimensions{Size: image.Pt(width, cell*8)}
+}
+
+// 日期的比较键，忽略时间和时区
+func dayKey(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
@@ -0,0 +1,222 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+)
+
+// 时间视图的列
+const (
+	clockHour = iota
+	clockMinute
+	clockSecond
+	clockMeridiem
+)
+
+// 时间视图同时显示的行数
+const clockVisibleRows = 7
+
+// 时间视图，每一列分别选择时、分、秒和上下午，由TimePicker和DateTimePicker共用
+type clockView struct {
+	// 是否使用12小时制
+	hour12 bool
+	// 是否显示秒
+	seconds bool
+	// 每一列的列表
+	lists [4]glayout.List
+	// 每一列中每一项的点击
+	clicks [4][]gesture.Click
+	// 下次渲染时是否滚动到选中项
+	scroll bool
+}
+
+// 创建时间视图
+func newClockView() clockView {
+	view := clockView{}
+	for i := range view.lists {
+		view.lists[i].Axis = glayout.Vertical
+	}
+	return view
+}
+
+// 显示的列
+func (p *clockView) columns() []int {
+	cols := []int{clockHour, clockMinute}
+	if p.seconds {
+		cols = append(cols, clockSecond)
+	}
+	if p.hour12 {
+		cols = append(cols, clockMeridiem)
+	}
+	return cols
+}
+
+// 列的项数
+func (p *clockView) count(col int) int {
+	switch col {
+	case clockHour:
+		if p.hour12 {
+			return 12
+		}
+		return 24
+	case clockMeridiem:
+		return 2
+	}
+	return 60
+}
+
+// 时间在列中对应的项
+func (p *clockView) index(col int, value time.Time) int {
+	h, m, s := value.Clock()
+	switch col {
+	case clockHour:
+		if p.hour12 {
+			return h % 12
+		}
+		return h
+	case clockMinute:
+		return m
+	case clockSecond:
+		return s
+	}
+	return h / 12
+}
+
+// 列中项的文字
+func (p *clockView) text(col, i int, names *dateNames) string {
+	switch {
+	case col == clockMeridiem && i == 0:
+		return names.am
+	case col == clockMeridiem:
+		return names.pm
+	case col == clockHour && p.hour12 && i == 0:
+		return "12"
+	}
+	return fmt.Sprintf("%02d", i)
+}
+
+// 选择列中的项后的时间，日期部分保持不变
+func (p *clockView) apply(col, i int, value time.Time) time.Time {
+	h, m, s := value.Clock()
+	switch col {
+	case clockHour:
+		if p.hour12 {
+			h = h/12*12 + i
+		} else {
+			h = i
+		}
+	case clockMinute:
+		m = i
+	case clockSecond:
+		s = i
+	case clockMeridiem:
+		h = h%12 + i*12
+	}
+	year, month, day := value.Date()
+	return time.Date(year, month, day, h, m, s, 0, value.Location())
+}
+
+// 处理项的点击，返回新的时间
+func (p *clockView) update(gtx glayout.Context, value time.Time) (time.Time, bool) {
+	changed := false
+	for _, col := range p.columns() {
+		for i := range p.clicks[col] {
+			for _, e := range p.clicks[col][i].Update(gtx) {
+				if e.Kind == gesture.KindClick && i < p.count(col) {
+					value, changed = p.apply(col, i, value), true
+				}
+			}
+		}
+	}
+	return value, changed
+}
+
+// 渲染时间视图，value为零值时不高亮任何项
+func (p *clockView) layout(gtx glayout.Context, field *pickerField, names *dateNames, value time.Time) glayout.Dimensions {
+	rowHeight := gtx.Dp(30)
+	gap := gtx.Dp(4)
+	height := rowHeight * clockVisibleRows
+	x := 0
+	for n, col := range p.columns() {
+		count := p.count(col)
+		for len(p.clicks[col]) < count {
+			p.clicks[col] = append(p.clicks[col], gesture.Click{})
+		}
+		selected := -1
+		if !value.IsZero() {
+			selected = p.index(col, value)
+		}
+		if p.scroll {
+			p.lists[col].Position = glayout.Position{First: maxInt(selected-clockVisibleRows/2, 0)}
+		}
+		width := gtx.Dp(48)
+		if col == clockMeridiem {
+			width = gtx.Dp(64)
+		}
+		if n > 0 {
+			x += gap
+		}
+		stack := op.Offset(image.Pt(x, 0)).Push(gtx.Ops)
+		cgtx := gtx
+		cgtx.Constraints = glayout.Exact(image.Pt(width, height))
+		p.lists[col].Layout(cgtx, count, func(gtx glayout.Context, i int) glayout.Dimensions {
+			rect := image.Rect(0, 0, width, rowHeight)
+			click := &p.clicks[col][i]
+			txtColor := field.fontColor
+			switch {
+			case i == selected:
+				paint.FillShape(gtx.Ops, field.markColor, clip.UniformRRect(rect.Inset(gtx.Dp(2)), gtx.Dp(4)).Op(gtx.Ops))
+				txtColor = field.markTextColor
+			case click.Hovered():
+				paint.FillShape(gtx.Ops, field.hoverColor, clip.UniformRRect(rect.Inset(gtx.Dp(2)), gtx.Dp(4)).Op(gtx.Ops))
+			}
+			field.centered(gtx, field.textSize*0.9, p.text(col, i, names), txtColor, rect)
+			area := clip.Rect(rect).Push(gtx.Ops)
+			pointer.CursorPointer.Add(gtx.Ops)
+			click.Add(gtx.Ops)
+			area.Pop()
+			return glayout.Dimensions{Size: rect.Max}
+		})
+		stack.Pop()
+		x += width
+	}
+	p.scroll = false
+	return glayout.Dimensions{Size: image.Pt(x, height)}
+}
+
+// 格式化时间，12小时制时使用本地化的上下午名称
+func formatClock(value time.Time, hour12, seconds bool, names *dateNames) string {
+	h, m, s := value.Clock()
+	txt := ""
+	if hour12 {
+		h12 := h % 12
+		if h12 == 0 {
+			h12 = 12
+		}
+		txt = fmt.Sprintf("%d:%02d", h12, m)
+	} else {
+		txt = fmt.Sprintf("%02d:%02d", h, m)
+	}
+	if seconds {
+		txt += fmt.Sprintf(":%02d", s)
+	}
+	if hour12 {
+		meridiem := names.am
+		if h >= 12 {
+			meridiem = names.pm
+		}
+		if names.meridiemFirst {
+			return meridiem + " " + txt
+		}
+		txt += " " + meridiem
+	}
+	return txt
+}
@@ -0,0 +1,263 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/key"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 选择器的输入框和弹出层，由日期选择器和时间选择器共用
+//
+// 点击输入框或按下回车、空格打开弹出层，点击弹出层外部或按下Esc关闭
+type pickerField struct {
+	// 弹出层是否打开
+	open bool
+	// 是否有键盘焦点
+	focused bool
+	// 是否请求焦点
+	requestFocus bool
+	// 输入框的点击
+	click gesture.Click
+	// 弹出层外部点击的标签
+	outside int
+	// 弹出层的标签，阻止点击穿透到外部
+	popup int
+	// 没有值时显示的提示文字
+	hint string
+	// 指定的语言，为空时使用系统语言
+	language string
+	// 字体族
+	typeface typeface
+	// 本次渲染使用的字体，弹出层与输入框相同
+	face gfont.Font
+	// 字体大小
+	textSize float32
+	// 圆角
+	cornerRadius float32
+	// 文字颜色
+	fontColor color.NRGBA
+	// 背景颜色
+	background color.NRGBA
+	// 边框颜色
+	borderColor color.NRGBA
+	// 选中项和焦点边框的颜色
+	markColor color.NRGBA
+	// 选中项的文字颜色
+	markTextColor color.NRGBA
+	// 提示文字、星期等次要文字的颜色
+	secondaryColor color.NRGBA
+	// 鼠标悬浮颜色
+	hoverColor color.NRGBA
+	// 不可选择的项的颜色
+	disabledColor color.NRGBA
+}
+
+// 创建选择器的输入框
+func newPickerField() pickerField {
+	return pickerField{typeface: newTypeface()}
+}
+
+// 当前使用的日期名称
+func (p *pickerField) names(gtx glayout.Context) *dateNames {
+	if p.language != "" {
+		return lookupDateNames(p.language)
+	}
+	return lookupDateNames(gtx.Locale.Language)
+}
+
+// 解析字体，需要覆盖日期名称、提示文字和输入框的文字
+func (p *pickerField) font(names *dateNames, txt string) gfont.Font {
+	return gfont.Font{Typeface: p.typeface.resolve(names.sample + p.hint + txt)}
+}
+
+// 处理输入框的点击、键盘和弹出层外部的点击，返回弹出层是否从关闭变为打开
+func (p *pickerField) update(gtx glayout.Context) bool {
+	// 禁用时关闭弹出层
+	if gtx.Queue == nil {
+		p.open = false
+		return false
+	}
+	wasOpen := p.open
+	for _, e := range p.click.Update(gtx) {
+		switch e.Kind {
+		case gesture.KindPress:
+			p.requestFocus = true
+		case gesture.KindClick:
+			p.open = !p.open
+		}
+	}
+	for _, e := range gtx.Events(&p.outside) {
+		if e, ok := e.(pointer.Event); ok && e.Kind == pointer.Press {
+			p.open = false
+		}
+	}
+	for _, e := range gtx.Events(p) {
+		switch e := e.(type) {
+		case key.FocusEvent:
+			p.focused = e.Focus
+		case key.Event:
+			if e.State != key.Press {
+				continue
+			}
+			switch e.Name {
+			case key.NameReturn, key.NameEnter, key.NameSpace:
+				p.open = !p.open
+			case key.NameEscape:
+				p.open = false
+			}
+		}
+	}
+	return p.open && !wasOpen
+}
+
+// 渲染输入框，打开时在输入框下方绘制弹出层
+func (p *pickerField) layout(gtx glayout.Context, txt string, popup glayout.Widget) glayout.Dimensions {
+	p.face = p.font(p.names(gtx), txt)
+	padX, padY := gtx.Dp(12), gtx.Dp(8)
+	arrow := gtx.Dp(10)
+	label, col := txt, p.fontColor
+	if label == "" {
+		label, col = p.hint, p.secondaryColor
+	}
+	if gtx.Queue == nil {
+		col = withAlpha(col, 0x80)
+	}
+	tgtx := gtx
+	tgtx.Constraints = glayout.Constraints{Max: image.Pt(maxInt(gtx.Constraints.Max.X-padX*3-arrow, 0), gtx.Constraints.Max.Y)}
+	macro := op.Record(gtx.Ops)
+	dims := textpaint.Label{MaxLines: 1, Truncator: "…"}.Layout(tgtx, theme.Shaper, p.face, gunit.Sp(p.textSize), label, col)
+	call := macro.Stop()
+	// 没有文字时保留一行的高度
+	lineHeight := maxInt(dims.Size.Y, gtx.Sp(gunit.Sp(p.textSize*1.2)))
+	size := gtx.Constraints.Constrain(image.Pt(dims.Size.X+padX*3+arrow, lineHeight+padY*2))
+
+	rect := image.Rectangle{Max: size}
+	radius := gtx.Dp(gunit.Dp(p.cornerRadius))
+	border := p.borderColor
+	if p.open || p.focused {
+		border = p.markColor
+	}
+	paint.FillShape(gtx.Ops, border, clip.UniformRRect(rect, radius).Op(gtx.Ops))
+	paint.FillShape(gtx.Ops, p.background, clip.UniformRRect(rect.Inset(gtx.Dp(1)), maxInt(radius-gtx.Dp(1), 0)).Op(gtx.Ops))
+	stack := op.Offset(image.Pt(padX, (size.Y-dims.Size.Y)/2)).Push(gtx.Ops)
+	call.Add(gtx.Ops)
+	stack.Pop()
+	// 右侧的下拉箭头
+	p.paintArrow(gtx, image.Pt(size.X-padX-arrow, (size.Y-arrow/2)/2), arrow)
+
+	area := clip.Rect(rect).Push(gtx.Ops)
+	pointer.CursorPointer.Add(gtx.Ops)
+	p.click.Add(gtx.Ops)
+	// 没有焦点时不注册按键，否则没有组件获得焦点时回车和空格会打开最后绘制的选择框
+	keys := key.Set("")
+	if p.focused {
+		keys = "[⏎,⌤,Space,⎋]"
+	}
+	key.InputOp{Tag: p, Keys: keys}.Add(gtx.Ops)
+	if p.requestFocus && gtx.Queue != nil {
+		key.FocusOp{Tag: p}.Add(gtx.Ops)
+	}
+	p.requestFocus = false
+	area.Pop()
+
+	if p.open {
+		p.layoutPopup(gtx, size, popup)
+	}
+	return glayout.Dimensions{Size: size, Baseline: dims.Baseline + (size.Y-dims.Size.Y)/2}
+}
+
+// 绘制下拉箭头，off为箭头左上角的位置
+func (p *pickerField) paintArrow(gtx glayout.Context, off image.Point, width int) {
+	var path clip.Path
+	path.Begin(gtx.Ops)
+	path.MoveTo(glayout.FPt(off))
+	path.LineTo(glayout.FPt(off.Add(image.Pt(width, 0))))
+	path.LineTo(glayout.FPt(off.Add(image.Pt(width/2, width/2))))
+	path.Close()
+	paint.FillShape(gtx.Ops, p.secondaryColor, clip.Outline{Path: path.End()}.Op())
+}
+
+// 在输入框下方绘制弹出层，size为输入框的大小
+func (p *pickerField) layoutPopup(gtx glayout.Context, size image.Point, popup glayout.Widget) {
+	macro := op.Record(gtx.Ops)
+	// 覆盖整个窗口的区域，用于接收弹出层外部的点击
+	area := clip.Rect(image.Rect(-1<<24, -1<<24, 1<<24, 1<<24)).Push(gtx.Ops)
+	pointer.InputOp{Tag: &p.outside, Kinds: pointer.Press}.Add(gtx.Ops)
+	area.Pop()
+	op.Offset(image.Pt(0, size.Y+gtx.Dp(4))).Add(gtx.Ops)
+
+	padding, border := gtx.Dp(8), gtx.Dp(1)
+	pgtx := gtx
+	pgtx.Constraints = glayout.Constraints{Max: image.Pt(1<<24, 1<<24)}
+	content := op.Record(gtx.Ops)
+	stack := op.Offset(image.Pt(padding, padding)).Push(gtx.Ops)
+	dims := popup(pgtx)
+	stack.Pop()
+	call := content.Stop()
+
+	rect := image.Rectangle{Max: dims.Size.Add(image.Pt(padding*2, padding*2))}
+	radius := gtx.Dp(gunit.Dp(p.cornerRadius))
+	paint.FillShape(gtx.Ops, withAlpha(p.fontColor, 0x30), clip.UniformRRect(rect, radius).Op(gtx.Ops))
+	paint.FillShape(gtx.Ops, p.background, clip.UniformRRect(rect.Inset(border), maxInt(radius-border, 0)).Op(gtx.Ops))
+	area = clip.Rect(rect).Push(gtx.Ops)
+	pointer.InputOp{Tag: &p.popup, Kinds: pointer.Press}.Add(gtx.Ops)
+	call.Add(gtx.Ops)
+	area.Pop()
+	// 弹出层绘制在其他组件之上
+	op.Defer(gtx.Ops, macro.Stop())
+}
+
+// 在区域中居中绘制单行文字
+func (p *pickerField) centered(gtx glayout.Context, size float32, txt string, col color.NRGBA, rect image.Rectangle) {
+	tgtx := gtx
+	tgtx.Constraints = glayout.Constraints{Max: image.Pt(1<<24, 1<<24)}
+	macro := op.Record(gtx.Ops)
+	dims := textpaint.Label{MaxLines: 1}.Layout(tgtx, theme.Shaper, p.face, gunit.Sp(size), txt, col)
+	call := macro.Stop()
+	off := image.Pt(rect.Min.X+(rect.Dx()-dims.Size.X)/2, rect.Min.Y+(rect.Dy()-dims.Size.Y)/2)
+	stack := op.Offset(off).Push(gtx.Ops)
+	call.Add(gtx.Ops)
+	stack.Pop()
+}
+
+// 应用主题颜色，手动设置过的属性不会被覆盖
+func (p *pickerField) applyTheme(th *theme.Theme, o overrides) {
+	if !o.has(overrideFontColor) {
+		p.fontColor = th.Text
+	}
+	if !o.has(overrideBackground) {
+		p.background = th.Background
+	}
+	if !o.has(overrideBorderColor) {
+		p.borderColor = withAlpha(th.Text, 0x40)
+	}
+	if !o.has(overrideMarkColor) {
+		p.markColor = th.Primary
+	}
+	if !o.has(overrideFontSize) {
+		p.textSize = th.Body
+	}
+	if !o.has(overrideCornerRadius) {
+		p.cornerRadius = th.CornerRadius
+	}
+	if !o.has(overrideHoverColor) {
+		p.hoverColor = withAlpha(th.Text, 0x14)
+	}
+	if !o.has(overrideDisabledColor) {
+		p.disabledColor = withAlpha(th.Text, 0x40)
+	}
+	p.markTextColor = th.OnPrimary
+	p.secondaryColor = th.TextSecondary
+}
@@ -0,0 +1,140 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 日期选择器使用的本地化名称
+type dateNames struct {
+	// 月份名称，从一月开始
+	months [12]string
+	// 星期的简称，从星期日开始
+	weekdays [7]string
+	// 上午和下午
+	am, pm string
+	// 上下午是否写在时间前面
+	meridiemFirst bool
+	// 一周的第一天
+	firstDay time.Weekday
+	// 年月标题的格式，%[1]d为年份，%[2]s为月份名称
+	title string
+	// 所有名称拼接成的文本，用于解析字体
+	sample string
+}
+
+// 年月标题
+func (p *dateNames) monthTitle(year int, month time.Month) string {
+	return fmt.Sprintf(p.title, year, p.months[month-1])
+}
+
+// 数字形式的月份名称，例如1月
+func numericMonths(suffix string) (months [12]string) {
+	for i := range months {
+		months[i] = fmt.Sprint(i+1, suffix)
+	}
+	return months
+}
+
+// 各语言的日期名称，键为BCP-47语言标签的主语言
+var dateNamesTable = map[string]*dateNames{
+	"zh": {
+		months:        numericMonths("月"),
+		weekdays:      [7]string{"日", "一", "二", "三", "四", "五", "六"},
+		am:            "上午",
+		pm:            "下午",
+		meridiemFirst: true,
+		firstDay:      time.Monday,
+		title:         "%d年%s",
+	},
+	"ja": {
+		months:        numericMonths("月"),
+		weekdays:      [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		am:            "午前",
+		pm:            "午後",
+		meridiemFirst: true,
+		firstDay:      time.Sunday,
+		title:         "%d年%s",
+	},
+	"ko": {
+		months:        numericMonths("월"),
+		weekdays:      [7]string{"일", "월", "화", "수", "목", "금", "토"},
+		am:            "오전",
+		pm:            "오후",
+		meridiemFirst: true,
+		firstDay:      time.Sunday,
+		title:         "%d년 %s",
+	},
+	"en": {
+		months:   [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		weekdays: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+		am:       "AM",
+		pm:       "PM",
+		firstDay: time.Sunday,
+		title:    "%[2]s %[1]d",
+	},
+	"de": {
+		months:   [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		weekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		am:       "AM",
+		pm:       "PM",
+		firstDay: time.Monday,
+		title:    "%[2]s %[1]d",
+	},
+	"fr": {
+		months:   [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		weekdays: [7]string{"di", "lu", "ma", "me", "je", "ve", "sa"},
+		am:       "AM",
+		pm:       "PM",
+		firstDay: time.Monday,
+		title:    "%[2]s %[1]d",
+	},
+	"es": {
+		months:   [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		weekdays: [7]string{"do", "lu", "ma", "mi", "ju", "vi", "sá"},
+		am:       "a. m.",
+		pm:       "p. m.",
+		firstDay: time.Monday,
+		title:    "%[2]s de %[1]d",
+	},
+	"it": {
+		months:   [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		weekdays: [7]string{"do", "lu", "ma", "me", "gi", "ve", "sa"},
+		am:       "AM",
+		pm:       "PM",
+		firstDay: time.Monday,
+		title:    "%[2]s %[1]d",
+	},
+	"pt": {
+		months:   [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		weekdays: [7]string{"D", "S", "T", "Q", "Q", "S", "S"},
+		am:       "AM",
+		pm:       "PM",
+		firstDay: time.Sunday,
+		title:    "%[2]s de %[1]d",
+	},
+	"ru": {
+		months:   [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+		weekdays: [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		am:       "AM",
+		pm:       "PM",
+		firstDay: time.Monday,
+		title:    "%[2]s %[1]d",
+	},
+}
+
+func init() {
+	for _, names := range dateNamesTable {
+		names.sample = strings.Join(names.months[:], "") + strings.Join(names.weekdays[:], "") + names.am + names.pm + "0123456789:-/«‹›»"
+	}
+}
+
+// 根据BCP-47语言标签获取日期名称，不支持的语言使用英语
+func lookupDateNames(language string) *dateNames {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(language, "_", "-")), "-")
+	if names, ok := dateNamesTable[lang]; ok {
+		return names
+	}
+	return dateNamesTable["en"]
+}
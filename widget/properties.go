@@ -0,0 +1,82 @@
+package widget
+
+import "github.com/Seikaijyu/nenki.ui/widget/internal/setter"
+
+// 注册描述文件和样式表中可以使用的属性和事件
+//
+// 只注册设置外观和状态的函数，Insert、Undo、Focus等操作以及添加和删除子节点的函数不能作为属性使用，
+// 新增设置函数时需要同时在这里注册
+func init() {
+	setter.RegisterProperties(&AnchorLayout{}, "Direction", "Margin")
+	setter.RegisterProperties(&Border{},
+		"Color", "SideColor", "Width", "Widths", "SideWidth", "CornerRadius", "CornerRadii", "Style",
+		"Background", "LinearGradient", "RadialGradient", "Shadow", "InnerBorder", "Margin", "Padding")
+	setter.RegisterProperties(&Button{},
+		"Text", "FontSize", "FontFamily", "FontWeight", "Italic", "TextStyle", "LetterSpacing", "Decoration",
+		"FontColor", "Background", "CornerRadius", "LinearGradient", "RadialGradient", "Shadow", "InnerBorder",
+		"Margin", "Padding", "Disabled",
+		"OnClicked", "OnLongClicked", "OnPressed", "OnHovered", "OnFocused")
+	setter.RegisterProperties(&Canvas{}, "Size", "Background", "CaptureScroll", "Margin", "Disabled", "OnPaint", "OnPointer")
+	setter.RegisterProperties(&CheckBox{},
+		"Text", "Checked", "Size", "FontFamily", "FontWeight", "Italic", "TextStyle", "LetterSpacing", "Decoration",
+		"FontColor", "CheckMarkColor", "Margin", "Disabled",
+		"OnChecked", "OnHovered", "OnFocused")
+	setter.RegisterProperties(&CodeEditor{},
+		"Text", "Tokenizer", "SyntaxColors", "FontSize", "FontFamily", "TextColor", "TabSize", "InsertSpaces",
+		"AutoIndent", "LineNumbers", "LineNumberColor", "GutterColor", "HighlightLine", "LineColor",
+		"BracketMatching", "BracketColor", "MatchColor", "FindCaseSensitive", "Margin")
+	setter.RegisterProperties(&ColorPicker{},
+		"Value", "Hex", "ShowAlpha", "Swatches", "RecentColors", "Width", "BorderColor", "Margin", "Disabled",
+		"OnColorChanged")
+	setter.RegisterProperties(&ColumnLayout{}, "Margin")
+	setter.RegisterProperties(&ContainerLayout{},
+		"Background", "CornerRadius", "LinearGradient", "RadialGradient", "Shadow", "InnerBorder", "Margin")
+	for _, picker := range []any{&DatePicker{}, &DateTimePicker{}, &TimePicker{}} {
+		setter.RegisterProperties(picker,
+			"Value", "Format", "Locale", "Hint", "FontSize", "FontFamily", "FontColor", "Background", "BorderColor",
+			"Color", "CornerRadius", "Margin", "Disabled", "OnChanged")
+	}
+	for _, picker := range []any{&DatePicker{}, &DateTimePicker{}} {
+		setter.RegisterProperties(picker, "MinDate", "MaxDate", "DisabledDates", "FirstDayOfWeek")
+	}
+	for _, picker := range []any{&DateTimePicker{}, &TimePicker{}} {
+		setter.RegisterProperties(picker, "Hour12", "ShowSeconds")
+	}
+	setter.RegisterProperties(&Editor{},
+		"Text", "Hint", "HintColor", "FontSize", "FontFamily", "FontWeight", "TextWeight", "Italic", "TextStyle",
+		"TextColor", "SelectionColor", "Alignment", "LineHeight", "LineHeightScale", "WrapPolicy", "SingleLine",
+		"Submit", "ReadOnly", "Mask", "MaxLength", "AllowOnly", "KeyboardType", "HistoryDepth",
+		"ShowError", "ErrorColor", "ErrorFontSize", "MatchColor", "FindCaseSensitive", "FindWholeWord", "FindRegex",
+		"Autocomplete", "AutocompleteAsync", "AutocompleteDelay", "AutocompleteMinLength", "AutocompleteMaxItems",
+		"AutocompleteWord", "Margin", "Disabled",
+		"OnChange", "OnSubmit", "OnSelect", "OnFocused", "OnValidChanged", "OnHistoryChanged", "OnSuggestionAccepted")
+	setter.RegisterProperties(&FindBar{}, "Background", "ShowReplace", "Margin")
+	setter.RegisterProperties(&Label{},
+		"Text", "FontSize", "FontFamily", "FontWeight", "Italic", "TextStyle", "LetterSpacing", "Decoration",
+		"FontColor", "Alignment", "LineHeight", "LineHeightScale", "WrapPolicy", "MaxLines", "Truncator",
+		"Selectable", "SelectionColor", "Margin", "OnSelect")
+	setter.RegisterProperties(&ListLayout{},
+		"Axis", "ScrollToEnd", "ScrollWidth", "ScrollMinLen", "ScrollColor", "ScrollHoverColor", "ScrollBgColor",
+		"ScrollCornerRadius", "ScrollPadding", "ScrollPaddingEnable", "Margin")
+	setter.RegisterProperties(&Markdown{},
+		"Source", "FontSize", "CodeFontFamily", "ImageLoader", "Selectable", "Margin", "OnLinkClicked")
+	setter.RegisterProperties(&NumberInput{},
+		"Value", "Min", "Max", "Range", "Step", "Precision", "DecimalSeparator", "Margin", "Disabled", "OnValueChanged")
+	setter.RegisterProperties(&RadioButtons{},
+		"Axis", "Size", "FontFamily", "FontWeight", "Italic", "TextStyle", "LetterSpacing", "Decoration",
+		"FontColor", "RadioMarkColor", "Margin",
+		"OnSelected", "OnHovered", "OnFocused")
+	setter.RegisterProperties(&RangeSlider{}, "Values")
+	setter.RegisterProperties(&Slider{}, "Value")
+	for _, slider := range []any{&Slider{}, &RangeSlider{}} {
+		setter.RegisterProperties(slider,
+			"Range", "Step", "Ticks", "TickLabels", "Tooltip", "LabelFormat", "FingerSize", "Color", "TrackColor",
+			"Margin", "Disabled", "OnChanged", "OnDragging")
+	}
+	setter.RegisterProperties(&RichText{},
+		"Spans", "FontSize", "FontFamily", "FontColor", "LinkColor", "Alignment", "WrapPolicy", "Selectable",
+		"SelectionColor", "Margin", "OnLinkClicked")
+	setter.RegisterProperties(&RowLayout{}, "Margin")
+	setter.RegisterProperties(&Switch{},
+		"Enabled", "EnabledColor", "DisabledColor", "TrackColor", "Margin", "Disabled", "OnChange")
+}
@@ -0,0 +1,58 @@
+package widget
+
+// 遍历组件树，先序遍历，fn返回false时不再遍历该组件的子节点
+func Walk(root WidgetInterface, fn func(w WidgetInterface) bool) {
+	if root == nil || !fn(root) {
+		return
+	}
+	switch layout := root.(type) {
+	case interface{ GetChildAll() []WidgetInterface }:
+		for _, child := range layout.GetChildAll() {
+			Walk(child, fn)
+		}
+	case interface{ GetChild() WidgetInterface }:
+		Walk(layout.GetChild(), fn)
+	}
+}
+
+// 查找所有满足条件的组件
+func FindAll(root WidgetInterface, fn func(w WidgetInterface) bool) []WidgetInterface {
+	var result []WidgetInterface
+	Walk(root, func(w WidgetInterface) bool {
+		if fn(w) {
+			result = append(result, w)
+		}
+		return true
+	})
+	return result
+}
+
+// 通过ID查找组件，不存在时返回nil
+func FindByID(root WidgetInterface, id string) WidgetInterface {
+	var result WidgetInterface
+	Walk(root, func(w WidgetInterface) bool {
+		if result == nil && IDOf(w) == id {
+			result = w
+		}
+		return result == nil
+	})
+	return result
+}
+
+// 查找所有包含指定类名的组件
+func FindByClass(root WidgetInterface, class string) []WidgetInterface {
+	return FindAll(root, func(w WidgetInterface) bool {
+		for _, c := range ClassesOf(w) {
+			if c == class {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// 通过ID查找指定类型的组件，不存在或者类型不匹配时返回false
+func Find[T WidgetInterface](root WidgetInterface, id string) (T, bool) {
+	w, ok := FindByID(root, id).(T)
+	return w, ok
+}
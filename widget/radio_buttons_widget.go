@@ -2,10 +2,14 @@ package widget
 
 import (
 	"image/color"
+	"strings"
 
 	"github.com/Seikaijyu/nenki.ui/widget/axis"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
 	"github.com/Seikaijyu/nenki.ui/widget/theme"
 
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/io/semantic"
 	glayout "github.com/Seikaijyu/gio/layout"
 	gunit "github.com/Seikaijyu/gio/unit"
 	gwidget "github.com/Seikaijyu/gio/widget"
@@ -16,6 +20,18 @@ import (
 var _ WidgetInterface = &RadioButtons{}
 
 type radioButtonsConfig struct {
+	// 主题状态
+	themed themed
+	// 字体族
+	typeface typeface
+	// 字体粗细
+	weight text.Weight
+	// 字体样式
+	italic gfont.Style
+	// 字间距
+	letterSpacing float32
+	// 装饰线
+	decoration text.Decoration
 	// 文字颜色
 	color color.NRGBA
 	// 勾选框左边标记的颜色
@@ -38,6 +54,8 @@ type radioButtonsConfig struct {
 
 // 复选框
 type RadioButtons struct {
+	// 组件标识
+	identity
 	// 固定布局
 	flexChilds []glayout.FlexChild
 	// 单选组件组
@@ -60,6 +78,18 @@ func (p *RadioButtons) Then(fn func(self *RadioButtons)) *RadioButtons {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *RadioButtons) ID(id string) *RadioButtons {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *RadioButtons) Class(classes ...string) *RadioButtons {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *RadioButtons) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -76,12 +106,15 @@ func (p *RadioButtons) AppendRadioButton(key, text string) *RadioButtons {
 	radio.Color = p.config.color
 	radio.IconColor = p.config.iconColor
 	radio.TextSize = gunit.Sp(p.config.size)
+	radio.Font.Weight = p.config.weight
+	radio.Font.Style = p.config.italic
 	radio.Size = gunit.Dp(p.config.size + 1 + p.config.size*0.3)
 	pradio := &radio
 	p.radioButtonWidgets = append(p.radioButtonWidgets, pradio)
+	p.config.typeface.invalidate()
 	p.flexChilds = append(p.flexChilds,
 		glayout.Rigid(func(gtx glayout.Context) glayout.Dimensions {
-			return (*pradio).Layout(gtx)
+			return p.layoutRadio(gtx, pradio)
 		}),
 	)
 	return p
@@ -105,6 +138,11 @@ func (p *RadioButtons) OnSelected(fn func(p *RadioButtons, key string)) *RadioBu
 	return p
 }
 
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *RadioButtons) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *RadioButtons) Destroy() {
 	p.config.update = false
@@ -125,11 +163,28 @@ func (p *RadioButtons) Margin(Top, Left, Bottom, Right float32) *RadioButtons {
 	return p
 }
 
+// 获取外边距
+func (p *RadioButtons) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 重新设置父节点
 func (p *RadioButtons) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		p.config.update = false
 	}
+	p.applyTheme()
+	// 所有单选框共用同一个字体回退链
+	if p.config.typeface.stale() {
+		texts := make([]string, len(p.radioButtonWidgets))
+		for i, v := range p.radioButtonWidgets {
+			texts[i] = v.Label
+		}
+		face := p.config.typeface.resolve(strings.Join(texts, ""))
+		for _, v := range p.radioButtonWidgets {
+			v.Font.Typeface = face
+		}
+	}
 	if p.config._hovered != nil {
 		if value, ok := p.radioEnum.Hovered(); ok {
 			p.config._hovered(p, value)
@@ -166,24 +221,126 @@ func (p *RadioButtons) Size(size float32) *RadioButtons {
 	return p
 }
 
+// 设置字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *RadioButtons) FontFamily(name string) *RadioButtons {
+	p.config.typeface.set(name)
+	return p
+}
+
+// 设置字体粗细
+func (p *RadioButtons) FontWeight(weight text.Weight) *RadioButtons {
+	for _, v := range p.radioButtonWidgets {
+		v.Font.Weight = weight
+	}
+	p.config.weight = weight
+	return p
+}
+
+// 设置是否使用斜体
+func (p *RadioButtons) Italic(italic bool) *RadioButtons {
+	p.config.italic = gfont.Regular
+	if italic {
+		p.config.italic = gfont.Italic
+	}
+	for _, v := range p.radioButtonWidgets {
+		v.Font.Style = p.config.italic
+	}
+	return p
+}
+
+// 设置文本样式
+func (p *RadioButtons) TextStyle(style text.Style) *RadioButtons {
+	p.FontFamily(style.Family)
+	p.FontWeight(style.Weight)
+	p.Italic(style.Italic)
+	p.LetterSpacing(style.LetterSpacing)
+	p.Decoration(style.Decoration)
+	return p
+}
+
+// 设置字间距，字间距不参与换行计算
+func (p *RadioButtons) LetterSpacing(spacing float32) *RadioButtons {
+	p.config.letterSpacing = spacing
+	return p
+}
+
+// 设置装饰线，例如text.Underline|text.Strikethrough
+func (p *RadioButtons) Decoration(decoration text.Decoration) *RadioButtons {
+	p.config.decoration = decoration
+	return p
+}
+
+// 绘制单选框，有字间距或装饰线时自行绘制文本
+func (p *RadioButtons) layoutRadio(gtx glayout.Context, radio *gmaterial.RadioButtonStyle) glayout.Dimensions {
+	if p.config.letterSpacing == 0 && p.config.decoration == text.DecorationNone {
+		return radio.Layout(gtx)
+	}
+	p.radioEnum.Update(gtx)
+	return p.radioEnum.Layout(gtx, radio.Key, func(gtx glayout.Context) glayout.Dimensions {
+		semantic.RadioButton.Add(gtx.Ops)
+		icon := p.radioTheme.Icon.RadioUnchecked
+		if p.radioEnum.Value == radio.Key {
+			icon = p.radioTheme.Icon.RadioChecked
+		}
+		return checkable{
+			icon:          icon,
+			iconColor:     radio.IconColor,
+			size:          radio.Size,
+			label:         radio.Label,
+			font:          radio.Font,
+			textSize:      radio.TextSize,
+			color:         radio.Color,
+			letterSpacing: p.config.letterSpacing,
+			decoration:    p.config.decoration,
+		}.layout(gtx)
+	})
+}
+
 // 设置文字颜色
 func (p *RadioButtons) FontColor(r, g, b, a uint8) *RadioButtons {
 	for _, v := range p.radioButtonWidgets {
 		v.Color = color.NRGBA{R: r, G: g, B: b, A: a}
 	}
 	p.config.color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
 	return p
 }
 
+// 获取文字颜色
+func (p *RadioButtons) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.config.color)
+}
+
 // 设置单选框左边标记的颜色
 func (p *RadioButtons) RadioMarkColor(r, g, b, a uint8) *RadioButtons {
 	for _, v := range p.radioButtonWidgets {
 		v.IconColor = color.NRGBA{R: r, G: g, B: b, A: a}
 	}
 	p.config.iconColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
 	return p
 }
 
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *RadioButtons) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideFontColor) {
+		p.config.color = th.Text
+		for _, v := range p.radioButtonWidgets {
+			v.Color = th.Text
+		}
+	}
+	if !p.config.themed.overrides.has(overrideMarkColor) {
+		p.config.iconColor = th.Primary
+		for _, v := range p.radioButtonWidgets {
+			v.IconColor = th.Primary
+		}
+	}
+}
+
 // 设置方向
 func (p *RadioButtons) Axis(axis axis.Axis) *RadioButtons {
 	p.flexWidget.Axis = axis
@@ -194,12 +351,14 @@ func (p *RadioButtons) Axis(axis axis.Axis) *RadioButtons {
 func NewRadioButtons(axis axis.Axis) *RadioButtons {
 	radioWidget := &RadioButtons{
 		radioEnum:  &gwidget.Enum{},
-		radioTheme: theme.NewTheme(),
+		radioTheme: theme.Material(),
 		flexChilds: []glayout.FlexChild{},
 		config: &radioButtonsConfig{
 			update: true, size: 16,
-			color:     color.NRGBA{A: 255},
-			iconColor: color.NRGBA{R: 63, G: 81, B: 181, A: 255},
+			color:     theme.Current().Text,
+			iconColor: theme.Current().Primary,
+			themed:    newThemed(),
+			typeface:  newTypeface(),
 		},
 		margin:     &glayout.Inset{},
 		flexWidget: &glayout.Flex{Axis: axis},
@@ -0,0 +1,250 @@
+package widget
+
+import (
+	"image/color"
+
+	glayout "github.com/Seikaijyu/gio/layout"
+	gunit "github.com/Seikaijyu/gio/unit"
+	"github.com/Seikaijyu/nenki.ui/widget/axis"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &RangeSlider{}
+
+type rangeSliderConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 拖动事件
+	_dragging func(*RangeSlider, float32, float32)
+	// 值改变事件，松开滑块或使用键盘修改后触发
+	_changed func(*RangeSlider, float32, float32)
+}
+
+// 范围滑块，有两个圆点用于选择一个区间
+//
+// 拖动时移动离指针最近的圆点，两个圆点不会交叉；键盘调整最后操作的圆点
+type RangeSlider struct {
+	// 组件标识
+	identity
+	// 配置
+	config *rangeSliderConfig
+	// 外边距
+	margin *glayout.Inset
+	// 轨道
+	track sliderTrack
+}
+
+// 绑定函数
+func (p *RangeSlider) Then(fn func(self *RangeSlider)) *RangeSlider {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *RangeSlider) ID(id string) *RangeSlider {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *RangeSlider) Class(classes ...string) *RangeSlider {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *RangeSlider) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *RangeSlider) Destroy() {
+	p.config.update = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *RangeSlider) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 注册拖动事件，拖动过程中值改变时触发
+func (p *RangeSlider) OnDragging(fn func(p *RangeSlider, low, high float32)) *RangeSlider {
+	p.config._dragging = fn
+	return p
+}
+
+// 注册值改变事件，松开滑块或使用键盘修改后触发
+func (p *RangeSlider) OnChanged(fn func(p *RangeSlider, low, high float32)) *RangeSlider {
+	p.config._changed = fn
+	return p
+}
+
+// 是否更新组件
+func (p *RangeSlider) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不响应任何输入
+func (p *RangeSlider) Disabled(disabled bool) *RangeSlider {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *RangeSlider) GetState() State {
+	return stateOf(false, p.track.focused, p.config.disabled, false)
+}
+
+// 外边距
+func (p *RangeSlider) Margin(Top, Left, Bottom, Right float32) *RangeSlider {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *RangeSlider) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 滑块的颜色
+func (p *RangeSlider) Color(r, g, b, a uint8) *RangeSlider {
+	p.track.color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
+	return p
+}
+
+// 轨道的颜色
+func (p *RangeSlider) TrackColor(r, g, b, a uint8) *RangeSlider {
+	p.track.trackColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideTrackColor)
+	return p
+}
+
+// 滑块可点击的范围
+func (p *RangeSlider) FingerSize(size float32) *RangeSlider {
+	p.track.fingerSize = gunit.Dp(size)
+	return p
+}
+
+// 设置取值范围，默认为0-1，位于两端的圆点会随范围移动
+func (p *RangeSlider) Range(min, max float32) *RangeSlider {
+	atMin, atMax := p.track.values[0] == p.track.min, p.track.values[1] == p.track.max
+	p.track.setRange(min, max)
+	if atMin {
+		p.track.values[0] = p.track.min
+	}
+	if atMax {
+		p.track.values[1] = p.track.max
+	}
+	return p
+}
+
+// 设置步长，值会吸附到最小值加上步长的整数倍，为0时不吸附
+func (p *RangeSlider) Step(step float32) *RangeSlider {
+	p.track.step = abs32(step)
+	p.track.setRange(p.track.min, p.track.max)
+	return p
+}
+
+// 设置区间，超出范围时修正到范围内，不会触发事件
+func (p *RangeSlider) Values(low, high float32) *RangeSlider {
+	if low > high {
+		low, high = high, low
+	}
+	// 先放宽相邻的限制，再分别设置
+	p.track.values[0], p.track.values[1] = p.track.min, p.track.max
+	p.track.setValue(0, low)
+	p.track.setValue(1, high)
+	return p
+}
+
+// 获取区间
+func (p *RangeSlider) GetValues() (low, high float32) {
+	return p.track.values[0], p.track.values[1]
+}
+
+// 设置刻度间隔，从最小值开始每隔interval绘制一个刻度，为0时不显示刻度
+func (p *RangeSlider) Ticks(interval float32) *RangeSlider {
+	p.track.ticks = abs32(interval)
+	return p
+}
+
+// 是否在刻度旁显示数值
+func (p *RangeSlider) TickLabels(show bool) *RangeSlider {
+	p.track.tickLabels = show
+	return p
+}
+
+// 拖动时是否显示数值提示，默认显示
+func (p *RangeSlider) Tooltip(show bool) *RangeSlider {
+	p.track.tooltip = show
+	return p
+}
+
+// 设置刻度和数值提示的格式化函数，为nil时使用默认格式
+func (p *RangeSlider) LabelFormat(fn func(value float32) string) *RangeSlider {
+	p.track.format = fn
+	return p
+}
+
+// 请求键盘焦点，获得焦点后可以使用方向键、PageUp、PageDown、Home和End调整最后操作的圆点
+func (p *RangeSlider) Focus() *RangeSlider {
+	p.track.requestFocus = true
+	return p
+}
+
+// 渲染组件
+func (p *RangeSlider) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	changed, committed := p.track.update(gtx, p)
+	low, high := p.GetValues()
+	if changed && p.track.dragging && p.config._dragging != nil {
+		p.config._dragging(p, low, high)
+	}
+	if committed && p.config._changed != nil {
+		p.config._changed(p, low, high)
+	}
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		return p.track.layout(gtx, p)
+	})
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *RangeSlider) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	p.track.applyTheme(th, p.config.themed.overrides)
+}
+
+// 创建范围滑块，默认范围为0-1，区间为整个范围
+func NewRangeSlider(axis axis.Axis) *RangeSlider {
+	slider := &RangeSlider{
+		track:  newSliderTrack(axis, 2),
+		margin: &glayout.Inset{},
+		config: &rangeSliderConfig{update: true, themed: newThemed()},
+	}
+	slider.track.values[1] = slider.track.max
+	return slider
+}
@@ -0,0 +1,43 @@
+package widget
+
+import "sync"
+
+// 窗口重绘请求
+var redraw struct {
+	mutex sync.Mutex
+	// 唤醒窗口的函数，由UI上下文设置
+	handler func()
+	// 当前帧是否已经唤醒过窗口
+	pending bool
+}
+
+// 设置唤醒窗口的函数，由UI上下文在创建窗口时调用
+func OnRedrawRequest(fn func()) {
+	redraw.mutex.Lock()
+	redraw.handler = fn
+	redraw.pending = false
+	redraw.mutex.Unlock()
+}
+
+// 请求重新渲染窗口，可以在任意goroutine中调用，用于在后台goroutine中修改组件数据后及时显示
+//
+// 一帧中的多次请求只会唤醒窗口一次，没有窗口时什么也不做
+func RequestRedraw() {
+	redraw.mutex.Lock()
+	fn := redraw.handler
+	wake := fn != nil && !redraw.pending
+	if wake {
+		redraw.pending = true
+	}
+	redraw.mutex.Unlock()
+	if wake {
+		fn()
+	}
+}
+
+// 开始渲染新的一帧，之后的重绘请求会再次唤醒窗口，由UI上下文在每一帧开始时调用
+func BeginFrame() {
+	redraw.mutex.Lock()
+	redraw.pending = false
+	redraw.mutex.Unlock()
+}
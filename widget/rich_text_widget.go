@@ -0,0 +1,772 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Seikaijyu/nenki.ui/widget/font"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/text"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/clipboard"
+	"github.com/Seikaijyu/gio/io/key"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gtext "github.com/Seikaijyu/gio/text"
+	gunit "github.com/Seikaijyu/gio/unit"
+	"golang.org/x/image/math/fixed"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &RichText{}
+
+// 富文本片段
+type Span struct {
+	// 文本内容
+	Text string
+	// 文字颜色，透明时使用富文本的文字颜色，链接使用链接颜色
+	Color color.NRGBA
+	// 字体大小，为0时使用富文本的字体大小
+	Size float32
+	// 字体粗细
+	Weight text.Weight
+	// 是否斜体
+	Italic bool
+	// 字体族，为空时使用富文本的字体族
+	Family string
+	// 背景颜色，透明时不绘制背景
+	Background color.NRGBA
+	// 装饰线，链接总是带有下划线
+	Decoration text.Decoration
+	// 链接目标，不为空时片段可以点击
+	Link string
+}
+
+type richTextConfig struct {
+	// 主题状态
+	themed themed
+	// 字体族
+	family string
+	// 文字颜色
+	color color.NRGBA
+	// 链接颜色
+	linkColor color.NRGBA
+	// 选中区域颜色
+	selectionColor color.NRGBA
+	// 字体大小
+	size float32
+	// 对齐方式
+	alignment text.Alignment
+	// 换行策略
+	wrapPolicy text.WrapPolicy
+	// 是否可以选择文本
+	selectable bool
+	// 是否更新组件
+	update bool
+	// 链接点击事件
+	_linkClicked func(*RichText, string)
+	// 删除事件
+	_destroy func()
+}
+
+// 排版后的一段文本，属于同一个片段且在同一行
+type richRun struct {
+	// 片段索引
+	span int
+	// 字形，X相对于文本段开始位置，Y为0
+	glyphs []gtext.Glyph
+	// 相对于行首的位置
+	x int
+	// 宽度
+	width int
+	// 字体的像素大小
+	size fixed.Int26_6
+}
+
+// 字形簇，选择文本的最小单位
+type richCluster struct {
+	// 相对于行首的范围
+	x0, x1 int
+	// 在全部文本中的字符范围
+	rune0, rune1 int
+}
+
+// 排版后的一行
+type richLine struct {
+	runs     []richRun
+	clusters []richCluster
+	// 行宽
+	width int
+	// 基线以上和以下的高度
+	ascent, descent int
+	// 对齐产生的偏移
+	offset int
+	// 行顶部和基线的位置
+	top, baseline int
+}
+
+// 排版缓存
+type richLayout struct {
+	// 排版参数，参数不变时复用排版结果
+	maxWidth    int
+	pxPerSp     float32
+	fontVersion int
+	version     int
+	// 排版结果
+	lines []richLine
+	size  image.Point
+}
+
+// 文本选择状态
+type richSelection struct {
+	// 选择的开始和结束字符位置，开始可能大于结束
+	start, end int
+	// 是否正在拖动选择
+	dragging bool
+	// 是否获得焦点
+	focused bool
+	// 是否需要获取焦点
+	requestFocus bool
+	clicker      gesture.Click
+	dragger      gesture.Drag
+}
+
+// 富文本，一个段落中可以混合不同样式的文本和链接
+type RichText struct {
+	// 组件标识
+	identity
+	// 配置
+	config *richTextConfig
+	// 外边距
+	margin *glayout.Inset
+	// 文本片段
+	spans []Span
+	// 配置版本，改变时重新排版
+	version int
+	// 排版缓存
+	layout richLayout
+	// 文本选择
+	selection richSelection
+	// 链接点击手势，与带有链接的文本段一一对应
+	links []gesture.Click
+	// 链接点击手势对应的片段
+	linkSpans []int
+}
+
+// 绑定函数
+func (p *RichText) Then(fn func(self *RichText)) *RichText {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *RichText) ID(id string) *RichText {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *RichText) Class(classes ...string) *RichText {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *RichText) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *RichText) Destroy() {
+	p.config.update = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *RichText) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 是否更新组件
+func (p *RichText) Update(update bool) {
+	p.config.update = update
+}
+
+// 外边距
+func (p *RichText) Margin(Top, Left, Bottom, Right float32) *RichText {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *RichText) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 替换所有文本片段
+func (p *RichText) Spans(spans ...Span) *RichText {
+	p.spans = append([]Span(nil), spans...)
+	p.selection.start, p.selection.end = 0, 0
+	// 原来的链接对应的片段已经不存在，丢弃还没有处理的点击
+	p.links, p.linkSpans = nil, nil
+	p.version++
+	return p
+}
+
+// 添加文本片段
+func (p *RichText) AppendSpan(spans ...Span) *RichText {
+	p.spans = append(p.spans, spans...)
+	p.version++
+	return p
+}
+
+// 获取所有文本片段
+func (p *RichText) GetSpans() []Span {
+	return append([]Span(nil), p.spans...)
+}
+
+// 获取所有片段拼接后的文本
+func (p *RichText) GetText() string {
+	var builder strings.Builder
+	for _, span := range p.spans {
+		builder.WriteString(span.Text)
+	}
+	return builder.String()
+}
+
+// 设置默认字体族，字体需要先通过font包注册，为空时使用默认字体族
+func (p *RichText) FontFamily(name string) *RichText {
+	p.config.family = name
+	p.version++
+	return p
+}
+
+// 设置默认字体大小
+func (p *RichText) FontSize(size float32) *RichText {
+	p.config.size = size
+	p.config.themed.overrides.set(overrideFontSize)
+	p.version++
+	return p
+}
+
+// 获取字体大小
+func (p *RichText) GetFontSize() float32 {
+	return p.config.size
+}
+
+// 设置默认文字颜色
+func (p *RichText) FontColor(r, g, b, a uint8) *RichText {
+	p.config.color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
+	return p
+}
+
+// 获取文字颜色
+func (p *RichText) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.config.color)
+}
+
+// 设置链接颜色
+func (p *RichText) LinkColor(r, g, b, a uint8) *RichText {
+	p.config.linkColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideLinkColor)
+	return p
+}
+
+// 设置选中区域颜色
+func (p *RichText) SelectionColor(r, g, b, a uint8) *RichText {
+	p.config.selectionColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideSelectionColor)
+	return p
+}
+
+// 设置文本对齐方式
+func (p *RichText) Alignment(alignment text.Alignment) *RichText {
+	p.config.alignment = alignment
+	p.version++
+	return p
+}
+
+// 设置如何显示文本换行
+func (p *RichText) WrapPolicy(wrapPolicy text.WrapPolicy) *RichText {
+	p.config.wrapPolicy = wrapPolicy
+	p.version++
+	return p
+}
+
+// 设置是否可以选择文本，可以选择时支持拖动选择、双击选择单词、Ctrl+A全选和Ctrl+C复制
+func (p *RichText) Selectable(selectable bool) *RichText {
+	p.config.selectable = selectable
+	if !selectable {
+		p.selection.start, p.selection.end = 0, 0
+	}
+	return p
+}
+
+// 获取选中的文本
+func (p *RichText) GetSelectedText() string {
+	start, end := p.selectionRange()
+	if start == end {
+		return ""
+	}
+	runes := []rune(p.GetText())
+	if end > len(runes) {
+		end = len(runes)
+	}
+	if start >= end {
+		return ""
+	}
+	return string(runes[start:end])
+}
+
+// 链接点击事件
+func (p *RichText) OnLinkClicked(fn func(p *RichText, link string)) *RichText {
+	p.config._linkClicked = fn
+	return p
+}
+
+// 渲染
+func (p *RichText) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	p.applyTheme()
+	// 链接点击事件
+	for i := range p.links {
+		for _, e := range p.links[i].Update(gtx) {
+			// 链接手势来自上一帧的排版，片段可能已经被替换
+			if e.Kind == gesture.KindClick && p.config._linkClicked != nil && p.linkSpans[i] < len(p.spans) {
+				p.config._linkClicked(p, p.spans[p.linkSpans[i]].Link)
+			}
+		}
+	}
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		p.shape(gtx)
+		if p.config.selectable {
+			p.handleSelection(gtx)
+		}
+		size := gtx.Constraints.Constrain(p.layout.size)
+		defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+		if p.config.selectable {
+			pointer.CursorText.Add(gtx.Ops)
+			var keys key.Set
+			if p.selection.focused {
+				keys = "Short-[A,C]"
+			}
+			key.InputOp{Tag: &p.selection, Keys: keys}.Add(gtx.Ops)
+			if p.selection.requestFocus {
+				key.FocusOp{Tag: &p.selection}.Add(gtx.Ops)
+			}
+			p.selection.requestFocus = false
+			p.selection.clicker.Add(gtx.Ops)
+			p.selection.dragger.Add(gtx.Ops)
+		}
+		p.paint(gtx)
+		return glayout.Dimensions{Size: size, Baseline: size.Y - p.firstBaseline()}
+	})
+}
+
+// 第一行的基线位置
+func (p *RichText) firstBaseline() int {
+	if len(p.layout.lines) == 0 {
+		return 0
+	}
+	return p.layout.lines[0].baseline
+}
+
+// 排版所有片段，排版参数不变时复用上次的结果
+func (p *RichText) shape(gtx glayout.Context) {
+	maxWidth := gtx.Constraints.Max.X
+	l := &p.layout
+	if l.lines != nil && l.maxWidth == maxWidth && l.pxPerSp == gtx.Metric.PxPerSp &&
+		l.fontVersion == font.Version() && l.version == p.version {
+		return
+	}
+	l.maxWidth, l.pxPerSp, l.fontVersion, l.version = maxWidth, gtx.Metric.PxPerSp, font.Version(), p.version
+	l.lines = []richLine{{}}
+	x, runeOffset := 0, 0
+	newline := func() {
+		l.lines = append(l.lines, richLine{})
+		x = 0
+	}
+	for si, span := range p.spans {
+		size := span.Size
+		if size == 0 {
+			size = p.config.size
+		}
+		family := span.Family
+		if family == "" {
+			family = p.config.family
+		}
+		params := gtext.Parameters{
+			Font:       gfont.Font{Typeface: font.Resolve(family, span.Text), Weight: span.Weight},
+			PxPerEm:    fixed.I(gtx.Sp(gunit.Sp(size))),
+			WrapPolicy: p.config.wrapPolicy,
+			Locale:     gtx.Locale,
+		}
+		if span.Italic {
+			params.Font.Style = gfont.Italic
+		}
+		rest := span.Text
+		for rest != "" {
+			// 在行首时可以一次排版多行，否则只排版剩余宽度中的第一行
+			full := x == 0
+			params.MaxWidth = maxWidth - x
+			theme.Shaper.LayoutString(params, rest)
+			total := utf8.RuneCountInString(rest)
+			consumed := 0
+			retry := false
+			var glyphs []gtext.Glyph
+			for g, ok := theme.Shaper.NextGlyph(); ok; g, ok = theme.Shaper.NextGlyph() {
+				glyphs = append(glyphs, g)
+				consumed += int(g.Runes)
+				if g.Flags&gtext.FlagLineBreak == 0 {
+					continue
+				}
+				// 不在行首时，如果在单词中间断开，则把整个单词移到下一行
+				if !full && consumed < total && !p.breakable(rest, consumed) {
+					retry = true
+					break
+				}
+				line := &l.lines[len(l.lines)-1]
+				x += p.appendRun(line, si, glyphs, x, params.PxPerEm, runeOffset)
+				runeOffset += runesOf(glyphs)
+				glyphs = nil
+				if consumed < total || g.Flags&gtext.FlagParagraphBreak != 0 {
+					newline()
+				}
+				if !full || consumed >= total {
+					break
+				}
+			}
+			if retry {
+				newline()
+				continue
+			}
+			if consumed == 0 {
+				break
+			}
+			rest = rest[byteOffset(rest, consumed):]
+		}
+	}
+	// 去掉末尾的空行
+	if n := len(l.lines); n > 1 && len(l.lines[n-1].runs) == 0 {
+		l.lines = l.lines[:n-1]
+	}
+	// 计算每一行的位置
+	y, width := 0, 0
+	for i := range l.lines {
+		line := &l.lines[i]
+		line.top = y
+		line.baseline = y + line.ascent
+		y = line.baseline + line.descent
+		if line.width > width {
+			width = line.width
+		}
+	}
+	if p.config.alignment != text.Start && maxWidth < math.MaxInt32/2 {
+		width = maxWidth
+	}
+	for i := range l.lines {
+		line := &l.lines[i]
+		switch p.config.alignment {
+		case text.Middle:
+			line.offset = (width - line.width) / 2
+		case text.End:
+			line.offset = width - line.width
+		}
+	}
+	l.size = image.Pt(width, y)
+}
+
+// 是否可以在指定字符位置换行
+func (p *RichText) breakable(s string, at int) bool {
+	if p.config.wrapPolicy == text.WrapGraphemes {
+		return true
+	}
+	if at <= 0 {
+		return false
+	}
+	i := byteOffset(s, at)
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i:])
+	return unicode.IsSpace(before) || unicode.IsSpace(after) || isWide(before) || isWide(after)
+}
+
+// 将排版好的字形添加到行中，返回文本段的宽度
+func (p *RichText) appendRun(line *richLine, span int, glyphs []gtext.Glyph, x int, size fixed.Int26_6, runeOffset int) int {
+	if len(glyphs) == 0 {
+		return 0
+	}
+	origin := glyphs[0].X
+	var advance fixed.Int26_6
+	clusterStart := fixed.Int26_6(0)
+	clusterOpen := false
+	for i := range glyphs {
+		g := &glyphs[i]
+		g.X -= origin
+		g.Y = 0
+		if !clusterOpen {
+			clusterStart = g.X
+			clusterOpen = true
+		}
+		advance += g.Advance
+		if a := g.Ascent.Ceil(); a > line.ascent {
+			line.ascent = a
+		}
+		if d := g.Descent.Ceil(); d > line.descent {
+			line.descent = d
+		}
+		if g.Flags&gtext.FlagClusterBreak != 0 {
+			line.clusters = append(line.clusters, richCluster{
+				x0:    x + clusterStart.Floor(),
+				x1:    x + (g.X + g.Advance).Ceil(),
+				rune0: runeOffset,
+				rune1: runeOffset + int(g.Runes),
+			})
+			runeOffset += int(g.Runes)
+			clusterOpen = false
+		}
+	}
+	width := advance.Ceil()
+	line.runs = append(line.runs, richRun{span: span, glyphs: glyphs, x: x, width: width, size: size})
+	line.width = x + width
+	return width
+}
+
+// 绘制背景、选中区域、文字和链接区域
+func (p *RichText) paint(gtx glayout.Context) {
+	start, end := p.selectionRange()
+	links := 0
+	for _, line := range p.layout.lines {
+		bottom := line.baseline + line.descent
+		for _, run := range line.runs {
+			span := p.spans[run.span]
+			if span.Background.A > 0 {
+				rect := image.Rect(line.offset+run.x, line.top, line.offset+run.x+run.width, bottom)
+				paint.FillShape(gtx.Ops, span.Background, clip.Rect(rect).Op())
+			}
+		}
+		if start != end {
+			for _, c := range line.clusters {
+				if c.rune1 > start && c.rune0 < end {
+					rect := image.Rect(line.offset+c.x0, line.top, line.offset+c.x1, bottom)
+					paint.FillShape(gtx.Ops, p.config.selectionColor, clip.Rect(rect).Op())
+				}
+			}
+		}
+		for _, run := range line.runs {
+			span := p.spans[run.span]
+			col := p.config.color
+			decoration := span.Decoration
+			if span.Link != "" {
+				col = p.config.linkColor
+				decoration |= text.Underline
+			}
+			if span.Color.A > 0 {
+				col = span.Color
+			}
+			x := line.offset + run.x
+			// 文本段的字形相对于自身的开始位置和基线
+			stack := op.Offset(image.Pt(x, line.baseline)).Push(gtx.Ops)
+			textpaint.Glyphs(gtx, theme.Shaper, run.glyphs, col)
+			stack.Pop()
+			textpaint.Decorate(gtx, decoration, x, x+run.width, line.baseline, run.size, col)
+			// 链接区域
+			if span.Link != "" {
+				if links == len(p.links) {
+					p.links = append(p.links, gesture.Click{})
+					p.linkSpans = append(p.linkSpans, 0)
+				}
+				p.linkSpans[links] = run.span
+				area := clip.Rect(image.Rect(x, line.top, x+run.width, bottom)).Push(gtx.Ops)
+				pointer.CursorPointer.Add(gtx.Ops)
+				p.links[links].Add(gtx.Ops)
+				area.Pop()
+				links++
+			}
+		}
+	}
+	p.links = p.links[:links]
+	p.linkSpans = p.linkSpans[:links]
+}
+
+// 处理文本选择的指针和键盘事件
+func (p *RichText) handleSelection(gtx glayout.Context) {
+	s := &p.selection
+	for _, e := range s.clicker.Update(gtx) {
+		if e.Kind != gesture.KindPress || e.Source != pointer.Mouse {
+			continue
+		}
+		pos := p.hit(e.Position)
+		s.requestFocus = true
+		s.dragging = true
+		if e.Modifiers == key.ModShift {
+			s.end = pos
+		} else {
+			s.start, s.end = pos, pos
+		}
+		switch {
+		case e.NumClicks == 2:
+			s.start, s.end = p.wordAt(pos)
+			s.dragging = false
+		case e.NumClicks >= 3:
+			s.start, s.end = 0, utf8.RuneCountInString(p.GetText())
+			s.dragging = false
+		}
+	}
+	for _, e := range s.dragger.Update(gtx.Metric, gtx, gesture.Both) {
+		if e.Source != pointer.Mouse || !s.dragging {
+			continue
+		}
+		switch e.Kind {
+		case pointer.Drag, pointer.Release:
+			s.end = p.hit(image.Pt(int(math.Round(float64(e.Position.X))), int(math.Round(float64(e.Position.Y)))))
+			if e.Kind == pointer.Release {
+				s.dragging = false
+			}
+		}
+	}
+	for _, e := range gtx.Events(s) {
+		switch e := e.(type) {
+		case key.FocusEvent:
+			s.focused = e.Focus
+		case key.Event:
+			if !s.focused || e.State != key.Press || !e.Modifiers.Contain(key.ModShortcut) {
+				break
+			}
+			switch e.Name {
+			case "A":
+				s.start, s.end = 0, utf8.RuneCountInString(p.GetText())
+			case "C":
+				if text := p.GetSelectedText(); text != "" {
+					clipboard.WriteOp{Text: text}.Add(gtx.Ops)
+				}
+			}
+		}
+	}
+}
+
+// 选中的字符范围
+func (p *RichText) selectionRange() (int, int) {
+	start, end := p.selection.start, p.selection.end
+	if start > end {
+		start, end = end, start
+	}
+	return start, end
+}
+
+// 获取坐标位置对应的字符位置
+func (p *RichText) hit(pos image.Point) int {
+	lines := p.layout.lines
+	for i, line := range lines {
+		if pos.Y >= line.baseline+line.descent && i < len(lines)-1 {
+			continue
+		}
+		for _, c := range line.clusters {
+			if pos.X < line.offset+(c.x0+c.x1)/2 {
+				return c.rune0
+			}
+		}
+		if n := len(line.clusters); n > 0 {
+			// 行尾的换行符不计入选择
+			last := line.clusters[n-1]
+			if last.x1 == last.x0 && i < len(lines)-1 {
+				return last.rune0
+			}
+			return last.rune1
+		}
+	}
+	return 0
+}
+
+// 获取字符位置所在的单词范围
+func (p *RichText) wordAt(pos int) (int, int) {
+	runes := []rune(p.GetText())
+	word := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+	}
+	start, end := pos, pos
+	for start > 0 && start <= len(runes) && word(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && word(runes[end]) {
+		end++
+	}
+	return start, end
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *RichText) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideFontColor) {
+		p.config.color = th.Text
+	}
+	if !p.config.themed.overrides.has(overrideLinkColor) {
+		p.config.linkColor = th.Primary
+	}
+	if !p.config.themed.overrides.has(overrideSelectionColor) {
+		p.config.selectionColor = withAlpha(th.Primary, 0x60)
+	}
+	if !p.config.themed.overrides.has(overrideFontSize) && p.config.size != th.Subtitle {
+		p.config.size = th.Subtitle
+		p.version++
+	}
+}
+
+// 字形的字符数量
+func runesOf(glyphs []gtext.Glyph) int {
+	n := 0
+	for _, g := range glyphs {
+		n += int(g.Runes)
+	}
+	return n
+}
+
+// 第n个字符在字符串中的字节位置
+func byteOffset(s string, n int) int {
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return i
+}
+
+// 是否为可以在任意位置换行的宽字符，例如中日韩文字
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		unicode.Is(unicode.Ideographic, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
+
+// 创建富文本
+func NewRichText(spans ...Span) *RichText {
+	return &RichText{
+		spans:  append([]Span(nil), spans...),
+		margin: &glayout.Inset{},
+		config: &richTextConfig{
+			update: true,
+			size:   theme.Current().Subtitle,
+			themed: newThemed(),
+		},
+	}
+}
@@ -15,6 +15,8 @@ type rowLayoutConfig struct {
 }
 
 type RowLayout struct {
+	// 组件标识
+	identity
 	config           *rowLayoutConfig
 	margin           *glayout.Inset
 	childWidgets     []WidgetInterface
@@ -32,6 +34,18 @@ func (p *RowLayout) Then(fn func(self *RowLayout)) *RowLayout {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *RowLayout) ID(id string) *RowLayout {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *RowLayout) Class(classes ...string) *RowLayout {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
 // 注册删除事件
 func (p *RowLayout) OnDestroy(fn func()) {
 	p.config._destroy = fn
@@ -56,8 +70,10 @@ func (p *RowLayout) Update(update bool) {
 func (p *RowLayout) ResetParent(child WidgetInterface) {
 	child.Destroy()
 	child.Update(true)
+	setParent(child, p)
 	child.OnDestroy(func() {
 		child.Update(false)
+		setParent(child, nil)
 		p.RemoveChild(child)
 	})
 }
@@ -148,6 +164,11 @@ func (p *RowLayout) Margin(Top, Left, Bottom, Right float32) *RowLayout {
 	return p
 }
 
+// 获取外边距
+func (p *RowLayout) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 渲染UI
 func (p *RowLayout) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
@@ -0,0 +1,375 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/Seikaijyu/nenki.ui/widget/axis"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/textpaint"
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+
+	"github.com/Seikaijyu/gio/f32"
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/gio/gesture"
+	"github.com/Seikaijyu/gio/io/key"
+	"github.com/Seikaijyu/gio/io/pointer"
+	glayout "github.com/Seikaijyu/gio/layout"
+	"github.com/Seikaijyu/gio/op"
+	"github.com/Seikaijyu/gio/op/clip"
+	"github.com/Seikaijyu/gio/op/paint"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 滑块圆点的半径
+const sliderThumbRadius gunit.Dp = 8
+
+// 最多绘制的刻度数量
+const sliderMaxTicks = 1000
+
+// 滑块的轨道，由Slider和RangeSlider共用，处理拖动、键盘和绘制
+type sliderTrack struct {
+	// 方向
+	axis axis.Axis
+	// 取值范围
+	min, max float32
+	// 步长，为0时不吸附
+	step float32
+	// 每个圆点的值，从小到大排列
+	values []float32
+	// 正在操作的圆点
+	active int
+	// 拖动
+	drag gesture.Drag
+	// 是否正在拖动
+	dragging bool
+	// 是否有键盘焦点
+	focused bool
+	// 是否请求焦点
+	requestFocus bool
+	// 轨道长度，不包括两端的圆点半径
+	length int
+	// 圆点半径的像素大小
+	thumbRadius int
+	// 可点击的范围
+	fingerSize gunit.Dp
+	// 刻度间隔，为0时不显示刻度
+	ticks float32
+	// 是否在刻度下方显示数值
+	tickLabels bool
+	// 拖动时是否显示数值提示
+	tooltip bool
+	// 数值的格式化函数
+	format func(float32) string
+	// 滑块颜色
+	color color.NRGBA
+	// 轨道颜色
+	trackColor color.NRGBA
+	// 刻度和刻度数值的颜色
+	tickColor color.NRGBA
+	// 数值提示的背景和文字颜色
+	tooltipColor, tooltipTextColor color.NRGBA
+}
+
+// 创建轨道，count为圆点的数量
+func newSliderTrack(a axis.Axis, count int) sliderTrack {
+	return sliderTrack{
+		axis:       a,
+		max:        1,
+		values:     make([]float32, count),
+		fingerSize: 20,
+		tooltip:    true,
+	}
+}
+
+// 设置取值范围，min大于max时交换
+func (p *sliderTrack) setRange(min, max float32) {
+	if min > max {
+		min, max = max, min
+	}
+	p.min, p.max = min, max
+	for i := range p.values {
+		p.values[i] = p.snap(p.values[i])
+	}
+}
+
+// 设置第i个圆点的值，不会越过相邻的圆点
+func (p *sliderTrack) setValue(i int, value float32) bool {
+	value = p.snap(value)
+	if i > 0 && value < p.values[i-1] {
+		value = p.values[i-1]
+	}
+	if i < len(p.values)-1 && value > p.values[i+1] {
+		value = p.values[i+1]
+	}
+	if value == p.values[i] {
+		return false
+	}
+	p.values[i] = value
+	return true
+}
+
+// 将值修正到范围内并吸附到步长
+func (p *sliderTrack) snap(value float32) float32 {
+	v := float64(value)
+	if p.step > 0 {
+		v = float64(p.min) + math.Round((v-float64(p.min))/float64(p.step))*float64(p.step)
+	}
+	return float32(math.Max(float64(p.min), math.Min(float64(p.max), v)))
+}
+
+// 值在范围中的比例
+func (p *sliderTrack) fraction(value float32) float32 {
+	if p.max == p.min {
+		return 0
+	}
+	return (value - p.min) / (p.max - p.min)
+}
+
+// 格式化数值
+func (p *sliderTrack) text(value float32) string {
+	if p.format != nil {
+		return p.format(value)
+	}
+	return strconv.FormatFloat(float64(value), 'f', -1, 32)
+}
+
+// 键盘调整的步长，没有设置步长时为范围的1%
+func (p *sliderTrack) keyStep() float32 {
+	if p.step > 0 {
+		return p.step
+	}
+	return (p.max - p.min) / 100
+}
+
+// 处理拖动和键盘事件，返回值是否改变以及是否完成了一次修改
+//
+// 拖动过程中只返回changed，松开时返回committed；键盘修改同时返回两者
+func (p *sliderTrack) update(gtx glayout.Context, tag any) (changed, committed bool) {
+	for _, e := range p.drag.Update(gtx.Metric, gtx, gesture.Axis(p.axis)) {
+		switch e.Kind {
+		case pointer.Press, pointer.Drag:
+			value := p.valueAt(e.Position)
+			if e.Kind == pointer.Press {
+				p.active = p.nearest(value)
+				p.dragging = true
+				p.requestFocus = true
+			}
+			if p.setValue(p.active, value) {
+				changed = true
+			}
+		case pointer.Release, pointer.Cancel:
+			if p.dragging {
+				p.dragging = false
+				committed = true
+			}
+		}
+	}
+	for _, e := range gtx.Events(tag) {
+		switch e := e.(type) {
+		case key.FocusEvent:
+			p.focused = e.Focus
+		case key.Event:
+			if e.State != key.Press {
+				continue
+			}
+			value := p.values[p.active]
+			switch e.Name {
+			case key.NameLeftArrow, key.NameDownArrow:
+				value -= p.keyStep()
+			case key.NameRightArrow, key.NameUpArrow:
+				value += p.keyStep()
+			case key.NamePageDown:
+				value -= p.keyStep() * 10
+			case key.NamePageUp:
+				value += p.keyStep() * 10
+			case key.NameHome:
+				value = p.min
+			case key.NameEnd:
+				value = p.max
+			}
+			if p.setValue(p.active, value) {
+				changed, committed = true, true
+			}
+		}
+	}
+	return changed, committed
+}
+
+// 指针位置对应的值
+func (p *sliderTrack) valueAt(pos f32.Point) float32 {
+	main := pos.X
+	if p.axis == axis.Vertical {
+		main = pos.Y
+	}
+	if p.length <= 0 {
+		return p.min
+	}
+	f := (main - float32(p.thumbRadius)) / float32(p.length)
+	if p.axis == axis.Vertical {
+		f = 1 - f
+	}
+	return p.min + f*(p.max-p.min)
+}
+
+// 离值最近的圆点，重叠时选择可以移动的一个
+func (p *sliderTrack) nearest(value float32) int {
+	best := 0
+	for i, v := range p.values {
+		d, bd := abs32(v-value), abs32(p.values[best]-value)
+		if d < bd || d == bd && value > v {
+			best = i
+		}
+	}
+	return best
+}
+
+// 渲染轨道，tag用于接收键盘事件，禁用时使用半透明的颜色
+func (p *sliderTrack) layout(gtx glayout.Context, tag any) glayout.Dimensions {
+	tr := gtx.Dp(sliderThumbRadius)
+	p.thumbRadius = tr
+	trackWidth := gtx.Dp(4)
+	a := p.axis
+	sizeMain := maxInt(a.Convert(gtx.Constraints.Min).X, tr*6)
+	sizeCross := maxInt(2*tr, minInt(gtx.Dp(p.fingerSize), a.Convert(gtx.Constraints.Max).Y))
+	p.length = sizeMain - 2*tr
+	// 主轴和交叉轴坐标转换为实际坐标，垂直时从下往上
+	rect := func(minx, miny, maxx, maxy int) image.Rectangle {
+		if a == axis.Vertical {
+			minx, maxx = sizeMain-maxx, sizeMain-minx
+		}
+		return image.Rectangle{Min: a.Convert(image.Pt(minx, miny)), Max: a.Convert(image.Pt(maxx, maxy))}
+	}
+	pos := func(value float32) int {
+		return tr + int(p.fraction(value)*float32(p.length))
+	}
+
+	// 输入区域
+	area := clip.Rect(rect(0, 0, sizeMain, sizeCross)).Push(gtx.Ops)
+	p.drag.Add(gtx.Ops)
+	// 没有焦点时不注册按键，否则没有组件获得焦点时方向键会交给最后绘制的滑块
+	keys := key.Set("")
+	if p.focused {
+		keys = "[←,→,↑,↓,⇞,⇟,⇱,⇲]"
+	}
+	key.InputOp{Tag: tag, Keys: keys}.Add(gtx.Ops)
+	if p.requestFocus && gtx.Queue != nil {
+		key.FocusOp{Tag: tag}.Add(gtx.Ops)
+	}
+	p.requestFocus = false
+	area.Pop()
+
+	col, trackCol := p.color, p.trackColor
+	if gtx.Queue == nil {
+		col, trackCol = withAlpha(col, 0x60), withAlpha(trackCol, 0x60)
+	}
+	center := sizeCross / 2
+	// 轨道，有多个圆点时高亮圆点之间的部分
+	paint.FillShape(gtx.Ops, trackCol, clip.Rect(rect(tr, center-trackWidth/2, sizeMain-tr, center+trackWidth/2)).Op())
+	start := tr
+	if len(p.values) > 1 {
+		start = pos(p.values[0])
+	}
+	end := pos(p.values[len(p.values)-1])
+	paint.FillShape(gtx.Ops, col, clip.Rect(rect(start, center-trackWidth/2, end, center+trackWidth/2)).Op())
+
+	// 刻度
+	cross := sizeCross
+	if p.ticks > 0 && (p.max-p.min)/p.ticks <= sliderMaxTicks {
+		cross = p.layoutTicks(gtx, rect, pos, sizeCross)
+	}
+
+	// 圆点，获得焦点时在正在操作的圆点外绘制光晕
+	for i, v := range p.values {
+		x := pos(v)
+		if i == p.active && (p.focused || p.dragging) && gtx.Queue != nil {
+			halo := tr * 2
+			paint.FillShape(gtx.Ops, withAlpha(col, 0x40), clip.Ellipse(rect(x-halo, center-halo, x+halo, center+halo)).Op(gtx.Ops))
+		}
+		paint.FillShape(gtx.Ops, col, clip.Ellipse(rect(x-tr, center-tr, x+tr, center+tr)).Op(gtx.Ops))
+	}
+	if p.dragging && p.tooltip {
+		p.layoutTooltip(gtx, rect(pos(p.values[p.active]), center-tr, pos(p.values[p.active]), center+tr))
+	}
+	return glayout.Dimensions{Size: a.Convert(image.Pt(sizeMain, cross))}
+}
+
+// 绘制刻度和刻度数值，返回包括刻度在内的交叉轴大小
+func (p *sliderTrack) layoutTicks(gtx glayout.Context, rect func(minx, miny, maxx, maxy int) image.Rectangle, pos func(float32) int, sizeCross int) int {
+	tickLen, tickWidth, gap := gtx.Dp(4), maxInt(gtx.Dp(1), 1), gtx.Dp(2)
+	top := sizeCross - gap
+	cross := top + tickLen
+	count := int(math.Floor(float64((p.max-p.min)/p.ticks) + 1e-6))
+	size := gunit.Sp(theme.Current().Caption)
+	for i := 0; i <= count; i++ {
+		v := p.min + float32(i)*p.ticks
+		x := pos(v)
+		paint.FillShape(gtx.Ops, p.tickColor, clip.Rect(rect(x-tickWidth/2, top, x-tickWidth/2+tickWidth, top+tickLen)).Op())
+		if !p.tickLabels {
+			continue
+		}
+		lgtx := gtx
+		lgtx.Constraints = glayout.Constraints{Max: image.Pt(1<<24, 1<<24)}
+		macro := op.Record(gtx.Ops)
+		dims := textpaint.Label{MaxLines: 1}.Layout(lgtx, theme.Shaper, gfont.Font{}, size, p.text(v), p.tickColor)
+		call := macro.Stop()
+		// 水平时数值居中于刻度下方，垂直时在刻度右侧
+		r := rect(x, top+tickLen+gap, x, top+tickLen+gap)
+		off := image.Pt(r.Min.X-dims.Size.X/2, r.Min.Y)
+		labelCross := dims.Size.Y
+		if p.axis == axis.Vertical {
+			off = image.Pt(r.Min.X, r.Min.Y-dims.Size.Y/2)
+			labelCross = dims.Size.X
+		}
+		stack := op.Offset(off).Push(gtx.Ops)
+		call.Add(gtx.Ops)
+		stack.Pop()
+		cross = maxInt(cross, top+tickLen+gap+labelCross)
+	}
+	return cross
+}
+
+// 在圆点外侧绘制数值提示，thumb为圆点所在的区域
+func (p *sliderTrack) layoutTooltip(gtx glayout.Context, thumb image.Rectangle) {
+	th := theme.Current()
+	padding, gap := gtx.Dp(6), gtx.Dp(4)
+	lgtx := gtx
+	lgtx.Constraints = glayout.Constraints{Max: image.Pt(1<<24, 1<<24)}
+	macro := op.Record(gtx.Ops)
+	dims := textpaint.Label{MaxLines: 1}.Layout(lgtx, theme.Shaper, gfont.Font{}, gunit.Sp(th.Caption), p.text(p.values[p.active]), p.tooltipTextColor)
+	call := macro.Stop()
+	size := dims.Size.Add(image.Pt(padding*2, padding))
+	// 水平时在圆点上方，垂直时在圆点左侧
+	off := image.Pt((thumb.Min.X+thumb.Max.X-size.X)/2, thumb.Min.Y-gap-size.Y)
+	if p.axis == axis.Vertical {
+		off = image.Pt(thumb.Min.X-gap-size.X, (thumb.Min.Y+thumb.Max.Y-size.Y)/2)
+	}
+	macro = op.Record(gtx.Ops)
+	op.Offset(off).Add(gtx.Ops)
+	paint.FillShape(gtx.Ops, p.tooltipColor, clip.UniformRRect(image.Rectangle{Max: size}, gtx.Dp(4)).Op(gtx.Ops))
+	op.Offset(image.Pt(padding, padding/2)).Add(gtx.Ops)
+	call.Add(gtx.Ops)
+	// 提示绘制在其他组件之上
+	op.Defer(gtx.Ops, macro.Stop())
+}
+
+// 应用主题颜色，手动设置过的颜色不会被覆盖
+func (p *sliderTrack) applyTheme(th *theme.Theme, o overrides) {
+	if !o.has(overrideMarkColor) {
+		p.color = th.Primary
+	}
+	if !o.has(overrideTrackColor) {
+		p.trackColor = withAlpha(th.Primary, 0x60)
+	}
+	p.tickColor = th.TextSecondary
+	p.tooltipColor = th.Primary
+	p.tooltipTextColor = th.OnPrimary
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -5,31 +5,36 @@ import (
 
 	glayout "github.com/Seikaijyu/gio/layout"
 	gunit "github.com/Seikaijyu/gio/unit"
-	"github.com/Seikaijyu/gio/widget"
-	gmaterial "github.com/Seikaijyu/gio/widget/material"
 	"github.com/Seikaijyu/nenki.ui/widget/axis"
-	"github.com/Seikaijyu/nenki.ui/widget/theme"
 )
 
 // 校验接口是否实现
 var _ WidgetInterface = &Slider{}
 
 type sliderConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
 	// 是否更新组件
 	update bool
 	// 删除事件
 	_destroy func()
 	// 拖动事件
 	_dragging func(*Slider, float32)
+	// 值改变事件，松开滑块或使用键盘修改后触发
+	_changed func(*Slider, float32)
 }
 
 type Slider struct {
+	// 组件标识
+	identity
 	// 配置
 	config *sliderConfig
 	// 外边距
 	margin *glayout.Inset
-	// 滑块组件
-	slider *gmaterial.SliderStyle
+	// 轨道
+	track sliderTrack
 }
 
 // 绑定函数
@@ -38,6 +43,23 @@ func (p *Slider) Then(fn func(self *Slider)) *Slider {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *Slider) ID(id string) *Slider {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Slider) Class(classes ...string) *Slider {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Slider) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *Slider) Destroy() {
 	p.config.update = false
@@ -52,17 +74,34 @@ func (p *Slider) OnDestroy(fn func()) {
 	p.config._destroy = fn
 }
 
-// 注册拖动事件
+// 注册拖动事件，拖动过程中值改变时触发
 func (p *Slider) OnDragging(fn func(p *Slider, value float32)) *Slider {
 	p.config._dragging = fn
 	return p
 }
 
+// 注册值改变事件，松开滑块或使用键盘修改后触发
+func (p *Slider) OnChanged(fn func(p *Slider, value float32)) *Slider {
+	p.config._changed = fn
+	return p
+}
+
 // 是否更新组件
 func (p *Slider) Update(update bool) {
 	p.config.update = update
 }
 
+// 是否禁用组件，禁用时不响应任何输入
+func (p *Slider) Disabled(disabled bool) *Slider {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *Slider) GetState() State {
+	return stateOf(false, p.track.focused, p.config.disabled, false)
+}
+
 // 外边距
 func (p *Slider) Margin(Top, Left, Bottom, Right float32) *Slider {
 	p.margin.Top = gunit.Dp(Top)
@@ -72,43 +111,121 @@ func (p *Slider) Margin(Top, Left, Bottom, Right float32) *Slider {
 	return p
 }
 
+// 获取外边距
+func (p *Slider) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 滑块的颜色
 func (p *Slider) Color(r, g, b, a uint8) *Slider {
-	p.slider.Color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.track.color = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
+	return p
+}
+
+// 轨道的颜色
+func (p *Slider) TrackColor(r, g, b, a uint8) *Slider {
+	p.track.trackColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideTrackColor)
 	return p
 }
 
 // 滑块可点击的范围
 func (p *Slider) FingerSize(size float32) *Slider {
-	p.slider.FingerSize = gunit.Dp(size)
+	p.track.fingerSize = gunit.Dp(size)
+	return p
+}
+
+// 设置取值范围，默认为0-1
+func (p *Slider) Range(min, max float32) *Slider {
+	p.track.setRange(min, max)
+	return p
+}
+
+// 设置步长，值会吸附到最小值加上步长的整数倍，为0时不吸附
+func (p *Slider) Step(step float32) *Slider {
+	p.track.step = abs32(step)
+	p.track.setRange(p.track.min, p.track.max)
+	return p
+}
+
+// 设置滑块的值，超出范围时修正到范围内，不会触发事件
+func (p *Slider) Value(value float32) *Slider {
+	p.track.setValue(0, value)
 	return p
 }
 
 // 获取滑块的值
 func (p *Slider) GetValue() float32 {
-	return p.slider.Float.Value
+	return p.track.values[0]
+}
+
+// 设置刻度间隔，从最小值开始每隔interval绘制一个刻度，为0时不显示刻度
+func (p *Slider) Ticks(interval float32) *Slider {
+	p.track.ticks = abs32(interval)
+	return p
+}
+
+// 是否在刻度旁显示数值
+func (p *Slider) TickLabels(show bool) *Slider {
+	p.track.tickLabels = show
+	return p
+}
+
+// 拖动时是否显示数值提示，默认显示
+func (p *Slider) Tooltip(show bool) *Slider {
+	p.track.tooltip = show
+	return p
+}
+
+// 设置刻度和数值提示的格式化函数，为nil时使用默认格式
+func (p *Slider) LabelFormat(fn func(value float32) string) *Slider {
+	p.track.format = fn
+	return p
+}
+
+// 请求键盘焦点，获得焦点后可以使用方向键、PageUp、PageDown、Home和End调整数值
+func (p *Slider) Focus() *Slider {
+	p.track.requestFocus = true
+	return p
 }
 
 // 渲染组件
 func (p *Slider) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
-		p.config.update = false
+		return glayout.Dimensions{}
+	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	changed, committed := p.track.update(gtx, p)
+	if changed && p.track.dragging && p.config._dragging != nil {
+		p.config._dragging(p, p.GetValue())
 	}
-	if p.config._dragging != nil && p.slider.Float.Dragging() {
-		p.config._dragging(p, p.slider.Float.Value)
+	if committed && p.config._changed != nil {
+		p.config._changed(p, p.GetValue())
 	}
 	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
-		return p.slider.Layout(gtx)
+		return p.track.layout(gtx, p)
 	})
 }
 
-// 创建滑块组件，值为0-1
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *Slider) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	p.track.applyTheme(th, p.config.themed.overrides)
+}
+
+// 创建滑块组件，默认值为0-1
 func NewSlider(axis axis.Axis) *Slider {
-	slider := gmaterial.Slider(theme.NewTheme(), &widget.Float{})
-	slider.Axis = axis
 	return &Slider{
-		slider: &slider,
+		track:  newSliderTrack(axis, 1),
 		margin: &glayout.Inset{},
-		config: &sliderConfig{},
+		config: &sliderConfig{update: true, themed: newThemed()},
 	}
 }
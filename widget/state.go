@@ -0,0 +1,45 @@
+package widget
+
+// 组件的交互状态，多个状态可以同时存在
+type State uint8
+
+const (
+	// 鼠标悬浮
+	StateHovered State = 1 << iota
+	// 获得焦点
+	StateFocused
+	// 禁用
+	StateDisabled
+	// 选中
+	StateChecked
+)
+
+// 是否包含指定状态
+func (s State) Has(state State) bool {
+	return s&state == state
+}
+
+// 可以获取交互状态的组件
+type StatefulInterface interface {
+	WidgetInterface
+	// 获取组件当前的交互状态
+	GetState() State
+}
+
+// 根据条件组合状态
+func stateOf(hovered, focused, disabled, checked bool) State {
+	var state State
+	if hovered {
+		state |= StateHovered
+	}
+	if focused {
+		state |= StateFocused
+	}
+	if disabled {
+		state |= StateDisabled
+	}
+	if checked {
+		state |= StateChecked
+	}
+	return state
+}
@@ -0,0 +1,69 @@
+package style
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+	"github.com/Seikaijyu/nenki.ui/widget/internal/setter"
+)
+
+// 样式表设置的一个属性，规则不再匹配时用于恢复
+type change struct {
+	// 设置函数
+	set reflect.Value
+	// 获取函数
+	get reflect.Value
+	// 设置之前的值
+	before []reflect.Value
+	// 样式表设置的值
+	after []reflect.Value
+	// 设置属性时新增的手动设置标记，恢复后清除以便属性重新跟随主题
+	overrides uint32
+}
+
+// 设置组件的属性，并通过属性的获取函数记录设置之前的值
+//
+// 组件不支持属性时返回false，属性没有获取函数时不能恢复，返回错误并且不设置
+func set(w widget.WidgetInterface, name, value string) (change, bool, error) {
+	method, err := setter.Method(w, name)
+	if err != nil {
+		return change{}, false, nil
+	}
+	get, ok := setter.Getter(w, name)
+	if !ok {
+		return change{}, true, fmt.Errorf("属性%s没有对应的获取函数，不能在样式表中使用", name)
+	}
+	args, err := setter.Args(method.Type(), value)
+	if err != nil {
+		return change{}, true, fmt.Errorf("属性%s: %w", name, err)
+	}
+	overrides := widget.ThemeOverrides(w)
+	c := change{set: method, get: get, before: get.Call(nil)}
+	method.Call(args)
+	c.after = get.Call(nil)
+	c.overrides = widget.ThemeOverrides(w) &^ overrides
+	return c, true, nil
+}
+
+// 按设置的相反顺序恢复属性，属性在设置后又被程序修改时保留程序修改的值
+func undo(w widget.WidgetInterface, changes []change) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		if !equal(c.get.Call(nil), c.after) {
+			continue
+		}
+		c.set.Call(c.before)
+		widget.ResetThemeOverrides(w, c.overrides)
+	}
+}
+
+// 两组属性值是否相同
+func equal(a, b []reflect.Value) bool {
+	for i := range a {
+		if a[i].Interface() != b[i].Interface() {
+			return false
+		}
+	}
+	return true
+}
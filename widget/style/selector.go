@@ -0,0 +1,164 @@
+package style
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 状态名对应的状态
+var states = map[string]widget.State{
+	"hovered":  widget.StateHovered,
+	"focused":  widget.StateFocused,
+	"disabled": widget.StateDisabled,
+	"checked":  widget.StateChecked,
+}
+
+// 复合选择器，例如 Button#ok.primary:hovered
+type compound struct {
+	// 组件类型名，为空时匹配所有类型
+	typeName string
+	// 组件ID
+	id string
+	// 组件类名
+	classes []string
+	// 组件状态
+	state widget.State
+}
+
+// 选择器，由空格分隔的多个复合选择器组成，表示后代关系
+type selector struct {
+	parts []compound
+	// 优先级，ID数量、类名和状态数量、类型数量依次比较
+	specificity [3]int
+}
+
+// 解析选择器
+func parseSelector(text string) (selector, error) {
+	var sel selector
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return sel, fmt.Errorf("选择器不能为空")
+	}
+	for _, field := range fields {
+		part, err := parseCompound(field)
+		if err != nil {
+			return sel, err
+		}
+		if part.id != "" {
+			sel.specificity[0]++
+		}
+		sel.specificity[1] += len(part.classes)
+		for s := part.state; s != 0; s &= s - 1 {
+			sel.specificity[1]++
+		}
+		if part.typeName != "" {
+			sel.specificity[2]++
+		}
+		sel.parts = append(sel.parts, part)
+	}
+	return sel, nil
+}
+
+// 解析复合选择器
+func parseCompound(text string) (compound, error) {
+	var part compound
+	// 读取一个名字
+	name := func(i int) (string, int) {
+		j := i
+		for j < len(text) && (unicode.IsLetter(rune(text[j])) || unicode.IsDigit(rune(text[j])) || text[j] == '-' || text[j] == '_' || text[j] >= 0x80) {
+			j++
+		}
+		return text[i:j], j
+	}
+	i := 0
+	if strings.HasPrefix(text, "*") {
+		i = 1
+	} else {
+		part.typeName, i = name(0)
+	}
+	for i < len(text) {
+		prefix := text[i]
+		value, next := name(i + 1)
+		if value == "" {
+			return part, fmt.Errorf("无效的选择器%q", text)
+		}
+		switch prefix {
+		case '#':
+			part.id = value
+		case '.':
+			part.classes = append(part.classes, value)
+		case ':':
+			state, ok := states[value]
+			if !ok {
+				return part, fmt.Errorf("未知的状态%q", value)
+			}
+			part.state |= state
+		default:
+			return part, fmt.Errorf("无效的选择器%q", text)
+		}
+		i = next
+	}
+	return part, nil
+}
+
+// 复合选择器是否匹配组件
+func (p compound) match(w widget.WidgetInterface, state widget.State) bool {
+	if p.typeName != "" && typeName(w) != p.typeName {
+		return false
+	}
+	if p.id != "" && widget.IDOf(w) != p.id {
+		return false
+	}
+	for _, class := range p.classes {
+		if !hasClass(w, class) {
+			return false
+		}
+	}
+	return state.Has(p.state)
+}
+
+// 选择器是否匹配组件，前面的复合选择器需要依次匹配组件的祖先节点
+func (p selector) match(w widget.WidgetInterface, state widget.State) bool {
+	last := len(p.parts) - 1
+	if !p.parts[last].match(w, state) {
+		return false
+	}
+	i := last - 1
+	for parent := widget.ParentOf(w); parent != nil && i >= 0; parent = widget.ParentOf(parent) {
+		if p.parts[i].match(parent, stateOf(parent)) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+// 获取组件类型名
+func typeName(w widget.WidgetInterface) string {
+	t := reflect.TypeOf(w)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// 组件是否包含类名
+func hasClass(w widget.WidgetInterface, class string) bool {
+	for _, c := range widget.ClassesOf(w) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// 获取组件状态，不支持状态的组件返回0
+func stateOf(w widget.WidgetInterface) widget.State {
+	if stateful, ok := w.(widget.StatefulInterface); ok {
+		return stateful.GetState()
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package style
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		text        string
+		parts       []compound
+		specificity [3]int
+		err         bool
+	}{
+		{text: "Button", parts: []compound{{typeName: "Button"}}, specificity: [3]int{0, 0, 1}},
+		{text: "*", parts: []compound{{}}},
+		{text: ".primary", parts: []compound{{classes: []string{"primary"}}}, specificity: [3]int{0, 1, 0}},
+		{text: "#ok", parts: []compound{{id: "ok"}}, specificity: [3]int{1, 0, 0}},
+		{
+			text:        "Button#ok.primary.big:hovered:focused",
+			parts:       []compound{{typeName: "Button", id: "ok", classes: []string{"primary", "big"}, state: widget.StateHovered | widget.StateFocused}},
+			specificity: [3]int{1, 4, 1},
+		},
+		{
+			text:        "  ColumnLayout   .panel Button:checked ",
+			parts:       []compound{{typeName: "ColumnLayout"}, {classes: []string{"panel"}}, {typeName: "Button", state: widget.StateChecked}},
+			specificity: [3]int{0, 2, 2},
+		},
+		{text: "*.side-bar_2", parts: []compound{{classes: []string{"side-bar_2"}}}, specificity: [3]int{0, 1, 0}},
+		{text: ".标题", parts: []compound{{classes: []string{"标题"}}}, specificity: [3]int{0, 1, 0}},
+		{text: "", err: true},
+		{text: "   ", err: true},
+		{text: "Button.", err: true},
+		{text: "Button#", err: true},
+		{text: "Button:pressed", err: true},
+		{text: "Button>Label", err: true},
+		{text: "Button[x]", err: true},
+	}
+	for _, tt := range tests {
+		sel, err := parseSelector(tt.text)
+		if tt.err {
+			if err == nil {
+				t.Errorf("parseSelector(%q) 应该返回错误", tt.text)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSelector(%q): %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(sel.parts, tt.parts) || sel.specificity != tt.specificity {
+			t.Errorf("parseSelector(%q) = %+v %v, want %+v %v", tt.text, sel.parts, sel.specificity, tt.parts, tt.specificity)
+		}
+	}
+}
+
+func TestSelectorMatch(t *testing.T) {
+	button := widget.NewButton("ok").ID("ok").Class("primary", "big")
+	label := widget.NewLabel("title").Class("title")
+	row := widget.NewRowLayout().Class("toolbar")
+	row.AppendRigidChild(button)
+	root := widget.NewColumnLayout().ID("root").Class("panel")
+	root.AppendRigidChild(label)
+	root.AppendRigidChild(row)
+
+	tests := []struct {
+		selector string
+		w        widget.WidgetInterface
+		state    widget.State
+		want     bool
+	}{
+		{"Button", button, 0, true},
+		{"Label", button, 0, false},
+		{"*", label, 0, true},
+		{"#ok", button, 0, true},
+		{"#root", button, 0, false},
+		{".primary", button, 0, true},
+		{".primary.big", button, 0, true},
+		{".primary.small", button, 0, false},
+		{"Button.title", label, 0, false},
+		{"Button:hovered", button, 0, false},
+		{"Button:hovered", button, widget.StateHovered, true},
+		{"Button:hovered", button, widget.StateHovered | widget.StateFocused, true},
+		{"Button:hovered:focused", button, widget.StateHovered, false},
+		// 后代选择器依次匹配祖先节点，不要求是直接的父节点
+		{"RowLayout Button", button, 0, true},
+		{"#root Button", button, 0, true},
+		{".panel .toolbar #ok", button, 0, true},
+		{".toolbar .panel #ok", button, 0, false},
+		{"RowLayout Label", label, 0, false},
+		{"#root Label.title", label, 0, true},
+		{"Button Button", button, 0, false},
+	}
+	for _, tt := range tests {
+		sel, err := parseSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("parseSelector(%q): %v", tt.selector, err)
+		}
+		if got := sel.match(tt.w, tt.state); got != tt.want {
+			t.Errorf("%q.match(%s, %v) = %v, want %v", tt.selector, typeName(tt.w), tt.state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// 样式表
+// 通过选择器（组件类型、ID、类名、状态）匹配组件，并使用与链式设置函数同名的属性设置组件外观
+package style
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 样式属性，名字为组件的设置函数名，值为设置函数的参数
+//
+// 多个参数使用逗号分隔，例如 Margin: 5,5,0,0；颜色可以直接使用16进制字符串、CSS函数或颜色名称，例如 FontColor: #ff0000、rgb(255, 0, 0)或red
+type Declaration struct {
+	Name  string
+	Value string
+}
+
+// 创建样式属性
+func Prop(name, value string) Declaration {
+	return Declaration{Name: name, Value: value}
+}
+
+// 样式规则
+type rule struct {
+	selector     selector
+	declarations []Declaration
+	// 添加顺序，优先级相同时后添加的规则优先
+	order int
+}
+
+// 组件已经应用的样式
+type applied struct {
+	// 样式表版本
+	version int
+	// 组件状态
+	state widget.State
+	// 父节点，父节点改变时后代选择器的匹配结果可能改变
+	parent widget.WidgetInterface
+	// 最后一次遍历的代数，用于清理已经不在组件树中的组件
+	generation int
+	// 样式表设置的属性，规则不再匹配时用于恢复
+	changes []change
+}
+
+// 样式表
+type StyleSheet struct {
+	mutex sync.Mutex
+	rules []*rule
+	// 每次修改规则时增加，用于判断组件是否需要重新应用样式
+	version int
+	// 遍历代数
+	generation int
+	// 组件已经应用的样式
+	cache map[widget.WidgetInterface]*applied
+	// 样式表修改事件
+	_change func()
+	// 属性设置错误事件
+	_error func(error)
+}
+
+// 创建空的样式表
+func NewStyleSheet() *StyleSheet {
+	return &StyleSheet{cache: map[widget.WidgetInterface]*applied{}}
+}
+
+// 从文本中解析样式表
+//
+// 格式为：
+//
+//	Button, CheckBox { FontColor: #333333 }
+//	Button.primary:hovered { Background: #3f51b5; FontColor: #ffffff }
+//	ColumnLayout #title { FontSize: 24 }
+func Parse(text string) (*StyleSheet, error) {
+	sheet := NewStyleSheet()
+	if err := sheet.Append(text); err != nil {
+		return nil, err
+	}
+	return sheet, nil
+}
+
+// 绑定函数
+func (p *StyleSheet) Then(fn func(self *StyleSheet)) *StyleSheet {
+	fn(p)
+	return p
+}
+
+// 添加规则，选择器可以使用逗号分隔多个
+//
+// 如果选择器无效则会panic
+func (p *StyleSheet) Rule(selectors string, declarations ...Declaration) *StyleSheet {
+	if err := p.addRule(selectors, declarations); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// 从文本中解析规则并添加到样式表
+func (p *StyleSheet) Append(text string) error {
+	text = stripComments(text)
+	line := 1
+	for {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			if strings.TrimSpace(text) != "" {
+				return fmt.Errorf("第%d行: 缺少{", line+leadingLines(text))
+			}
+			return nil
+		}
+		end := strings.IndexByte(text[open:], '}')
+		if end < 0 {
+			return fmt.Errorf("第%d行: 缺少}", line+strings.Count(text[:open], "\n"))
+		}
+		end += open
+		ruleLine := line + leadingLines(text)
+		var declarations []Declaration
+		for _, item := range strings.Split(text[open+1:end], ";") {
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			name, value, ok := strings.Cut(item, ":")
+			if !ok {
+				return fmt.Errorf("第%d行: 无效的属性%q", line+strings.Count(text[:open], "\n")+leadingLines(item), strings.TrimSpace(item))
+			}
+			declarations = append(declarations, Prop(strings.TrimSpace(name), strings.TrimSpace(value)))
+		}
+		if err := p.addRule(text[:open], declarations); err != nil {
+			return fmt.Errorf("第%d行: %w", ruleLine, err)
+		}
+		line += strings.Count(text[:end+1], "\n")
+		text = text[end+1:]
+	}
+}
+
+// 删除所有规则
+func (p *StyleSheet) Clear() *StyleSheet {
+	p.mutex.Lock()
+	p.rules = nil
+	p.version++
+	p.mutex.Unlock()
+	p.changed()
+	return p
+}
+
+// 样式表修改事件，由App用于在修改规则后立即重新渲染
+func (p *StyleSheet) OnChange(fn func()) *StyleSheet {
+	p._change = fn
+	return p
+}
+
+// 属性设置错误事件，组件不支持的属性会被忽略，属性值无效或者属性没有对应的获取函数时触发
+func (p *StyleSheet) OnError(fn func(err error)) *StyleSheet {
+	p._error = fn
+	return p
+}
+
+// 添加规则
+func (p *StyleSheet) addRule(selectors string, declarations []Declaration) error {
+	var parsed []selector
+	for _, text := range strings.Split(selectors, ",") {
+		sel, err := parseSelector(text)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, sel)
+	}
+	p.mutex.Lock()
+	for _, sel := range parsed {
+		p.rules = append(p.rules, &rule{selector: sel, declarations: declarations, order: len(p.rules)})
+	}
+	p.version++
+	p.mutex.Unlock()
+	p.changed()
+	return nil
+}
+
+// 触发修改事件
+func (p *StyleSheet) changed() {
+	if p._change != nil {
+		p._change()
+	}
+}
+
+// 将样式应用到组件树，只有样式表、组件状态或者父节点改变时才会重新设置组件，返回是否有组件被重新设置
+//
+// 只能使用组件同时提供获取函数（Get加属性名，例如GetFontColor）的属性，设置前通过获取函数记录原来的值，
+// 重新设置组件时会先恢复样式表之前设置的属性，因此状态消失或者规则被删除后，属性会恢复到样式表设置之前的值，
+// 原来跟随主题的属性恢复后继续跟随主题；属性在样式表设置之后又被程序修改时保留程序修改的值
+func (p *StyleSheet) Apply(root widget.WidgetInterface) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.generation++
+	seen := 0
+	changed := false
+	widget.Walk(root, func(w widget.WidgetInterface) bool {
+		seen++
+		state := stateOf(w)
+		last, ok := p.cache[w]
+		if !ok {
+			last = &applied{version: -1}
+			p.cache[w] = last
+		}
+		last.generation = p.generation
+		if last.version == p.version && last.state == state && last.parent == widget.ParentOf(w) {
+			return true
+		}
+		last.version, last.state, last.parent = p.version, state, widget.ParentOf(w)
+		changed = p.applyTo(w, state, last) || changed
+		return true
+	})
+	// 清理已经不在组件树中的组件
+	if len(p.cache) > seen*2+64 {
+		for w, last := range p.cache {
+			if last.generation != p.generation {
+				delete(p.cache, w)
+			}
+		}
+	}
+	return changed
+}
+
+// 组件树中是否有组件需要重新应用样式，用于在渲染改变了组件状态后判断是否需要再渲染一帧
+func (p *StyleSheet) Outdated(root widget.WidgetInterface) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	outdated := false
+	widget.Walk(root, func(w widget.WidgetInterface) bool {
+		last, ok := p.cache[w]
+		outdated = !ok || last.version != p.version || last.state != stateOf(w) || last.parent != widget.ParentOf(w)
+		return !outdated
+	})
+	return outdated
+}
+
+// 撤销样式表对所有组件设置的属性，用于替换或者停止使用样式表
+//
+// 必须在渲染组件的goroutine中调用，撤销后样式表可以重新应用
+func (p *StyleSheet) Revert() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	changed := false
+	for w, last := range p.cache {
+		changed = changed || len(last.changes) > 0
+		undo(w, last.changes)
+		delete(p.cache, w)
+	}
+	return changed
+}
+
+// 恢复组件之前设置的属性，并将匹配的规则应用到组件，返回组件是否被修改
+func (p *StyleSheet) applyTo(w widget.WidgetInterface, state widget.State, last *applied) bool {
+	changed := len(last.changes) > 0
+	undo(w, last.changes)
+	last.changes = nil
+	var matched []*rule
+	for _, r := range p.rules {
+		if r.selector.match(w, state) {
+			matched = append(matched, r)
+		}
+	}
+	if len(matched) == 0 {
+		return changed
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.selector.specificity != b.selector.specificity {
+			return less(a.selector.specificity, b.selector.specificity)
+		}
+		return a.order < b.order
+	})
+	// 合并同名属性，优先级高的规则覆盖优先级低的规则，但保持属性第一次出现的顺序
+	var names []string
+	values := map[string]string{}
+	for _, r := range matched {
+		for _, d := range r.declarations {
+			if _, ok := values[d.Name]; !ok {
+				names = append(names, d.Name)
+			}
+			values[d.Name] = d.Value
+		}
+	}
+	for _, name := range names {
+		c, ok, err := set(w, name, values[name])
+		if err != nil {
+			if p._error != nil {
+				p._error(fmt.Errorf("%s: %w", typeName(w), err))
+			}
+			continue
+		}
+		// 组件不支持的属性直接忽略
+		if !ok {
+			continue
+		}
+		last.changes = append(last.changes, c)
+		changed = true
+	}
+	return changed
+}
+
+// 比较优先级
+func less(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// 删除注释，保留换行以便计算行号
+func stripComments(text string) string {
+	var builder strings.Builder
+	for {
+		start := strings.Index(text, "/*")
+		if start < 0 {
+			builder.WriteString(text)
+			return builder.String()
+		}
+		builder.WriteString(text[:start])
+		end := strings.Index(text[start+2:], "*/")
+		if end < 0 {
+			builder.WriteString(strings.Repeat("\n", strings.Count(text[start:], "\n")))
+			return builder.String()
+		}
+		comment := text[start : start+2+end+2]
+		builder.WriteString(strings.Repeat("\n", strings.Count(comment, "\n")))
+		text = text[start+2+end+2:]
+	}
+}
+
+// 文本开头空白中的换行数
+func leadingLines(text string) int {
+	trimmed := strings.TrimLeftFunc(text, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '\r' })
+	return strings.Count(text[:len(text)-len(trimmed)], "\n")
+}
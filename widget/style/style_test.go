@@ -0,0 +1,80 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Seikaijyu/nenki.ui/widget"
+)
+
+// 优先级高的规则覆盖优先级低的规则，优先级相同时后添加的规则优先
+func TestApplyCascade(t *testing.T) {
+	tests := []struct {
+		sheet string
+		want  float32
+	}{
+		{"Label { FontSize: 10 } Label { FontSize: 12 }", 12},
+		{".title { FontSize: 14 } Label { FontSize: 12 }", 14},
+		{"#name { FontSize: 16 } Label.title { FontSize: 14 }", 16},
+		{"ColumnLayout Label { FontSize: 18 } Label { FontSize: 12 }", 18},
+		{"Label, Button { FontSize: 20 }", 20},
+		{"/* 注释 */ Label { FontSize: 22; }", 22},
+	}
+	for _, tt := range tests {
+		label := widget.NewLabel("x").ID("name").Class("title").FontSize(9)
+		root := widget.NewColumnLayout()
+		root.AppendRigidChild(label)
+		sheet, err := Parse(tt.sheet)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.sheet, err)
+		}
+		sheet.Apply(root)
+		if got := label.GetFontSize(); got != tt.want {
+			t.Errorf("%q: FontSize = %v, want %v", tt.sheet, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		sheet string
+		err   string
+	}{
+		{"Label FontSize: 10 }", "第1行"},
+		{"Label {\n FontSize: 10", "第1行: 缺少}"},
+		{"Label { FontSize: 10 }\n\nButton { Margin }", "第3行"},
+		{"Label:pressed { FontSize: 10 }", "未知的状态"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.sheet)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) = %v, want error containing %q", tt.sheet, err, tt.err)
+		}
+	}
+}
+
+// 撤销时恢复设置之前的值，程序在样式表之后修改的值保留
+func TestRevert(t *testing.T) {
+	label := widget.NewLabel("x").FontSize(9).Margin(1, 2, 3, 4)
+	root := widget.NewColumnLayout()
+	root.AppendRigidChild(label)
+	var errs []error
+	sheet := NewStyleSheet().OnError(func(err error) { errs = append(errs, err) })
+	sheet.Rule("Label", Prop("FontSize", "20"), Prop("Margin", "5"), Prop("Text", "y"), Prop("Unknown", "1"))
+	sheet.Apply(root)
+	if got := label.GetFontSize(); got != 20 {
+		t.Fatalf("FontSize = %v, want 20", got)
+	}
+	// 没有获取函数的属性不能恢复，不会被设置
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Text") {
+		t.Errorf("errors = %v", errs)
+	}
+	label.Margin(7, 7, 7, 7)
+	sheet.Revert()
+	if got := label.GetFontSize(); got != 9 {
+		t.Errorf("FontSize = %v, want 9", got)
+	}
+	if top, left, bottom, right := label.GetMargin(); top != 7 || left != 7 || bottom != 7 || right != 7 {
+		t.Errorf("Margin = %v, %v, %v, %v, want 7", top, left, bottom, right)
+	}
+}
@@ -14,6 +14,10 @@ import (
 var _ WidgetInterface = &Switch{}
 
 type switchConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled  bool
 	prevValue bool
 	// 是否更新组件
 	update bool
@@ -24,6 +28,8 @@ type switchConfig struct {
 }
 
 type Switch struct {
+	// 组件标识
+	identity
 	// 配置
 	config *switchConfig
 	// 外边距
@@ -38,6 +44,23 @@ func (p *Switch) Then(fn func(self *Switch)) *Switch {
 	return p
 }
 
+// 设置组件ID，用于在组件树中查找
+func (p *Switch) ID(id string) *Switch {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *Switch) Class(classes ...string) *Switch {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *Switch) themeState() *themed {
+	return &p.config.themed
+}
+
 // 注销自身，清理所有引用
 func (p *Switch) Destroy() {
 	p.config.update = false
@@ -57,6 +80,17 @@ func (p *Switch) Update(update bool) {
 	p.config.update = update
 }
 
+// 是否禁用组件，禁用时不响应任何输入
+func (p *Switch) Disabled(disabled bool) *Switch {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *Switch) GetState() State {
+	return stateOf(p.switchWidget.Switch.Hovered(), p.switchWidget.Switch.Focused(), p.config.disabled, p.switchWidget.Switch.Value)
+}
+
 // 外边距
 func (p *Switch) Margin(Top, Left, Bottom, Right float32) *Switch {
 	p.margin.Top = gunit.Dp(Top)
@@ -66,6 +100,11 @@ func (p *Switch) Margin(Top, Left, Bottom, Right float32) *Switch {
 	return p
 }
 
+// 获取外边距
+func (p *Switch) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
 // 选择事件
 func (p *Switch) OnChange(fn func(p *Switch, value bool)) *Switch {
 	p.config._change = fn
@@ -75,18 +114,21 @@ func (p *Switch) OnChange(fn func(p *Switch, value bool)) *Switch {
 // 启用颜色
 func (p *Switch) EnabledColor(r, g, b, a uint8) *Switch {
 	p.switchWidget.Color.Enabled = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
 	return p
 }
 
 // 禁用颜色
 func (p *Switch) DisabledColor(r, g, b, a uint8) *Switch {
 	p.switchWidget.Color.Disabled = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideDisabledColor)
 	return p
 }
 
 // 轨道颜色
 func (p *Switch) TrackColor(r, g, b, a uint8) *Switch {
 	p.switchWidget.Color.Track = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideTrackColor)
 	return p
 }
 
@@ -101,6 +143,11 @@ func (p *Switch) Layout(gtx glayout.Context) glayout.Dimensions {
 	if !p.config.update {
 		p.config.update = false
 	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
 	if p.config._change != nil && p.config.prevValue != p.switchWidget.Switch.Value {
 		p.config._change(p, p.switchWidget.Switch.Value)
 	}
@@ -111,11 +158,29 @@ func (p *Switch) Layout(gtx glayout.Context) glayout.Dimensions {
 	})
 }
 
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *Switch) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	if !p.config.themed.overrides.has(overrideMarkColor) {
+		p.switchWidget.Color.Enabled = th.Primary
+	}
+	if !p.config.themed.overrides.has(overrideDisabledColor) {
+		p.switchWidget.Color.Disabled = th.Surface
+	}
+	if !p.config.themed.overrides.has(overrideTrackColor) {
+		p.switchWidget.Color.Track = withAlpha(th.Text, 0x88)
+	}
+}
+
+// 创建开关
 func NewSwitch() *Switch {
-	switchWidget := gmaterial.Switch(theme.NewTheme(), &gwidget.Bool{}, "")
+	switchWidget := gmaterial.Switch(theme.Material(), &gwidget.Bool{}, "")
 	return &Switch{
 		switchWidget: &switchWidget,
 		margin:       &glayout.Inset{},
-		config:       &switchConfig{},
+		config:       &switchConfig{themed: newThemed()},
 	}
 }
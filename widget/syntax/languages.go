@@ -0,0 +1,47 @@
+package syntax
+
+// JSON词法分析器，对象的键作为属性名
+func JSON() Tokenizer {
+	return &lexer{
+		quotes:     `"`,
+		literals:   words("true false null"),
+		properties: true,
+	}
+}
+
+// Go语言词法分析器
+func Go() Tokenizer {
+	return &lexer{
+		lineComments: []string{"//"},
+		blockStart:   "/*",
+		blockEnd:     "*/",
+		quotes:       "\"'`",
+		multiline:    "`",
+		operators:    "+-*/%&|^<>=!~:",
+		keywords: words(`break case chan const continue default defer else fallthrough for func go goto if
+			import interface map package range return select struct switch type var`),
+		types: words(`any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32
+			int64 rune string uint uint8 uint16 uint32 uint64 uintptr`),
+		literals: words("true false nil iota"),
+	}
+}
+
+// SQL词法分析器，关键字忽略大小写
+func SQL() Tokenizer {
+	return &lexer{
+		lineComments: []string{"--"},
+		blockStart:   "/*",
+		blockEnd:     "*/",
+		quotes:       "'\"`",
+		operators:    "+-*/%&|^<>=!~",
+		keywords: words(`add all alter and any as asc autoincrement begin between by case cascade check column
+			commit constraint create cross database default delete desc distinct drop else end exists foreign
+			from full group having if in index inner insert intersect into is join key left like limit not
+			offset on or order outer primary references replace right rollback select set table then
+			transaction trigger truncate union unique update using values view when where with`),
+		types: words(`bigint binary bit blob boolean char date datetime decimal double float int integer json
+			numeric real smallint text time timestamp tinyint varchar`),
+		literals:   words("true false null"),
+		ignoreCase: true,
+	}
+}
@@ -0,0 +1,345 @@
+// 语法高亮
+// 按行将代码分析为带有类型的记号，供代码编辑框着色
+package syntax
+
+import (
+	"image/color"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 记号类型
+type Kind uint8
+
+const (
+	// 普通文本
+	Plain Kind = iota
+	// 关键字
+	Keyword
+	// 内置类型
+	Type
+	// 字符串
+	String
+	// 数字
+	Number
+	// 注释
+	Comment
+	// 运算符
+	Operator
+	// 标点符号，例如括号和逗号
+	Punctuation
+	// 字面量，例如true、false和null
+	Literal
+	// 属性名，例如JSON对象的键
+	Property
+	// 函数调用
+	Function
+)
+
+// 记号，Start和End为行内的字节偏移
+type Token struct {
+	Kind       Kind
+	Start, End int
+}
+
+// 行结束时的分析状态，用于在行之间延续多行注释和多行字符串
+//
+// 0表示没有未结束的结构
+type State int
+
+// 词法分析器
+type Tokenizer interface {
+	// 分析一行文本，line不包含换行符，state为上一行结束时的状态
+	Tokenize(line string, state State) ([]Token, State)
+}
+
+// 使用函数实现的词法分析器
+type TokenizerFunc func(line string, state State) ([]Token, State)
+
+func (f TokenizerFunc) Tokenize(line string, state State) ([]Token, State) {
+	return f(line, state)
+}
+
+// 各类型记号的颜色，颜色透明度为0时使用普通文本的颜色
+type Colors struct {
+	Plain       color.NRGBA
+	Keyword     color.NRGBA
+	Type        color.NRGBA
+	String      color.NRGBA
+	Number      color.NRGBA
+	Comment     color.NRGBA
+	Operator    color.NRGBA
+	Punctuation color.NRGBA
+	Literal     color.NRGBA
+	Property    color.NRGBA
+	Function    color.NRGBA
+}
+
+// 获取记号类型对应的颜色
+func (c *Colors) Of(kind Kind) color.NRGBA {
+	var col color.NRGBA
+	switch kind {
+	case Keyword:
+		col = c.Keyword
+	case Type:
+		col = c.Type
+	case String:
+		col = c.String
+	case Number:
+		col = c.Number
+	case Comment:
+		col = c.Comment
+	case Operator:
+		col = c.Operator
+	case Punctuation:
+		col = c.Punctuation
+	case Literal:
+		col = c.Literal
+	case Property:
+		col = c.Property
+	case Function:
+		col = c.Function
+	}
+	if col.A == 0 {
+		return c.Plain
+	}
+	return col
+}
+
+// 亮色背景使用的配色，Plain为透明时使用编辑框的文字颜色
+func Light() *Colors {
+	return &Colors{
+		Keyword:  color.NRGBA{R: 0x00, G: 0x00, B: 0xc0, A: 0xff},
+		Type:     color.NRGBA{R: 0x26, G: 0x7f, B: 0x99, A: 0xff},
+		String:   color.NRGBA{R: 0xa3, G: 0x15, B: 0x15, A: 0xff},
+		Number:   color.NRGBA{R: 0x09, G: 0x86, B: 0x58, A: 0xff},
+		Comment:  color.NRGBA{R: 0x00, G: 0x80, B: 0x00, A: 0xff},
+		Operator: color.NRGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xff},
+		Literal:  color.NRGBA{R: 0x00, G: 0x00, B: 0xc0, A: 0xff},
+		Property: color.NRGBA{R: 0x04, G: 0x51, B: 0xa5, A: 0xff},
+		Function: color.NRGBA{R: 0x79, G: 0x5e, B: 0x26, A: 0xff},
+	}
+}
+
+// 暗色背景使用的配色，Plain为透明时使用编辑框的文字颜色
+func Dark() *Colors {
+	return &Colors{
+		Keyword:  color.NRGBA{R: 0x56, G: 0x9c, B: 0xd6, A: 0xff},
+		Type:     color.NRGBA{R: 0x4e, G: 0xc9, B: 0xb0, A: 0xff},
+		String:   color.NRGBA{R: 0xce, G: 0x91, B: 0x78, A: 0xff},
+		Number:   color.NRGBA{R: 0xb5, G: 0xce, B: 0xa8, A: 0xff},
+		Comment:  color.NRGBA{R: 0x6a, G: 0x99, B: 0x55, A: 0xff},
+		Operator: color.NRGBA{R: 0xd4, G: 0xd4, B: 0xd4, A: 0xff},
+		Literal:  color.NRGBA{R: 0x56, G: 0x9c, B: 0xd6, A: 0xff},
+		Property: color.NRGBA{R: 0x9c, G: 0xdc, B: 0xfe, A: 0xff},
+		Function: color.NRGBA{R: 0xdc, G: 0xdc, B: 0xaa, A: 0xff},
+	}
+}
+
+// 分析状态
+const (
+	// 在多行注释中
+	stateComment State = 1
+	// 在多行字符串中，加上引号在multiline中的位置
+	stateString State = 2
+)
+
+// 通用的类C语言词法分析器
+type lexer struct {
+	// 单行注释的开始符号
+	lineComments []string
+	// 多行注释的开始和结束符号
+	blockStart, blockEnd string
+	// 字符串的引号
+	quotes string
+	// 可以跨行且不处理转义的引号
+	multiline string
+	// 运算符字符
+	operators string
+	// 关键字、内置类型和字面量
+	keywords, types, literals map[string]bool
+	// 关键字是否忽略大小写，忽略时集合中的词必须是小写
+	ignoreCase bool
+	// 后面跟着冒号的字符串是否作为属性名
+	properties bool
+}
+
+// 创建词集合
+func words(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+func (l *lexer) Tokenize(line string, state State) ([]Token, State) {
+	var tokens []Token
+	i := 0
+	// 延续上一行未结束的结构
+	switch {
+	case state == stateComment:
+		end := strings.Index(line, l.blockEnd)
+		if end < 0 {
+			return []Token{{Kind: Comment, End: len(line)}}, state
+		}
+		i = end + len(l.blockEnd)
+		tokens = append(tokens, Token{Kind: Comment, End: i})
+	case state >= stateString && int(state-stateString) < len(l.multiline):
+		end := strings.IndexByte(line, l.multiline[state-stateString])
+		if end < 0 {
+			return []Token{{Kind: String, End: len(line)}}, state
+		}
+		i = end + 1
+		tokens = append(tokens, Token{Kind: String, End: i})
+	}
+	state = 0
+	for i < len(line) {
+		c := line[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case l.lineComment(line[i:]):
+			return append(tokens, Token{Kind: Comment, Start: i, End: len(line)}), state
+		case l.blockStart != "" && strings.HasPrefix(line[i:], l.blockStart):
+			end := strings.Index(line[i+len(l.blockStart):], l.blockEnd)
+			if end < 0 {
+				return append(tokens, Token{Kind: Comment, Start: i, End: len(line)}), stateComment
+			}
+			i += len(l.blockStart) + end + len(l.blockEnd)
+			tokens = append(tokens, Token{Kind: Comment, Start: start, End: i})
+		case strings.IndexByte(l.quotes, c) >= 0:
+			var closed bool
+			i, closed = l.scanString(line, i)
+			if !closed {
+				if q := strings.IndexByte(l.multiline, c); q >= 0 {
+					state = stateString + State(q)
+				}
+			}
+			kind := String
+			if l.properties && nextByte(line, i) == ':' {
+				kind = Property
+			}
+			tokens = append(tokens, Token{Kind: kind, Start: start, End: i})
+		case isDigit(c) || (c == '.' || c == '-') && i+1 < len(line) && isDigit(line[i+1]) && !l.afterValue(tokens, line, i):
+			i++
+			for i < len(line) {
+				b := line[i]
+				if isDigit(b) || isLetter(b) || b == '.' || b == '_' {
+					i++
+				} else if (b == '+' || b == '-') && (line[i-1] == 'e' || line[i-1] == 'E') {
+					i++
+				} else {
+					break
+				}
+			}
+			tokens = append(tokens, Token{Kind: Number, Start: start, End: i})
+		case isIdentStart(line[i:]):
+			for i < len(line) {
+				r, size := utf8.DecodeRuneInString(line[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			word := line[start:i]
+			if l.ignoreCase {
+				word = strings.ToLower(word)
+			}
+			switch {
+			case l.keywords[word]:
+				tokens = append(tokens, Token{Kind: Keyword, Start: start, End: i})
+			case l.types[word]:
+				tokens = append(tokens, Token{Kind: Type, Start: start, End: i})
+			case l.literals[word]:
+				tokens = append(tokens, Token{Kind: Literal, Start: start, End: i})
+			case nextByte(line, i) == '(':
+				tokens = append(tokens, Token{Kind: Function, Start: start, End: i})
+			}
+		case strings.IndexByte(l.operators, c) >= 0:
+			for i < len(line) && strings.IndexByte(l.operators, line[i]) >= 0 && !l.lineComment(line[i:]) &&
+				(l.blockStart == "" || !strings.HasPrefix(line[i:], l.blockStart)) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Operator, Start: start, End: i})
+		case strings.IndexByte("()[]{},;.:", c) >= 0:
+			i++
+			tokens = append(tokens, Token{Kind: Punctuation, Start: start, End: i})
+		default:
+			_, size := utf8.DecodeRuneInString(line[i:])
+			i += size
+		}
+	}
+	return tokens, state
+}
+
+// 是否为单行注释的开始
+func (l *lexer) lineComment(s string) bool {
+	for _, prefix := range l.lineComments {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 扫描从start开始的字符串，返回结束位置和字符串是否在本行结束
+func (l *lexer) scanString(line string, start int) (int, bool) {
+	quote := line[start]
+	raw := strings.IndexByte(l.multiline, quote) >= 0
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if !raw {
+				i++
+			}
+		case quote:
+			return i + 1, true
+		}
+	}
+	return len(line), false
+}
+
+// 减号或点号前面是否为值，此时它们是运算符而不是数字的一部分
+func (l *lexer) afterValue(tokens []Token, line string, i int) bool {
+	if line[i] == '.' {
+		return i > 0 && (isDigit(line[i-1]) || isLetter(line[i-1]) || line[i-1] == '_' || line[i-1] == ')')
+	}
+	j := i - 1
+	for j >= 0 && (line[j] == ' ' || line[j] == '\t') {
+		j--
+	}
+	if j < 0 {
+		return false
+	}
+	b := line[j]
+	return isDigit(b) || isLetter(b) || b == '_' || b == ')' || b == ']' || strings.IndexByte(l.quotes, b) >= 0
+}
+
+// 跳过空白后的下一个字节，没有时返回0
+func nextByte(line string, i int) byte {
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	if i < len(line) {
+		return line[i]
+	}
+	return 0
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// 是否为标识符的开始
+func isIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || unicode.IsLetter(r)
+}
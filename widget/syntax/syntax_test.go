@@ -0,0 +1,114 @@
+package syntax
+
+import (
+	"reflect"
+	"testing"
+)
+
+var kindNames = map[Kind]string{
+	Plain: "plain", Keyword: "keyword", Type: "type", String: "string", Number: "number", Comment: "comment",
+	Operator: "op", Punctuation: "punct", Literal: "literal", Property: "prop", Function: "func",
+}
+
+// 把记号转换为“类型:文字”的形式，便于比较
+func describe(line string, tokens []Token) []string {
+	var out []string
+	for _, t := range tokens {
+		out = append(out, kindNames[t.Kind]+":"+line[t.Start:t.End])
+	}
+	return out
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer Tokenizer
+		line      string
+		want      []string
+	}{
+		{"Go关键字和类型", Go(), "var x int = 1", []string{"keyword:var", "type:int", "op:=", "number:1"}},
+		{"Go函数调用", Go(), "fmt.Println(nil)", []string{"punct:.", "func:Println", "punct:(", "literal:nil", "punct:)"}},
+		{"Go单行注释", Go(), "x := 1 // 注释", []string{"op::=", "number:1", "comment:// 注释"}},
+		{"Go行内的多行注释", Go(), "a /* b */ c", []string{"comment:/* b */"}},
+		{"Go字符串中的转义", Go(), `s := "a\"b" + 'c'`, []string{"op::=", `string:"a\"b"`, "op:+", "string:'c'"}},
+		{"Go字符串中的注释符号", Go(), `"//"`, []string{`string:"//"`}},
+		{"Go数字", Go(), "1.5e-3 0x1F .5", []string{"number:1.5e-3", "number:0x1F", "number:.5"}},
+		{"Go减号作为运算符", Go(), "a-1", []string{"op:-", "number:1"}},
+		{"Go负数", Go(), "f(-1)", []string{"func:f", "punct:(", "number:-1", "punct:)"}},
+		{"Go字段访问不是数字", Go(), "a.b", []string{"punct:."}},
+		{"Go运算符不包含注释", Go(), "a =// c", []string{"op:=", "comment:// c"}},
+		{"JSON属性名和值", JSON(), `{"a": "b", "c" : true}`,
+			[]string{"punct:{", `prop:"a"`, "punct::", `string:"b"`, "punct:,", `prop:"c"`, "punct::", "literal:true", "punct:}"}},
+		{"JSON负数", JSON(), `[-1, 2.5e+3, null]`,
+			[]string{"punct:[", "number:-1", "punct:,", "number:2.5e+3", "punct:,", "literal:null", "punct:]"}},
+		{"SQL关键字忽略大小写", SQL(), "SELECT id FROM t WHERE x = 'a'",
+			[]string{"keyword:SELECT", "keyword:FROM", "keyword:WHERE", "op:=", "string:'a'"}},
+		{"SQL类型和注释", SQL(), "id Integer -- 主键", []string{"type:Integer", "comment:-- 主键"}},
+		{"SQL函数", SQL(), "count(*)", []string{"func:count", "punct:(", "op:*", "punct:)"}},
+		{"非ASCII标识符", Go(), "变量 := 函数()", []string{"op::=", "func:函数", "punct:(", "punct:)"}},
+	}
+	for _, tt := range tests {
+		tokens, state := tt.tokenizer.Tokenize(tt.line, 0)
+		if got := describe(tt.line, tokens); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+		if state != 0 {
+			t.Errorf("%s: state %d, want 0", tt.name, state)
+		}
+	}
+}
+
+// 多行注释和多行字符串的状态在行之间延续
+func TestTokenizeState(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer Tokenizer
+		lines     []string
+		want      [][]string
+		states    []State
+	}{
+		{"多行注释", Go(), []string{"x /* a", "b", "c */ y()"},
+			[][]string{{"comment:/* a"}, {"comment:b"}, {"comment:c */", "func:y", "punct:(", "punct:)"}},
+			[]State{stateComment, stateComment, 0}},
+		{"注释结束后开始新的注释", Go(), []string{"/*", "*/ /* x"},
+			[][]string{{"comment:/*"}, {"comment:*/", "comment:/* x"}},
+			[]State{stateComment, stateComment}},
+		{"多行原始字符串", Go(), []string{"s := `a", `b\`, "c` + 1"},
+			[][]string{{"op::=", "string:`a"}, {`string:b\`}, {"string:c`", "op:+", "number:1"}},
+			[]State{stateString, stateString, 0}},
+		{"未结束的普通字符串不延续", Go(), []string{`"a`, "b"},
+			[][]string{{`string:"a`}, nil},
+			[]State{0, 0}},
+		{"空行保持状态", SQL(), []string{"/* a", "", "*/"},
+			[][]string{{"comment:/* a"}, {"comment:"}, {"comment:*/"}},
+			[]State{stateComment, stateComment, 0}},
+		{"JSON没有多行结构", JSON(), []string{`"a`, `b"`},
+			[][]string{{`string:"a`}, {`string:"`}},
+			[]State{0, 0}},
+	}
+	for _, tt := range tests {
+		var state State
+		for i, line := range tt.lines {
+			var tokens []Token
+			tokens, state = tt.tokenizer.Tokenize(line, state)
+			if got := describe(line, tokens); !reflect.DeepEqual(got, tt.want[i]) {
+				t.Errorf("%s: line %d: %q, want %q", tt.name, i, got, tt.want[i])
+			}
+			if state != tt.states[i] {
+				t.Errorf("%s: line %d: state %d, want %d", tt.name, i, state, tt.states[i])
+			}
+		}
+	}
+}
+
+func TestColorsOf(t *testing.T) {
+	colors := Dark()
+	colors.Plain.A = 0xff
+	if got := colors.Of(Keyword); got != colors.Keyword {
+		t.Errorf("Of(Keyword) = %v, want %v", got, colors.Keyword)
+	}
+	// 没有设置颜色的类型使用普通文本的颜色
+	if got := colors.Of(Punctuation); got != colors.Plain {
+		t.Errorf("Of(Punctuation) = %v, want %v", got, colors.Plain)
+	}
+}
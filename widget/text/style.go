@@ -0,0 +1,34 @@
+package text
+
+// Decoration 定义了文本的装饰线，可以使用按位或组合多种装饰线
+type Decoration uint8
+
+const (
+	// DecorationNone 表示没有装饰线
+	DecorationNone Decoration = 0
+	// Underline 表示下划线
+	Underline Decoration = 1 << (iota - 1)
+	// Strikethrough 表示删除线
+	Strikethrough
+)
+
+// Style 定义了文本的样式
+//
+// 所有文本组件都支持字体族、粗细和斜体，字间距和装饰线只有自行绘制文本的组件支持（Label和Button）
+type Style struct {
+	// Family 是字体族名，为空时使用默认字体族
+	Family string
+	// Weight 是字体粗细
+	Weight Weight
+	// Italic 表示是否使用斜体
+	Italic bool
+	// LetterSpacing 是字符之间额外的间距，单位为sp
+	LetterSpacing float32
+	// Decoration 是文本的装饰线
+	Decoration Decoration
+}
+
+// Has 判断是否包含指定的装饰线
+func (d Decoration) Has(decoration Decoration) bool {
+	return d&decoration != 0
+}
@@ -0,0 +1,185 @@
+package widget
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 文本查找，记录查找条件和所有匹配的位置
+type textFinder struct {
+	// 查找的文字
+	query string
+	// 是否区分大小写
+	caseSensitive bool
+	// 是否只匹配完整的单词
+	wholeWord bool
+	// 是否把查找的文字作为正则表达式
+	regex bool
+	// 编译好的正则表达式
+	re *regexp.Regexp
+	// 正则表达式的错误
+	err error
+	// 查找时的文本，文本改变时需要重新查找
+	text string
+	// 是否需要重新查找
+	dirty bool
+	// 所有匹配的字符范围
+	matches [][2]int
+	// 使用正则表达式时每个匹配的分组位置，以字节计数，用于展开替换文字中的$1
+	groups [][]int
+}
+
+// 设置查找的文字
+func (f *textFinder) setQuery(query string) {
+	f.query = query
+	f.dirty = true
+}
+
+// 设置是否区分大小写
+func (f *textFinder) setCaseSensitive(caseSensitive bool) {
+	f.caseSensitive = caseSensitive
+	f.dirty = true
+}
+
+// 设置是否只匹配完整的单词
+func (f *textFinder) setWholeWord(wholeWord bool) {
+	f.wholeWord = wholeWord
+	f.dirty = true
+}
+
+// 设置是否使用正则表达式
+func (f *textFinder) setRegex(regex bool) {
+	f.regex = regex
+	f.dirty = true
+}
+
+// 在文本中查找，文本和查找条件都没有改变时不会重新查找
+func (f *textFinder) search(text string) [][2]int {
+	if !f.dirty && f.text == text {
+		return f.matches
+	}
+	f.dirty = false
+	f.text = text
+	f.matches = f.matches[:0]
+	f.groups = f.groups[:0]
+	f.re, f.err = nil, nil
+	if f.query == "" {
+		return f.matches
+	}
+	if f.regex {
+		f.searchRegex(text)
+		return f.matches
+	}
+	query := []rune(f.query)
+	runes := []rune(text)
+	for i := 0; i+len(query) <= len(runes); i++ {
+		if f.equal(runes[i:i+len(query)], query) && f.isWord(runes, i, i+len(query)) {
+			f.matches = append(f.matches, [2]int{i, i + len(query)})
+			i += len(query) - 1
+		}
+	}
+	return f.matches
+}
+
+// 使用正则表达式查找，忽略空匹配
+func (f *textFinder) searchRegex(text string) {
+	expr := f.query
+	if !f.caseSensitive {
+		expr = "(?i)" + expr
+	}
+	f.re, f.err = regexp.Compile(expr)
+	if f.err != nil {
+		return
+	}
+	var runes []rune
+	if f.wholeWord {
+		runes = []rune(text)
+	}
+	// 匹配按顺序返回，增量地把字节位置转换为字符位置
+	bytePos, runePos := 0, 0
+	toRune := func(b int) int {
+		runePos += utf8.RuneCountInString(text[bytePos:b])
+		bytePos = b
+		return runePos
+	}
+	for _, loc := range f.re.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		m := [2]int{toRune(loc[0]), toRune(loc[1])}
+		if f.wholeWord && !f.isWord(runes, m[0], m[1]) {
+			continue
+		}
+		f.matches = append(f.matches, m)
+		f.groups = append(f.groups, loc)
+	}
+}
+
+// 比较两段文字
+func (f *textFinder) equal(a, b []rune) bool {
+	for i := range a {
+		if a[i] == b[i] {
+			continue
+		}
+		if f.caseSensitive || unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// 只匹配完整的单词时，检查匹配的前后是否为单词的边界
+func (f *textFinder) isWord(runes []rune, start, end int) bool {
+	if !f.wholeWord {
+		return true
+	}
+	return (start == 0 || !isWordRune(runes[start-1])) && (end == len(runes) || !isWordRune(runes[end]))
+}
+
+// 获取替换第i个匹配的文字，使用正则表达式时展开$1等分组引用
+func (f *textFinder) replacement(i int, replacement string) string {
+	if f.re == nil || i >= len(f.groups) {
+		return replacement
+	}
+	return string(f.re.ExpandString(nil, replacement, f.text, f.groups[i]))
+}
+
+// 查找caret之后的第一个匹配，没有时从头开始，forward为false时向前查找
+func (f *textFinder) next(caret int, forward bool) int {
+	if len(f.matches) == 0 {
+		return -1
+	}
+	if forward {
+		for i, m := range f.matches {
+			if m[0] >= caret {
+				return i
+			}
+		}
+		return 0
+	}
+	for i := len(f.matches) - 1; i >= 0; i-- {
+		if f.matches[i][1] <= caret {
+			return i
+		}
+	}
+	return len(f.matches) - 1
+}
+
+// 返回与选择区域完全相同的匹配，没有时返回-1
+func (f *textFinder) at(start, end int) int {
+	if start > end {
+		start, end = end, start
+	}
+	for i, m := range f.matches {
+		if m[0] == start && m[1] == end {
+			return i
+		}
+	}
+	return -1
+}
+
+// 是否为单词中的字符
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,123 @@
+package widget
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextFinderSearch(t *testing.T) {
+	tests := []struct {
+		name                            string
+		text, query                     string
+		caseSensitive, wholeWord, regex bool
+		want                            [][2]int
+	}{
+		{"空查找没有匹配", "abc", "", false, false, false, nil},
+		{"默认不区分大小写", "Go go GO", "go", false, false, false, [][2]int{{0, 2}, {3, 5}, {6, 8}}},
+		{"区分大小写", "Go go GO", "go", true, false, false, [][2]int{{3, 5}}},
+		{"匹配不重叠", "aaaa", "aa", false, false, false, [][2]int{{0, 2}, {2, 4}}},
+		{"按字符计算位置", "你好，你好", "你好", false, false, false, [][2]int{{0, 2}, {3, 5}}},
+		{"完整单词", "cat concat cat_ cat.", "cat", false, true, false, [][2]int{{0, 3}, {16, 19}}},
+		{"完整单词不把汉字作为边界", "猫cat 猫", "cat", false, true, false, nil},
+		{"正则表达式", "a1 b22 c333", `\d+`, false, false, true, [][2]int{{1, 2}, {4, 6}, {8, 11}}},
+		{"正则表达式不区分大小写", "Abc abc", "a.c", false, false, true, [][2]int{{0, 3}, {4, 7}}},
+		{"正则表达式区分大小写", "Abc abc", "a.c", true, false, true, [][2]int{{4, 7}}},
+		{"正则表达式忽略空匹配", "ab", "x*", false, false, true, nil},
+		{"正则表达式按字符计算位置", "你好 world", "wor", false, false, true, [][2]int{{3, 6}}},
+		{"正则表达式和完整单词", "foo foobar foo", "fo+", false, true, true, [][2]int{{0, 3}, {11, 14}}},
+		{"错误的正则表达式", "abc", "(", false, false, true, nil},
+	}
+	for _, tt := range tests {
+		var f textFinder
+		f.setQuery(tt.query)
+		f.setCaseSensitive(tt.caseSensitive)
+		f.setWholeWord(tt.wholeWord)
+		f.setRegex(tt.regex)
+		got := f.search(tt.text)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// 正则表达式错误时记录错误，修改查找条件后重新查找
+func TestTextFinderError(t *testing.T) {
+	var f textFinder
+	f.setRegex(true)
+	f.setQuery("(")
+	f.search("(a)")
+	if f.err == nil {
+		t.Fatal("expected regexp error")
+	}
+	f.setRegex(false)
+	if got := f.search("(a)"); f.err != nil || !reflect.DeepEqual(got, [][2]int{{0, 1}}) {
+		t.Errorf("%v %v, want [[0 1]] <nil>", got, f.err)
+	}
+}
+
+func TestTextFinderReplacement(t *testing.T) {
+	tests := []struct {
+		name        string
+		text, query string
+		regex       bool
+		replacement string
+		want        []string
+	}{
+		{"普通查找不展开", "a1 b2", "a1", false, "$1", []string{"$1"}},
+		{"展开编号分组", "a1 b2", `([a-z])(\d)`, true, "$2$1", []string{"1a", "2b"}},
+		{"展开命名分组", "key=value", `(?P<k>\w+)=(?P<v>\w+)`, true, "${v}=${k}", []string{"value=key"}},
+		{"多字节字符", "你好 世界", `(\S)(\S)`, true, "$2$1", []string{"好你", "界世"}},
+		{"$$表示$", "a", "a", true, "$$", []string{"$"}},
+	}
+	for _, tt := range tests {
+		var f textFinder
+		f.setQuery(tt.query)
+		f.setRegex(tt.regex)
+		matches := f.search(tt.text)
+		var got []string
+		for i := range matches {
+			got = append(got, f.replacement(i, tt.replacement))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTextFinderNext(t *testing.T) {
+	var f textFinder
+	f.setQuery("ab")
+	f.search("ab ab ab")
+	tests := []struct {
+		caret   int
+		forward bool
+		want    int
+	}{
+		{0, true, 0},
+		{1, true, 1},
+		{3, true, 1},
+		{7, true, 0},
+		{8, false, 2},
+		{5, false, 1},
+		{4, false, 0},
+		{1, false, 2},
+	}
+	for _, tt := range tests {
+		if got := f.next(tt.caret, tt.forward); got != tt.want {
+			t.Errorf("next(%d, %v) = %d, want %d", tt.caret, tt.forward, got, tt.want)
+		}
+	}
+	if got := f.at(5, 3); got != 1 {
+		t.Errorf("at(5, 3) = %d, want 1", got)
+	}
+	if got := f.at(3, 4); got != -1 {
+		t.Errorf("at(3, 4) = %d, want -1", got)
+	}
+	var empty textFinder
+	if got := empty.next(0, true); got != -1 {
+		t.Errorf("empty next = %d, want -1", got)
+	}
+}
@@ -1,14 +1,165 @@
 package theme
 
 import (
+	"image/color"
+	"sync"
+
 	gtext "github.com/Seikaijyu/gio/text"
+	gunit "github.com/Seikaijyu/gio/unit"
 	"github.com/Seikaijyu/gio/widget/material"
 )
 
 var Shaper *gtext.Shaper = &gtext.Shaper{}
 
+// 调色板
+type Palette struct {
+	// 主色，用于按钮、选中标记、滑块等需要强调的组件
+	Primary color.NRGBA
+	// 绘制在主色上的文字颜色
+	OnPrimary color.NRGBA
+	// 窗口背景颜色
+	Background color.NRGBA
+	// 表面颜色，用于开关关闭时的滑块等绘制在背景上的区域
+	Surface color.NRGBA
+	// 错误颜色，用于验证错误信息等
+	Error color.NRGBA
+	// 文字颜色
+	Text color.NRGBA
+	// 次要文字颜色，用于提示文字
+	TextSecondary color.NRGBA
+}
+
+// 字号等级，单位为sp
+type Typography struct {
+	// 说明文字，例如验证错误信息
+	Caption float32
+	// 正文，例如按钮和编辑框
+	Body float32
+	// 副标题，标签默认使用此字号
+	Subtitle float32
+	// 标题
+	Title float32
+	// 大标题
+	Heading float32
+}
+
+// 应用程序主题
+type Theme struct {
+	Palette
+	Typography
+	// 圆角，单位为dp
+	CornerRadius float32
+	// 间距单位，单位为dp
+	Spacing float32
+}
+
+// 获取指定倍数的间距
+func (t *Theme) Space(n float32) float32 {
+	return t.Spacing * n
+}
+
+// 复制主题，用于在内置主题的基础上修改
+func (t *Theme) Clone() *Theme {
+	clone := *t
+	return &clone
+}
+
+// 亮色主题
+func Light() *Theme {
+	return &Theme{
+		Palette: Palette{
+			Primary:       color.NRGBA{R: 0x3f, G: 0x51, B: 0xb5, A: 0xff},
+			OnPrimary:     color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			Background:    color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			Surface:       color.NRGBA{R: 0xf5, G: 0xf5, B: 0xf5, A: 0xff},
+			Error:         color.NRGBA{R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff},
+			Text:          color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+			TextSecondary: color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xbb},
+		},
+		Typography:   Typography{Caption: 12, Body: 16, Subtitle: 18, Title: 20, Heading: 24},
+		CornerRadius: 4,
+		Spacing:      4,
+	}
+}
+
+// 暗色主题
+func Dark() *Theme {
+	return &Theme{
+		Palette: Palette{
+			Primary:       color.NRGBA{R: 0x8c, G: 0x9e, B: 0xff, A: 0xff},
+			OnPrimary:     color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+			Background:    color.NRGBA{R: 0x12, G: 0x12, B: 0x12, A: 0xff},
+			Surface:       color.NRGBA{R: 0x2c, G: 0x2c, B: 0x2c, A: 0xff},
+			Error:         color.NRGBA{R: 0xcf, G: 0x66, B: 0x79, A: 0xff},
+			Text:          color.NRGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff},
+			TextSecondary: color.NRGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0x99},
+		},
+		Typography:   Typography{Caption: 12, Body: 16, Subtitle: 18, Title: 20, Heading: 24},
+		CornerRadius: 4,
+		Spacing:      4,
+	}
+}
+
+var (
+	mutex sync.RWMutex
+	// 当前主题
+	current = Light()
+	// 主题版本，每次切换主题时增加，组件通过版本判断是否需要重新应用主题
+	version int
+	// 所有组件共享的gio主题
+	shared = newMaterial(current)
+)
+
+// 获取当前主题
+func Current() *Theme {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// 获取当前主题版本
+func Version() int {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return version
+}
+
+// 切换当前主题，所有组件会在下一次渲染时应用新主题
+func Set(t *Theme) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = t
+	version++
+	palette := newMaterial(t)
+	shared.Palette = palette.Palette
+	shared.TextSize = palette.TextSize
+}
+
+// 获取所有组件共享的gio主题
+func Material() *material.Theme {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return shared
+}
+
+// 根据当前主题创建一个新的gio主题
 func NewTheme() *material.Theme {
+	return newMaterial(Current())
+}
+
+// 根据主题创建gio主题
+func newMaterial(t *Theme) *material.Theme {
 	theme := material.NewTheme()
 	theme.Shaper = Shaper
+	theme.Palette = material.Palette{
+		Bg:         t.Background,
+		Fg:         t.Text,
+		ContrastBg: t.Primary,
+		ContrastFg: t.OnPrimary,
+	}
+	theme.TextSize = 16
+	if t.Body > 0 {
+		theme.TextSize = gunit.Sp(t.Body)
+	}
 	return theme
 }
@@ -0,0 +1,118 @@
+package widget
+
+import (
+	"image/color"
+
+	"github.com/Seikaijyu/nenki.ui/widget/theme"
+)
+
+// 记录手动设置过的属性，这些属性在切换主题时不会被覆盖
+type overrides uint32
+
+const (
+	// 文字颜色
+	overrideFontColor overrides = 1 << iota
+	// 背景颜色
+	overrideBackground
+	// 字体大小
+	overrideFontSize
+	// 圆角
+	overrideCornerRadius
+	// 提示文字颜色
+	overrideHintColor
+	// 选中区域颜色
+	overrideSelectionColor
+	// 标记颜色，例如复选框和单选框的标记、滑块和开关启用时的颜色
+	overrideMarkColor
+	// 轨道颜色
+	overrideTrackColor
+	// 禁用颜色
+	overrideDisabledColor
+	// 错误颜色
+	overrideErrorColor
+	// 边框颜色
+	overrideBorderColor
+	// 鼠标悬浮颜色
+	overrideHoverColor
+	// 链接颜色
+	overrideLinkColor
+	// 行号颜色
+	overrideLineNumberColor
+	// 当前行高亮颜色
+	overrideLineColor
+	// 查找结果高亮颜色
+	overrideMatchColor
+	// 语法高亮配色
+	overrideSyntaxColors
+)
+
+// 标记属性已经手动设置
+func (p *overrides) set(o overrides) {
+	*p |= o
+}
+
+// 属性是否手动设置过
+func (p overrides) has(o overrides) bool {
+	return p&o != 0
+}
+
+// 组件的主题状态
+type themed struct {
+	// 已经应用的主题版本
+	version int
+	// 手动设置过的属性
+	overrides overrides
+}
+
+// 主题是否已经改变，改变时记录新的版本并返回当前主题
+func (p *themed) changed() (*theme.Theme, bool) {
+	version := theme.Version()
+	if p.version == version {
+		return nil, false
+	}
+	p.version = version
+	return theme.Current(), true
+}
+
+// 跟随主题的组件
+type themedWidget interface {
+	// 获取组件的主题状态
+	themeState() *themed
+}
+
+// 获取组件手动设置过的属性，用于样式表在设置属性前后记录状态，不跟随主题的组件返回0
+func ThemeOverrides(w WidgetInterface) uint32 {
+	if t, ok := w.(themedWidget); ok {
+		return uint32(t.themeState().overrides)
+	}
+	return 0
+}
+
+// 清除组件手动设置过的属性，被清除的属性会在下一次渲染时重新使用当前主题的值
+//
+// 用于样式表恢复属性后让属性重新跟随主题
+func ResetThemeOverrides(w WidgetInterface, mask uint32) {
+	t, ok := w.(themedWidget)
+	if !ok || mask == 0 {
+		return
+	}
+	state := t.themeState()
+	state.overrides &^= overrides(mask)
+	state.version = -1
+}
+
+// 创建组件时的主题状态，版本为-1以保证第一次渲染时应用主题
+func newThemed() themed {
+	return themed{version: -1}
+}
+
+// 颜色的四个分量，顺序与颜色设置函数的参数相同
+func rgba(c color.NRGBA) (r, g, b, a uint8) {
+	return c.R, c.G, c.B, c.A
+}
+
+// 修改颜色的透明度
+func withAlpha(c color.NRGBA, a uint8) color.NRGBA {
+	c.A = uint8(uint32(c.A) * uint32(a) / 0xff)
+	return c
+}
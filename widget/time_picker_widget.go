@@ -0,0 +1,322 @@
+package widget
+
+import (
+	"image/color"
+	"time"
+
+	glayout "github.com/Seikaijyu/gio/layout"
+	gunit "github.com/Seikaijyu/gio/unit"
+)
+
+// 校验接口是否实现
+var _ WidgetInterface = &TimePicker{}
+
+type timePickerConfig struct {
+	// 主题状态
+	themed themed
+	// 是否禁用
+	disabled bool
+	// 是否更新组件
+	update bool
+	// 删除事件
+	_destroy func()
+	// 值改变事件
+	_changed func(*TimePicker, time.Time)
+	// 输入框显示的格式，为空时根据12/24小时制和是否显示秒决定
+	format string
+}
+
+// 时间选择器，点击后弹出时、分、秒的列表，支持12小时制和24小时制
+type TimePicker struct {
+	// 组件标识
+	identity
+	// 配置
+	config *timePickerConfig
+	// 外边距
+	margin *glayout.Inset
+	// 选中的时间，零值表示没有选中
+	value time.Time
+	// 输入框和弹出层
+	field pickerField
+	// 时间列表
+	clock clockView
+}
+
+// 绑定函数
+func (p *TimePicker) Then(fn func(self *TimePicker)) *TimePicker {
+	fn(p)
+	return p
+}
+
+// 设置组件ID，用于在组件树中查找
+func (p *TimePicker) ID(id string) *TimePicker {
+	p.id = id
+	return p
+}
+
+// 添加组件类名
+func (p *TimePicker) Class(classes ...string) *TimePicker {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// 获取主题状态，用于样式表恢复属性后重新跟随主题
+func (p *TimePicker) themeState() *themed {
+	return &p.config.themed
+}
+
+// 注销自身，清理所有引用
+func (p *TimePicker) Destroy() {
+	p.config.update = false
+	p.field.open = false
+	if p.config._destroy != nil {
+		p.config._destroy()
+	}
+	p.config._destroy = nil
+}
+
+// 注册删除事件
+func (p *TimePicker) OnDestroy(fn func()) {
+	p.config._destroy = fn
+}
+
+// 注册值改变事件，每次在列表中选择时、分、秒或上下午后触发
+//
+// 没有设置过时间时，日期部分为选择时的当天
+func (p *TimePicker) OnChanged(fn func(p *TimePicker, value time.Time)) *TimePicker {
+	p.config._changed = fn
+	return p
+}
+
+// 是否更新组件
+func (p *TimePicker) Update(update bool) {
+	p.config.update = update
+}
+
+// 是否禁用组件，禁用时不响应任何输入并关闭列表
+func (p *TimePicker) Disabled(disabled bool) *TimePicker {
+	p.config.disabled = disabled
+	return p
+}
+
+// 获取组件当前的交互状态
+func (p *TimePicker) GetState() State {
+	return stateOf(p.field.click.Hovered(), p.field.focused, p.config.disabled, false)
+}
+
+// 外边距
+func (p *TimePicker) Margin(Top, Left, Bottom, Right float32) *TimePicker {
+	p.margin.Top = gunit.Dp(Top)
+	p.margin.Left = gunit.Dp(Left)
+	p.margin.Bottom = gunit.Dp(Bottom)
+	p.margin.Right = gunit.Dp(Right)
+	return p
+}
+
+// 获取外边距
+func (p *TimePicker) GetMargin() (top, left, bottom, right float32) {
+	return insetValues(p.margin)
+}
+
+// 设置选中的时间，零值表示清除选择，不会触发事件
+func (p *TimePicker) Value(value time.Time) *TimePicker {
+	p.value = value
+	p.clock.scroll = true
+	return p
+}
+
+// 获取选中的时间，没有选中时返回零值
+func (p *TimePicker) GetValue() time.Time {
+	return p.value
+}
+
+// 是否使用12小时制，默认使用24小时制
+func (p *TimePicker) Hour12(hour12 bool) *TimePicker {
+	p.clock.hour12 = hour12
+	p.clock.scroll = true
+	return p
+}
+
+// 是否可以选择秒，默认只选择时和分
+func (p *TimePicker) ShowSeconds(show bool) *TimePicker {
+	p.clock.seconds = show
+	p.clock.scroll = true
+	return p
+}
+
+// 指定上下午名称使用的语言，例如zh、en-US，为空时使用系统语言
+func (p *TimePicker) Locale(language string) *TimePicker {
+	p.field.language = language
+	return p
+}
+
+// 输入框显示时间的格式，使用time包的格式，为空时根据12/24小时制和是否显示秒决定
+func (p *TimePicker) Format(layout string) *TimePicker {
+	p.config.format = layout
+	return p
+}
+
+// 没有选中时间时显示的提示文字
+func (p *TimePicker) Hint(hint string) *TimePicker {
+	p.field.hint = hint
+	return p
+}
+
+// 字体大小
+func (p *TimePicker) FontSize(size float32) *TimePicker {
+	p.field.textSize = size
+	p.config.themed.overrides.set(overrideFontSize)
+	return p
+}
+
+// 获取字体大小
+func (p *TimePicker) GetFontSize() float32 {
+	return p.field.textSize
+}
+
+// 字体族
+func (p *TimePicker) FontFamily(name string) *TimePicker {
+	p.field.typeface.set(name)
+	return p
+}
+
+// 文字颜色
+func (p *TimePicker) FontColor(r, g, b, a uint8) *TimePicker {
+	p.field.fontColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideFontColor)
+	return p
+}
+
+// 获取文字颜色
+func (p *TimePicker) GetFontColor() (r, g, b, a uint8) {
+	return rgba(p.field.fontColor)
+}
+
+// 输入框和列表的背景颜色
+func (p *TimePicker) Background(r, g, b, a uint8) *TimePicker {
+	p.field.background = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBackground)
+	return p
+}
+
+// 获取背景颜色
+func (p *TimePicker) GetBackground() (r, g, b, a uint8) {
+	return rgba(p.field.background)
+}
+
+// 输入框的边框颜色
+func (p *TimePicker) BorderColor(r, g, b, a uint8) *TimePicker {
+	p.field.borderColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideBorderColor)
+	return p
+}
+
+// 选中项和焦点边框的颜色
+func (p *TimePicker) Color(r, g, b, a uint8) *TimePicker {
+	p.field.markColor = color.NRGBA{R: r, G: g, B: b, A: a}
+	p.config.themed.overrides.set(overrideMarkColor)
+	return p
+}
+
+// 圆角
+func (p *TimePicker) CornerRadius(radius float32) *TimePicker {
+	p.field.cornerRadius = radius
+	p.config.themed.overrides.set(overrideCornerRadius)
+	return p
+}
+
+// 获取圆角
+func (p *TimePicker) GetCornerRadius() float32 {
+	return p.field.cornerRadius
+}
+
+// 打开时间列表
+func (p *TimePicker) Open() *TimePicker {
+	if !p.field.open {
+		p.field.open = true
+		p.clock.scroll = true
+	}
+	return p
+}
+
+// 关闭时间列表
+func (p *TimePicker) Close() *TimePicker {
+	p.field.open = false
+	return p
+}
+
+// 时间列表是否打开
+func (p *TimePicker) IsOpen() bool {
+	return p.field.open
+}
+
+// 输入框显示的文字
+func (p *TimePicker) text(names *dateNames) string {
+	switch {
+	case p.value.IsZero():
+		return ""
+	case p.config.format != "":
+		return p.value.Format(p.config.format)
+	}
+	return formatClock(p.value, p.clock.hour12, p.clock.seconds, names)
+}
+
+// 渲染组件
+func (p *TimePicker) Layout(gtx glayout.Context) glayout.Dimensions {
+	if !p.config.update {
+		return glayout.Dimensions{}
+	}
+	// 禁用时移除输入事件
+	if p.config.disabled {
+		gtx = gtx.Disabled()
+	}
+	p.applyTheme()
+	if p.field.update(gtx) {
+		p.clock.scroll = true
+	}
+	if p.field.open {
+		if value, ok := p.clock.update(gtx, pickerBase(p.value, gtx.Now)); ok {
+			p.value = value
+			if p.config._changed != nil {
+				p.config._changed(p, value)
+			}
+		}
+	}
+	names := p.field.names(gtx)
+	return p.margin.Layout(gtx, func(gtx glayout.Context) glayout.Dimensions {
+		return p.field.layout(gtx, p.text(names), func(gtx glayout.Context) glayout.Dimensions {
+			return p.clock.layout(gtx, &p.field, names, p.value)
+		})
+	})
+}
+
+// 应用当前主题，手动设置过的属性不会被覆盖
+func (p *TimePicker) applyTheme() {
+	th, ok := p.config.themed.changed()
+	if !ok {
+		return
+	}
+	p.field.applyTheme(th, p.config.themed.overrides)
+}
+
+// 创建时间选择器，默认没有选中时间，使用24小时制
+func NewTimePicker() *TimePicker {
+	return &TimePicker{
+		field:  newPickerField(),
+		clock:  newClockView(),
+		margin: &glayout.Inset{},
+		config: &timePickerConfig{update: true, themed: newThemed()},
+	}
+}
+
+// 修改时间时使用的基准，没有值时使用当天的0点
+func pickerBase(value, now time.Time) time.Time {
+	if !value.IsZero() {
+		return value
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	year, month, day := now.In(time.Local).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
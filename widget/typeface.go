@@ -0,0 +1,51 @@
+package widget
+
+import (
+	gfont "github.com/Seikaijyu/gio/font"
+	"github.com/Seikaijyu/nenki.ui/widget/font"
+)
+
+// 组件的字体族，根据字体注册表和文本内容解析出字体回退链
+type typeface struct {
+	// 字体族名，为空时使用默认字体族
+	family string
+	// 上次解析时的注册表版本
+	version int
+	// 上次解析时的文本
+	text string
+	// 解析结果
+	face gfont.Typeface
+}
+
+// 创建组件字体族
+func newTypeface() typeface {
+	return typeface{version: -1}
+}
+
+// 设置字体族名
+func (p *typeface) set(family string) {
+	p.family = family
+	p.version = -1
+}
+
+// 解析字体回退链，只有注册表版本或文本改变时才会重新解析
+func (p *typeface) resolve(text string) gfont.Typeface {
+	version := font.Version()
+	if p.version == version && p.text == text {
+		return p.face
+	}
+	p.version = version
+	p.text = text
+	p.face = font.Resolve(p.family, text)
+	return p.face
+}
+
+// 标记需要重新解析，用于文本内容获取代价较高的组件
+func (p *typeface) invalidate() {
+	p.version = -1
+}
+
+// 是否需要重新解析
+func (p *typeface) stale() bool {
+	return p.version != font.Version()
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 验证器，传入文本内容，验证失败时返回错误信息
+type Validator = func(text string) error
+
+// 不能为空
+func Required() Validator {
+	return func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return errors.New("内容不能为空")
+		}
+		return nil
+	}
+}
+
+// 整数，并且在[min, max]范围内
+func Int(min, max int64) Validator {
+	return func(text string) error {
+		value, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return errors.New("请输入有效的整数")
+		}
+		if value < min || value > max {
+			return fmt.Errorf("请输入%d到%d之间的整数", min, max)
+		}
+		return nil
+	}
+}
+
+// 浮点数，并且在[min, max]范围内
+func Float(min, max float64) Validator {
+	return func(text string) error {
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return errors.New("请输入有效的数字")
+		}
+		if value < min || value > max {
+			return fmt.Errorf("请输入%g到%g之间的数字", min, max)
+		}
+		return nil
+	}
+}
+
+// 文本长度（按字符计算）在[min, max]范围内，max小于等于0时不限制最大长度
+func Length(min, max int) Validator {
+	return func(text string) error {
+		length := utf8.RuneCountInString(text)
+		if length < min {
+			return fmt.Errorf("长度不能少于%d个字符", min)
+		}
+		if max > 0 && length > max {
+			return fmt.Errorf("长度不能超过%d个字符", max)
+		}
+		return nil
+	}
+}
+
+// 正则表达式匹配，匹配失败时返回message作为错误信息
+//
+// 如果正则表达式无效则会panic
+func Regex(pattern, message string) Validator {
+	re := regexp.MustCompile(pattern)
+	return func(text string) error {
+		if !re.MatchString(text) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// 电子邮件地址
+func Email() Validator {
+	return func(text string) error {
+		address, err := mail.ParseAddress(text)
+		// 只允许纯地址，不允许"名字 <地址>"的形式
+		if err != nil || address.Address != text || !strings.Contains(text[strings.LastIndex(text, "@")+1:], ".") {
+			return errors.New("请输入有效的电子邮件地址")
+		}
+		return nil
+	}
+}
+
+// URL地址，需要包含协议和主机名
+func URL() Validator {
+	return func(text string) error {
+		u, err := url.ParseRequestURI(text)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("请输入有效的URL地址")
+		}
+		return nil
+	}
+}
@@ -17,6 +17,20 @@ type WidgetInterface interface {
 	Update(update bool)
 }
 
+// 组件树节点接口，提供组件ID、类名和父节点，用于组件查询和样式表
+//
+// 内置组件都实现了该接口，外部实现的组件可以不实现，不实现时没有ID和类名，也不记录父节点
+type NodeInterface interface {
+	// 获取组件ID
+	GetID() string
+	// 获取组件类名
+	GetClasses() []string
+	// 获取父节点
+	GetParent() WidgetInterface
+	// 设置父节点，由父节点的ResetParent调用
+	SetParent(parent WidgetInterface)
+}
+
 // 多子节点布局接口
 type MultiChildLayoutInterface[T any] interface {
 	WidgetInterface
@@ -52,3 +66,11 @@ type SingleChildLayoutInterface[T any] interface {
 	// 删除子节点
 	RemoveChild() T
 }
+
+// 外边距的四个值，顺序与Margin的参数相同，没有外边距时都为0
+func insetValues(inset *glayout.Inset) (top, left, bottom, right float32) {
+	if inset == nil {
+		return 0, 0, 0, 0
+	}
+	return float32(inset.Top), float32(inset.Left), float32(inset.Bottom), float32(inset.Right)
+}